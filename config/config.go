@@ -11,10 +11,16 @@ import (
 
 // ConfigList contains application information.
 type ConfigList struct {
+	Auth struct {
+		RequireVerification bool `mapstructure:"require_verification"`
+	}
 	AWS struct {
 		Bucket string
 		Region string
 	}
+	Demo struct {
+		UserIDs []uint `mapstructure:"user_ids"`
+	}
 	Database struct {
 		User     string
 		Name     string
@@ -23,10 +29,30 @@ type ConfigList struct {
 		Driver   string
 		Log      bool
 	}
+	Token struct {
+		Signed bool
+		KeyID  string `mapstructure:"key_id"`
+		Keys   map[string]string
+	}
 	Web struct {
 		Port   int
 		Origin string
 	}
+	OIDC struct {
+		Issuer       string
+		ClientID     string `mapstructure:"client_id"`
+		ClientSecret string `mapstructure:"client_secret"`
+		RedirectURL  string `mapstructure:"redirect_url"`
+	}
+	Mail struct {
+		Driver   string
+		Host     string
+		Port     int
+		Username string
+		Password string
+		From     string
+		Dir      string
+	}
 }
 
 var (
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	// AccessTokenPrefix is a prefix of personal access tokens that distinguishes them from session tokens.
+	AccessTokenPrefix = "kpat_"
+	// ScopeRead is a scope of AccessToken that allows only read requests.
+	ScopeRead = "read"
+	// ScopeWrite is a scope of AccessToken that allows read and write requests.
+	ScopeWrite = "write"
+)
+
+// AccessToken is model of access_tokens table.
+// a personal access token is long-lived and used by scripts instead of a session.
+// only a digest of the token is stored.
+type AccessToken struct {
+	ID         uint       `json:"id"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt  time.Time  `json:"-" gorm:"not null"`
+	UserID     uint       `json:"-" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"size:50;not null"`
+	Scope      string     `json:"scope" gorm:"type:enum('read','write');not null"`
+	BoardID    *uint      `json:"board_id"`
+	Digest     string     `json:"-" gorm:"size:64;unique;not null"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// actions of an activity.
+const (
+	ActivityCreate  = "create"
+	ActivityUpdate  = "update"
+	ActivityMove    = "move"
+	ActivityDelete  = "delete"
+	ActivityRestore = "restore"
+)
+
+// targets of an activity.
+const (
+	ActivityTargetBoard         = "board"
+	ActivityTargetList          = "list"
+	ActivityTargetCard          = "card"
+	ActivityTargetLabel         = "label"
+	ActivityTargetCardLabel     = "card_label"
+	ActivityTargetCheckList     = "check_list"
+	ActivityTargetCheckListItem = "check_list_item"
+	ActivityTargetFile          = "file"
+	ActivityTargetCover         = "cover"
+)
+
+// ActivityValues are attributes of a target before or after an activity.
+type ActivityValues map[string]interface{}
+
+// Activity is model of activities table.
+// an activity records who did what to a board or something in it.
+// CardID is set when the target is a card or belongs to a card, so that activities of a card are found.
+// Before and After are JSON of ActivityValues, and are empty when the target did not exist.
+type Activity struct {
+	ID         uint      `json:"id"`
+	CreatedAt  time.Time `json:"created_at" gorm:"not null"`
+	BoardID    uint      `json:"board_id" gorm:"not null;index"`
+	CardID     *uint     `json:"card_id" gorm:"index"`
+	ActorID    uint      `json:"actor_id" gorm:"not null"`
+	Action     string    `json:"action" gorm:"not null"`
+	TargetType string    `json:"target_type" gorm:"not null"`
+	TargetID   uint      `json:"target_id" gorm:"not null"`
+	Before     string    `json:"-" gorm:"type:text"`
+	After      string    `json:"-" gorm:"type:text"`
+	Actor      *User     `json:"-" gorm:"foreignkey:ActorID"`
+}
@@ -1,8 +1,16 @@
 package entity
 
+import "local.packages/validator"
+
 // BackgroundImage is model of background_images table.
 type BackgroundImage struct {
 	ID    uint   `json:"id"`
-	URL   string `json:"url" gorm:"not null"`
-	Theme string `json:"theme" gorm:"type:enum('light','dark')"`
+	URL   string `json:"url" validate:"required,url,max=255" gorm:"not null"`
+	Theme string `json:"theme" validate:"required,oneof=light dark" gorm:"type:enum('light','dark')"`
+}
+
+// BeforeSave called before create/update a record of background_images table.
+// validate a field of struct and return an error if there is an invalid value
+func (b *BackgroundImage) BeforeSave() error {
+	return validator.Validate(b)
 }
@@ -14,6 +14,8 @@ type Board struct {
 	DeletedAt       *time.Time            `json:"-"`
 	Name            string                `json:"name" validate:"required,max=50" gorm:"size:50;not null"`
 	UserID          uint                  `json:"-" gorm:"not null"`
+	WorkspaceID     *uint                 `json:"workspace_id" gorm:"index"`
+	Version         uint                  `json:"version" gorm:"not null"`
 	Lists           []List                `json:"lists"`
 	BackgroundImage *BoardBackgroundImage `json:"background_image"`
 }
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"local.packages/validator"
+)
+
+const (
+	// BoardRoleOwner is a role of a member who can manage members and delete the board.
+	BoardRoleOwner = "owner"
+	// BoardRoleEditor is a role of a member who can edit lists, cards and labels of the board.
+	BoardRoleEditor = "editor"
+	// BoardRoleViewer is a role of a member who can only view the board.
+	BoardRoleViewer = "viewer"
+)
+
+// BoardMember is model of board_members table.
+type BoardMember struct {
+	BoardID   uint      `json:"board_id" gorm:"primary_key;auto_increment:false"`
+	UserID    uint      `json:"user_id" gorm:"primary_key;auto_increment:false"`
+	Role      string    `json:"role" validate:"required,oneof=owner editor viewer" gorm:"type:enum('owner','editor','viewer');not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null"`
+	User      *User     `json:"-" gorm:"foreignkey:UserID"`
+}
+
+// BeforeSave called before create/update a record of board_members table.
+// validate a field of struct and return an error if there is an invalid value
+func (m *BoardMember) BeforeSave() error {
+	return validator.Validate(m)
+}
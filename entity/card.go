@@ -6,19 +6,50 @@ import (
 	"local.packages/validator"
 )
 
+// DueSoonWindow is the time before a due date during which a card is due soon.
+const DueSoonWindow = 24 * time.Hour
+
 // Card is model of cards table.
+// StartAt and DueAt are optional, and a card that is Completed is neither overdue nor due soon.
 type Card struct {
-	ID          uint        `json:"id"`
-	CreatedAt   time.Time   `json:"-" gorm:"not null"`
-	UpdatedAt   time.Time   `json:"-" gorm:"not null"`
-	DeletedAt   *time.Time  `json:"-"`
-	Title       string      `json:"title" validate:"required,max=50" gorm:"not null;size:50"`
-	Description string      `json:"description" gorm:"type:varchar(20000)"`
-	ListID      uint        `json:"list_id" gorm:"not null"`
-	Labels      []Label     `json:"labels" gorm:"many2many:card_labels;"`
-	CheckLists  []CheckList `json:"check_lists"`
-	Index       int         `json:"index"`
-	Cover       *Cover      `json:"cover"`
+	ID          uint           `json:"id"`
+	CreatedAt   time.Time      `json:"-" gorm:"not null"`
+	UpdatedAt   time.Time      `json:"-" gorm:"not null"`
+	DeletedAt   *time.Time     `json:"-"`
+	Title       string         `json:"title" validate:"required,max=50" gorm:"not null;size:50"`
+	Description string         `json:"description" gorm:"type:varchar(20000)"`
+	ListID      uint           `json:"list_id" gorm:"not null"`
+	Labels      []Label        `json:"labels" gorm:"many2many:card_labels;"`
+	Assignees   []CardAssignee `json:"assignees"`
+	CheckLists  []CheckList    `json:"check_lists"`
+	Index       int            `json:"index"`
+	Cover       *Cover         `json:"cover"`
+	StartAt     *time.Time     `json:"start_at"`
+	DueAt       *time.Time     `json:"due_at" gorm:"index"`
+	Completed   bool           `json:"completed" gorm:"not null"`
+	Version     uint           `json:"version" gorm:"not null"`
+	// CommentCount is the number of comments on the card, and is counted only when a board is shown.
+	CommentCount int `json:"comment_count" gorm:"-"`
+	// Overdue and DueSoon tell the state of the due date, and are set only when a board is shown.
+	Overdue bool `json:"overdue" gorm:"-"`
+	DueSoon bool `json:"due_soon" gorm:"-"`
+}
+
+// DueStatus reports whether a due date is overdue or due soon at a time.
+// a card without a due date or a completed card is neither overdue nor due soon.
+func DueStatus(due *time.Time, completed bool, now time.Time) (overdue, soon bool) {
+	if due == nil || completed {
+		return false, false
+	}
+
+	overdue = due.Before(now)
+
+	return overdue, !overdue && due.Before(now.Add(DueSoonWindow))
+}
+
+// SetDueStatus sets Overdue and DueSoon of a card at a time.
+func (c *Card) SetDueStatus(now time.Time) {
+	c.Overdue, c.DueSoon = DueStatus(c.DueAt, c.Completed, now)
 }
 
 // BeforeSave called before create/update a record of cards table.
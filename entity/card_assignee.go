@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// CardAssignee is model of card_assignees table.
+// only members of the board can be assigned to its cards.
+type CardAssignee struct {
+	CardID    uint      `json:"-" gorm:"primary_key;auto_increment:false"`
+	UserID    uint      `json:"user_id" gorm:"primary_key;auto_increment:false"`
+	CreatedAt time.Time `json:"-" gorm:"not null"`
+}
@@ -13,6 +13,7 @@ type CheckList struct {
 	UpdatedAt time.Time       `json:"-" gorm:"not null"`
 	Title     string          `json:"title" validate:"required,max=50" gorm:"not null;size:50"`
 	CardID    uint            `json:"card_id" gorm:"not null"`
+	Version   uint            `json:"version" gorm:"not null"`
 	Items     []CheckListItem `json:"items"`
 }
 
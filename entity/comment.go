@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"local.packages/validator"
+)
+
+// Comment is model of comments table.
+type Comment struct {
+	ID        uint       `json:"id"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt time.Time  `json:"-" gorm:"not null"`
+	CardID    uint       `json:"card_id" gorm:"not null;index"`
+	UserID    uint       `json:"user_id" gorm:"not null"`
+	Body      string     `json:"body" validate:"required,max=5000" gorm:"type:text;not null"`
+	EditedAt  *time.Time `json:"edited_at"`
+	User      *User      `json:"-" gorm:"foreignkey:UserID"`
+}
+
+// BeforeSave called before create/update a record of comments table.
+// validate a field of struct and return an error if there is an invalid value
+func (c *Comment) BeforeSave() error {
+	return validator.Validate(c)
+}
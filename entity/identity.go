@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// Identity is model of identities table.
+// it links an account of an external OpenID Provider to a user.
+type Identity struct {
+	ID        uint      `json:"-"`
+	CreatedAt time.Time `json:"-" gorm:"not null"`
+	UserID    uint      `json:"-" gorm:"not null;index"`
+	Issuer    string    `json:"-" gorm:"size:255;not null;unique_index:idx_identities_issuer_subject"`
+	Subject   string    `json:"-" gorm:"size:255;not null;unique_index:idx_identities_issuer_subject"`
+}
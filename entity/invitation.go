@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"local.packages/validator"
+)
+
+// Invitation is model of invitations table.
+// an invitation link adds the user who opens it to members of a board with a preset role.
+// only a digest of the token is stored.
+type Invitation struct {
+	ID        uint       `json:"id"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null"`
+	BoardID   uint       `json:"board_id" gorm:"not null;index"`
+	InviterID uint       `json:"inviter_id" gorm:"not null"`
+	Role      string     `json:"role" validate:"required,oneof=owner editor viewer" gorm:"type:enum('owner','editor','viewer');not null"`
+	Digest    string     `json:"-" gorm:"size:64;unique;not null"`
+	SingleUse bool       `json:"single_use" gorm:"not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+// BeforeSave called before create/update a record of invitations table.
+// validate a field of struct and return an error if there is an invalid value
+func (i *Invitation) BeforeSave() error {
+	return validator.Validate(i)
+}
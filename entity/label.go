@@ -15,6 +15,7 @@ type Label struct {
 	Name      string     `json:"name" validate:"required,max=50" gorm:"not null;size:50"`
 	Color     string     `json:"color" validate:"required,hexcolor" gorm:"not null;size:7"`
 	BoardID   uint       `json:"-" gorm:"not null"`
+	Version   uint       `json:"version" gorm:"not null"`
 }
 
 // BeforeSave called before create/update a record of labels table.
@@ -16,6 +16,7 @@ type List struct {
 	BoardID   uint       `json:"board_id" gorm:"not null"`
 	Cards     []Card     `json:"cards"`
 	Index     int        `json:"index"`
+	Version   uint       `json:"version" gorm:"not null"`
 }
 
 // BeforeSave called before create/update a record of lists table.
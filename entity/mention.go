@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Mention is model of mentions table.
+// a mention is written in a description of a card when CommentID is nil, otherwise in a comment.
+type Mention struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null"`
+	BoardID   uint      `json:"board_id" gorm:"not null"`
+	CardID    uint      `json:"card_id" gorm:"not null;index"`
+	CommentID *uint     `json:"comment_id" gorm:"index"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	ActorID   uint      `json:"actor_id" gorm:"not null"`
+	User      *User     `json:"-" gorm:"foreignkey:UserID"`
+	Actor     *User     `json:"-" gorm:"foreignkey:ActorID"`
+}
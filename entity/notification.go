@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// types of a notification.
+const (
+	NotificationAssigned       = "assigned"
+	NotificationMentioned      = "mentioned"
+	NotificationCardMoved      = "card_moved"
+	NotificationCardUpdated    = "card_updated"
+	NotificationCardDeleted    = "card_deleted"
+	NotificationCheckListAdded = "check_list_added"
+	NotificationFileAdded      = "file_added"
+	NotificationListUpdated    = "list_updated"
+	NotificationListDeleted    = "list_deleted"
+	NotificationBoardUpdated   = "board_updated"
+	NotificationBoardDeleted   = "board_deleted"
+)
+
+// Notification is model of notifications table.
+// a notification tells a user that a board, a list or a card was changed by another member, and is read in the inbox of the user.
+// Title is the name of the target at the time.
+type Notification struct {
+	ID        uint       `json:"id"`
+	CreatedAt time.Time  `json:"created_at" gorm:"not null"`
+	UserID    uint       `json:"-" gorm:"not null;index"`
+	ActorID   uint       `json:"actor_id" gorm:"not null"`
+	Type      string     `json:"type" gorm:"not null"`
+	BoardID   uint       `json:"board_id" gorm:"not null"`
+	ListID    *uint      `json:"list_id"`
+	CardID    *uint      `json:"card_id"`
+	Title     string     `json:"title" gorm:"not null"`
+	ReadAt    *time.Time `json:"read_at"`
+	Actor     *User      `json:"-" gorm:"foreignkey:ActorID"`
+}
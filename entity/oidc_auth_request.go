@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// OIDCAuthRequest is model of oidc_auth_requests table.
+// it keeps a nonce and a code verifier of an authorization request until the callback.
+// only a digest of the state is stored.
+type OIDCAuthRequest struct {
+	ID           uint      `json:"-"`
+	CreatedAt    time.Time `json:"-" gorm:"not null"`
+	StateDigest  string    `json:"-" gorm:"size:64;unique;not null"`
+	Nonce        string    `json:"-" gorm:"size:64;not null"`
+	CodeVerifier string    `json:"-" gorm:"size:64;not null"`
+	ExpiresAt    time.Time `json:"-" gorm:"not null"`
+}
+
+// TableName overrides a table name that is derived from the struct name.
+func (OIDCAuthRequest) TableName() string {
+	return "oidc_auth_requests"
+}
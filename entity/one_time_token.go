@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	// PurposePasswordReset is a purpose of OneTimeToken that used to reset a password.
+	PurposePasswordReset = "password_reset"
+	// PurposeEmailVerification is a purpose of OneTimeToken that used to verify an email address.
+	PurposeEmailVerification = "email_verification"
+	// PurposeSignInChallenge is a purpose of OneTimeToken that used to complete a sign in with a second factor.
+	PurposeSignInChallenge = "sign_in_challenge"
+	// PurposeEventStream is a purpose of OneTimeToken that used to open a stream of events from a browser that can not send an auth header.
+	PurposeEventStream = "event_stream"
+)
+
+// OneTimeToken is model of one_time_tokens table.
+// only a digest of the token is stored.
+// Failures counts wrong attempts to use the token with a second factor.
+type OneTimeToken struct {
+	ID        uint       `json:"-"`
+	CreatedAt time.Time  `json:"-" gorm:"not null"`
+	UserID    uint       `json:"-" gorm:"not null;index"`
+	Purpose   string     `json:"-" gorm:"size:30;not null"`
+	Digest    string     `json:"-" gorm:"size:64;unique;not null"`
+	ExpiresAt time.Time  `json:"-" gorm:"not null"`
+	UsedAt    *time.Time `json:"-"`
+	Failures  int        `json:"-" gorm:"not null"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// types of a presence event.
+const (
+	PresenceJoin   = "join"
+	PresenceUpdate = "update"
+	PresenceLeave  = "leave"
+)
+
+// Presence is a user who is looking at a board.
+// CardID is set while the user has a card of the board open.
+// presences are kept in memory of a process and are not stored in database.
+type Presence struct {
+	UserID uint      `json:"user_id"`
+	Name   string    `json:"name"`
+	CardID *uint     `json:"card_id"`
+	SeenAt time.Time `json:"seen_at"`
+}
+
+// PresenceEvent is sent to viewers of a board when a user joins or leaves the board, or opens or closes a card.
+type PresenceEvent struct {
+	Type     string   `json:"type"`
+	BoardID  uint     `json:"board_id"`
+	Presence Presence `json:"presence"`
+}
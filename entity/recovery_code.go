@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// RecoveryCode is model of recovery_codes table.
+// a recovery code is used instead of a TOTP code when the user lost the authenticator.
+// only a digest of the code is stored.
+type RecoveryCode struct {
+	ID        uint       `json:"-"`
+	CreatedAt time.Time  `json:"-" gorm:"not null"`
+	UserID    uint       `json:"-" gorm:"not null;index"`
+	Digest    string     `json:"-" gorm:"size:64;unique;not null"`
+	UsedAt    *time.Time `json:"-"`
+}
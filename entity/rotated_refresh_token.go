@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// RotatedRefreshToken is model of rotated_refresh_tokens table.
+// it keeps digests of refresh tokens that were replaced, so that reuse of a stolen refresh token is detected.
+type RotatedRefreshToken struct {
+	ID        uint      `json:"-"`
+	CreatedAt time.Time `json:"-" gorm:"not null"`
+	SessionID uint      `json:"-" gorm:"not null;index"`
+	Digest    string    `json:"-" gorm:"size:64;unique;not null"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Session is model of sessions table.
+// a user has one session per signed in device.
+// a session is also a family of refresh tokens, that is revoked when a rotated refresh token is used again.
+// only digests of tokens are stored. plain tokens are set only right after they were issued.
+type Session struct {
+	ID             uint      `json:"id"`
+	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time `json:"-" gorm:"not null"`
+	UserID         uint      `json:"-" gorm:"not null;index"`
+	Device         string    `json:"device" gorm:"size:100"`
+	UserAgent      string    `json:"user_agent" gorm:"size:255"`
+	IPAddress      string    `json:"ip_address" gorm:"size:45"`
+	RememberDigest string    `json:"-" gorm:"column:remember_token;unique;not null"`
+	RefreshDigest  string    `json:"-" gorm:"column:refresh_token;unique;not null"`
+	RememberToken  string    `json:"-" gorm:"-"`
+	RefreshToken   string    `json:"-" gorm:"-"`
+	LastSeenAt     time.Time `json:"last_seen_at" gorm:"not null"`
+	ExpiresAt      time.Time `json:"expires_at" gorm:"not null"`
+	Current        bool      `json:"current" gorm:"-"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// SignInThrottle is model of sign_in_throttles table.
+// it counts failed sign in attempts per email address or per IP address.
+type SignInThrottle struct {
+	ID           uint       `json:"-"`
+	Identifier   string     `json:"-" gorm:"size:255;unique;not null"`
+	Failures     int        `json:"-" gorm:"not null"`
+	LastFailedAt time.Time  `json:"-" gorm:"not null"`
+	LockedUntil  *time.Time `json:"-"`
+}
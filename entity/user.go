@@ -2,16 +2,27 @@ package entity
 
 import "time"
 
+const (
+	// RoleUser is a role of a general user.
+	RoleUser = "user"
+	// RoleAdmin is a role of an administrator who can use the administration API.
+	RoleAdmin = "admin"
+)
+
 // User is model of users table.
 type User struct {
-	ID             uint      `json:"id"`
-	CreatedAt      time.Time `json:"created_at" gorm:"not null"`
-	UpdatedAt      time.Time `json:"updated_at" gorm:"not null"`
-	Name           string    `json:"name" gorm:"not null"`
-	Email          string    `json:"email" gorm:"unique;not null"`
-	PasswordDigest string    `json:"password_digest" gorm:"not null"`
-	RememberToken  string    `json:"remember_token"`
-	RefreshToken   string    `json:"refresh_token"`
-	ExpiresAt      time.Time `json:"-" gorm:"not null"`
-	Boards         []Board   `json:"boards" gorm:"foreignkey:UserID"`
+	ID             uint       `json:"id"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"not null"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"not null"`
+	Name           string     `json:"name" gorm:"not null"`
+	Email          string     `json:"email" gorm:"unique;not null"`
+	PasswordDigest string     `json:"-" gorm:"not null"`
+	VerifiedAt     *time.Time `json:"verified_at"`
+	TOTPSecret     string     `json:"-" gorm:"column:totp_secret;size:32"`
+	TOTPEnabledAt  *time.Time `json:"-" gorm:"column:totp_enabled_at"`
+	TOTPLastStep   int64      `json:"-" gorm:"column:totp_last_step;not null;default:0"`
+	Role           string     `json:"role" gorm:"type:enum('user','admin');not null;default:'user'"`
+	DisabledAt     *time.Time `json:"disabled_at"`
+	Boards         []Board    `json:"boards" gorm:"foreignkey:UserID"`
+	Sessions       []Session  `json:"-" gorm:"foreignkey:UserID"`
 }
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// targets of a watch.
+const (
+	WatchTargetBoard = "board"
+	WatchTargetList  = "list"
+	WatchTargetCard  = "card"
+)
+
+// Watch is model of watches table.
+// a user who watches a board, a list or a card is notified when it or something in it changes.
+type Watch struct {
+	UserID     uint      `json:"-" gorm:"primary_key;auto_increment:false"`
+	TargetType string    `json:"target_type" gorm:"primary_key;type:varchar(10)"`
+	TargetID   uint      `json:"target_id" gorm:"primary_key;auto_increment:false"`
+	CreatedAt  time.Time `json:"created_at" gorm:"not null"`
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"local.packages/validator"
+)
+
+const (
+	// WorkspaceRoleOwner is a role of a member who can manage members and settings of the workspace.
+	WorkspaceRoleOwner = "owner"
+	// WorkspaceRoleMember is a role of a member who can create boards in the workspace.
+	WorkspaceRoleMember = "member"
+)
+
+// Workspace is model of workspaces table.
+// members of a workspace join its boards with the default role.
+type Workspace struct {
+	ID          uint      `json:"id"`
+	CreatedAt   time.Time `json:"-" gorm:"not null"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"not null"`
+	Name        string    `json:"name" validate:"required,max=50" gorm:"size:50;not null"`
+	DefaultRole string    `json:"default_role" validate:"required,oneof=editor viewer" gorm:"type:enum('editor','viewer');not null"`
+}
+
+// BeforeSave called before create/update a record of workspaces table.
+// validate a field of struct and return an error if there is an invalid value
+func (w *Workspace) BeforeSave() error {
+	return validator.Validate(w)
+}
+
+// WorkspaceMember is model of workspace_members table.
+type WorkspaceMember struct {
+	WorkspaceID uint      `json:"workspace_id" gorm:"primary_key;auto_increment:false"`
+	UserID      uint      `json:"user_id" gorm:"primary_key;auto_increment:false"`
+	Role        string    `json:"role" validate:"required,oneof=owner member" gorm:"type:enum('owner','member');not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"not null"`
+	User        *User     `json:"-" gorm:"foreignkey:UserID"`
+}
+
+// BeforeSave called before create/update a record of workspace_members table.
+// validate a field of struct and return an error if there is an invalid value
+func (m *WorkspaceMember) BeforeSave() error {
+	return validator.Validate(m)
+}
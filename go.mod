@@ -10,8 +10,14 @@ replace local.packages/entity => ./entity
 
 replace local.packages/handler => ./handler
 
+replace local.packages/jwt => ./jwt
+
+replace local.packages/mailer => ./mailer
+
 replace local.packages/migration => ./migration
 
+replace local.packages/oidc => ./oidc
+
 replace local.packages/repository => ./repository
 
 replace local.packages/utils => ./utils
@@ -36,7 +42,10 @@ require (
 	local.packages/db v0.0.0-00010101000000-000000000000
 	local.packages/entity v0.0.0-00010101000000-000000000000 // indirect
 	local.packages/handler v0.0.0-00010101000000-000000000000
+	local.packages/jwt v0.0.0-00010101000000-000000000000 // indirect
+	local.packages/mailer v0.0.0-00010101000000-000000000000 // indirect
 	local.packages/migration v0.0.0-00010101000000-000000000000
+	local.packages/oidc v0.0.0-00010101000000-000000000000 // indirect
 	local.packages/repository v0.0.0-00010101000000-000000000000
 	local.packages/utils v0.0.0-00010101000000-000000000000 // indirect
 	local.packages/validator v0.0.0-00010101000000-000000000000 // indirect
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/validator"
+)
+
+type accessTokenParams struct {
+	Name      string     `json:"name" binding:"required,max=50"`
+	Scope     string     `json:"scope" binding:"required,oneof=read write"`
+	BoardID   *uint      `json:"board_id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// IndexAccessTokens returns status 200 and slice of AccessToken instance as http response.
+func (h UserHandler) IndexAccessTokens(c *gin.Context) {
+	ats := h.repository.GetAccessTokens(currentUserID(c))
+
+	c.JSON(http.StatusOK, gin.H{"access_tokens": ats})
+}
+
+// CreateAccessToken call a function that create a new record to access_tokens table.
+// if creation was successful, returns status 201 and the raw token as http response.
+// the raw token is returned only once.
+// if creation was failure, returns status 400 and error with messages.
+func (h UserHandler) CreateAccessToken(c *gin.Context) {
+	var p accessTokenParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	if p.ExpiresAt != nil && p.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	at, t, err := h.repository.CreateAccessToken(currentUserID(c), p.Name, p.Scope, p.BoardID, p.ExpiresAt)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"access_token": at, "token": t})
+}
+
+// RevokeAccessToken call a function that delete an access token of the login user.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and error with messages.
+func (h UserHandler) RevokeAccessToken(c *gin.Context) {
+	if err := h.repository.DeleteAccessToken(getIDParam(c, "accessTokenID"), currentUserID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
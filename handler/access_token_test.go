@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+type accessTokenRequestBody struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+}
+
+func TestCreateAccessTokenHandlerShouldReturnsStatusCreatedWithToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, err := json.Marshal(accessTokenRequestBody{Name: "ci", Scope: entity.ScopeRead})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/access_tokens", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `access_tokens`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	r.POST("/access_tokens", RejectAccessToken(), h.CreateAccessToken)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 201)
+	assert.Regexp(t, "^"+entity.AccessTokenPrefix, res["token"])
+}
+
+func TestCreateAccessTokenHandlerShouldReturnsStatusBadRequestWhenScopeIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, err := json.Marshal(accessTokenRequestBody{Name: "ci", Scope: "admin"})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/access_tokens", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	r.POST("/access_tokens", h.CreateAccessToken)
+	r.ServeHTTP(w, req)
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, res["errors"][0].Text, validator.ErrorOneOf("スコープ", "read, write"))
+}
+
+func TestIndexAccessTokensHandlerShouldReturnsStatusOKWithAccessTokens(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/access_tokens", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, created_at, name, scope, board_id, last_used_at, expires_at FROM `access_tokens`")).
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "scope"}).AddRow(uint(1), "ci", entity.ScopeRead))
+
+	r.GET("/access_tokens", h.IndexAccessTokens)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]entity.AccessToken{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, res["access_tokens"][0].Name, "ci")
+}
+
+func TestRevokeAccessTokenHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/access_tokens/3", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `access_tokens`")).
+		WithArgs(uint(3), uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.DELETE("/access_tokens/:accessTokenID", h.RevokeAccessToken)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestAuthenticateWithAccessToken(t *testing.T) {
+	type testCase struct {
+		testName     string
+		method       string
+		scope        string
+		boardID      interface{}
+		expectedCode int
+		mockQueries  func(mock sqlmock.Sqlmock)
+	}
+
+	testCases := []testCase{
+		{
+			testName:     "when read scope requests GET",
+			method:       http.MethodGet,
+			scope:        entity.ScopeRead,
+			expectedCode: 200,
+		}, {
+			testName:     "when read scope requests PATCH",
+			method:       http.MethodPatch,
+			scope:        entity.ScopeRead,
+			expectedCode: 403,
+		}, {
+			testName:     "when write scope requests PATCH",
+			method:       http.MethodPatch,
+			scope:        entity.ScopeWrite,
+			expectedCode: 200,
+		}, {
+			testName:     "when board scope matches the card's board",
+			method:       http.MethodPatch,
+			scope:        entity.ScopeWrite,
+			boardID:      uint(2),
+			expectedCode: 200,
+			mockQueries: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT lists.board_id FROM `cards`")).
+					WithArgs(uint(4)).
+					WillReturnRows(sqlmock.NewRows([]string{"board_id"}).AddRow(uint(2)))
+			},
+		}, {
+			testName:     "when board scope does not match the card's board",
+			method:       http.MethodPatch,
+			scope:        entity.ScopeWrite,
+			boardID:      uint(5),
+			expectedCode: 403,
+			mockQueries: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT lists.board_id FROM `cards`")).
+					WithArgs(uint(4)).
+					WillReturnRows(sqlmock.NewRows([]string{"board_id"}).AddRow(uint(2)))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			db, mock := utils.NewDBMock(t)
+			defer db.Close()
+
+			h := NewUserHandler(repository.NewUserRepository(db))
+
+			token := entity.AccessTokenPrefix + "sampletoken"
+
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `access_tokens`")).
+				WillReturnRows(
+					sqlmock.NewRows([]string{"id", "user_id", "scope", "board_id"}).
+						AddRow(uint(3), uint(1), tc.scope, tc.boardID))
+
+			if tc.mockQueries != nil {
+				tc.mockQueries(mock)
+			}
+
+			if tc.expectedCode == 200 {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `access_tokens` SET `last_used_at` = ?")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			}
+
+			r := utils.SetUpRouter()
+			r.Use(MapIDParamsToContext(), h.Authenticate())
+			r.Handle(tc.method, "/card/:cardID", func(c *gin.Context) {
+				assert.Equal(t, c.Keys["uid"], uint(1))
+				c.Status(200)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, "/card/4", nil)
+			req.Header.Add("Authorization", "Bearer "+token)
+
+			r.ServeHTTP(w, req)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("there were unfulfilled expectations: %v", err)
+			}
+
+			assert.Equal(t, w.Code, tc.expectedCode)
+		})
+	}
+}
+
+func TestRejectAccessTokenShouldRejectRequestAuthenticatedByAccessToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `access_tokens`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "scope"}).
+				AddRow(uint(3), uint(1), entity.ScopeWrite))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `access_tokens` SET `last_used_at` = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r := utils.SetUpRouter()
+	r.Use(h.Authenticate(), RejectAccessToken())
+	r.GET("/sessions", func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/sessions", nil)
+	req.Header.Add("X-Auth-Token", entity.AccessTokenPrefix+"sampletoken")
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 403)
+	assert.Equal(t, res["errors"][0].Text, ErrorInsufficientScope)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+type profileParams struct {
+	Name  string `json:"name" binding:"omitempty"`
+	Email string `json:"email" binding:"omitempty,email"`
+}
+
+type changePasswordParams struct {
+	CurrentPassword      string `json:"current_password" binding:"required"`
+	Password             string `json:"password" binding:"required,min=8,eqfield=PasswordConfirmation"`
+	PasswordConfirmation string `json:"password_confirmation" binding:"required"`
+}
+
+type deleteAccountParams struct {
+	Password string `json:"password" binding:"required"`
+}
+
+func profileResponse(u *entity.User) gin.H {
+	return gin.H{
+		"id":                 u.ID,
+		"name":               u.Name,
+		"email":              u.Email,
+		"verified_at":        u.VerifiedAt,
+		"two_factor_enabled": u.TOTPEnabledAt != nil,
+		"role":               u.Role,
+		"created_at":         u.CreatedAt,
+	}
+}
+
+// ShowUser returns status 200 and a profile of the login user as http response.
+func (h UserHandler) ShowUser(c *gin.Context) {
+	u, err := h.repository.Find(currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": profileResponse(u)})
+}
+
+// UpdateUser call a function that update a name and an email address of the login user.
+// if an email address was changed, send a verification mail to the new address.
+// if update was successful, returns status 200 and a profile of the user as http response.
+// if update was failure, returns status 400 and error with messages.
+func (h UserHandler) UpdateUser(c *gin.Context) {
+	var p profileParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	u, err := h.repository.Find(currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	emailChanged := p.Email != "" && p.Email != u.Email
+
+	if err := h.repository.UpdateProfile(u, p.Name, p.Email); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if emailChanged {
+		u.Email = p.Email
+		u.VerifiedAt = nil
+
+		if err := h.sendVerificationMail(u); err != nil {
+			log.Printf("fail to send verification mail on email change: %v", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": profileResponse(u)})
+}
+
+// UpdatePassword call a function that change a password of the login user.
+// other devices of the user are signed out.
+// if update was successful, returns status 200.
+// if update was failure, returns status 400 and error with messages.
+func (h UserHandler) UpdatePassword(c *gin.Context) {
+	var p changePasswordParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	passwordDigest, err := h.repository.EncryptPassword(p.Password)
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.ChangePassword(currentUserID(c), currentSessionID(c), p.CurrentPassword, passwordDigest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// DeleteUser call a function that delete the login user with boards and uploaded files that are not shared with others.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and error with messages.
+func (h UserHandler) DeleteUser(c *gin.Context) {
+	var p deleteAccountParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	if err := h.repository.DeleteAccount(currentUserID(c), p.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+type profileRequestBody struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type changePasswordRequestBody struct {
+	CurrentPassword      string `json:"current_password"`
+	Password             string `json:"password"`
+	PasswordConfirmation string `json:"password_confirmation"`
+}
+
+type deleteAccountRequestBody struct {
+	Password string `json:"password"`
+}
+
+func TestShowUserHandlerShouldReturnsStatusOKWithProfile(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/user", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "email", "password_digest"}).
+				AddRow(uint(1), "gopher", "gopher@sample.com", "digest"))
+
+	r.GET("/user", h.ShowUser)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, res["user"]["name"], "gopher")
+	assert.Equal(t, res["user"]["email"], "gopher@sample.com")
+	assert.NotContains(t, res["user"], "password_digest")
+}
+
+func TestUpdateUserHandlerShouldReturnsStatusOKWithProfile(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	name := "new gopher"
+
+	b, err := json.Marshal(profileRequestBody{Name: name})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/user", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "email", "verified_at"}).
+				AddRow(uint(1), "gopher", "gopher@sample.com", time.Now()))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `name` = ?, `updated_at` = ?")).
+		WithArgs(name, utils.AnyTime{}, uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.PATCH("/user", h.UpdateUser)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, res["user"]["name"], name)
+	assert.NotNil(t, res["user"]["verified_at"])
+}
+
+func TestUpdateUserHandlerShouldReturnsStatusBadRequestWhenEmailIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, err := json.Marshal(profileRequestBody{Email: "gopher"})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/user", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	r.PATCH("/user", h.UpdateUser)
+	r.ServeHTTP(w, req)
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, res["errors"][0].Text, validator.ErrorEmail("メールアドレス"))
+}
+
+func TestUpdatePasswordHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	current := "12345678"
+	currentDigest, _ := bcrypt.GenerateFromPassword([]byte(current), bcrypt.DefaultCost)
+
+	b, err := json.Marshal(changePasswordRequestBody{
+		CurrentPassword:      current,
+		Password:             "87654321",
+		PasswordConfirmation: "87654321",
+	})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/user/password", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_digest"}).AddRow(uint(1), currentDigest))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `password_digest`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions`")).
+		WithArgs(uint(1), uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.PUT("/user/password", h.UpdatePassword)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestUpdatePasswordHandlerShouldReturnsStatusBadRequestWhenCurrentPasswordIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	currentDigest, _ := bcrypt.GenerateFromPassword([]byte("12345678"), bcrypt.DefaultCost)
+
+	b, err := json.Marshal(changePasswordRequestBody{
+		CurrentPassword:      "00000000",
+		Password:             "87654321",
+		PasswordConfirmation: "87654321",
+	})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/user/password", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_digest"}).AddRow(uint(1), currentDigest))
+
+	r.PUT("/user/password", h.UpdatePassword)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorInvalidPassword)
+}
+
+func TestDeleteUserHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	password := "12345678"
+	passwordDigest, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	b, err := json.Marshal(deleteAccountRequestBody{Password: password})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/user", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_digest"}).AddRow(uint(1), passwordDigest))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT workspace_id FROM `workspace_members`")).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id"}))
+
+	for _, table := range []string{"boards", "lists", "cards", "check_lists"} {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `" + table + "`")).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT card_id, `key` FROM `files`")).
+		WillReturnRows(sqlmock.NewRows([]string{"card_id", "key"}))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `users`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.DELETE("/user", h.DeleteUser)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestDeleteUserHandlerShouldReturnsStatusBadRequestWithoutPassword(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, err := json.Marshal(deleteAccountRequestBody{})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/user", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	r.DELETE("/user", h.DeleteUser)
+	r.ServeHTTP(w, req)
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, res["errors"][0].Text, validator.ErrorRequired("パスワード"))
+}
+
+func TestRejectTestUserAccountShouldRejectTestUser(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sessions`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "last_seen_at", "expires_at"}).
+				AddRow(uint(1), uint(2), time.Now(), time.Now().Add(time.Hour*1)))
+
+	r := utils.SetUpRouter()
+	r.Use(h.Authenticate(), h.RejectTestUserAccount())
+	r.DELETE("/user", func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/user", nil)
+	req.Header.Add("X-Auth-Token", "sampletoken")
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 403)
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/entity"
+	"local.packages/repository"
+	"local.packages/validator"
+)
+
+// eventHeartbeatInterval is the interval of comments that keep a stream of events open through proxies.
+var eventHeartbeatInterval = 15 * time.Second
+
+// ActivityHandler ...
+// presence is used to mark users who have a stream of events open as looking at the board.
+type ActivityHandler struct {
+	repository *repository.ActivityRepository
+	presence   *repository.PresenceRepository
+}
+
+// NewActivityHandler is constructor for ActivityHandler.
+func NewActivityHandler(r *repository.ActivityRepository, p *repository.PresenceRepository) *ActivityHandler {
+	return &ActivityHandler{repository: r, presence: p}
+}
+
+// activityValues returns values of an activity as raw JSON, or null when there are no values.
+func activityValues(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+
+	return json.RawMessage(s)
+}
+
+func activityResponse(a *entity.Activity) gin.H {
+	r := gin.H{
+		"id":          a.ID,
+		"action":      a.Action,
+		"target_type": a.TargetType,
+		"target_id":   a.TargetID,
+		"board_id":    a.BoardID,
+		"card_id":     a.CardID,
+		"actor_id":    a.ActorID,
+		"before":      activityValues(a.Before),
+		"after":       activityValues(a.After),
+		"created_at":  a.CreatedAt,
+	}
+
+	if a.Actor != nil {
+		r["actor_name"] = a.Actor.Name
+	}
+
+	return r
+}
+
+func activitiesResponse(as *[]entity.Activity) []gin.H {
+	r := []gin.H{}
+
+	for i := range *as {
+		r = append(r, activityResponse(&(*as)[i]))
+	}
+
+	return r
+}
+
+// IndexBoardActivities returns status 200 and activities of a board as http response.
+// activities are paginated by query `page` newest first, and the number of all activities of the board is returned as total.
+func (h ActivityHandler) IndexBoardActivities(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	bid := getIDParam(c, "boardID")
+
+	if err := h.repository.ValidateUID(bid, currentUserID(c)); err != nil {
+		log.Println("uid is not a member of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	as, total := h.repository.GetAll(bid, page)
+
+	c.JSON(http.StatusOK, gin.H{"activities": activitiesResponse(as), "total": total})
+}
+
+// IndexCardActivities returns status 200 and activities of a card as http response.
+// activities of check lists, files and a cover of the card are included.
+// activities are paginated by query `page` newest first, and the number of all activities of the card is returned as total.
+func (h ActivityHandler) IndexCardActivities(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	cid := getIDParam(c, "cardID")
+
+	if err := h.repository.ValidateCardUID(cid, currentUserID(c)); err != nil {
+		log.Println("uid is not a member of the board associated with the card")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	as, total := h.repository.GetAllOfCard(cid, page)
+
+	c.JSON(http.StatusOK, gin.H{"activities": activitiesResponse(as), "total": total})
+}
+
+// writePresenceEvent writes a presence event as a server-sent event named by its type, such as `presence.join`.
+// presence events have no id, so that they do not change the last event id of a client.
+func writePresenceEvent(w io.Writer, e *entity.PresenceEvent) {
+	b, err := json.Marshal(e.Presence)
+
+	if err != nil {
+		log.Printf("fail to marshal presence: %v", err)
+		return
+	}
+
+	fmt.Fprintf(w, "event: presence.%s\ndata: %s\n\n", e.Type, b)
+}
+
+// writeActivityEvent writes an activity as a server-sent event named by its target and action, such as `card.move`.
+func writeActivityEvent(w io.Writer, a *entity.Activity) {
+	b, err := json.Marshal(activityResponse(a))
+
+	if err != nil {
+		log.Printf("fail to marshal activity: %v", err)
+		return
+	}
+
+	fmt.Fprintf(w, "id: %d\nevent: %s.%s\ndata: %s\n\n", a.ID, a.TargetType, a.Action, b)
+}
+
+// lastEventID returns an id of the last event that a client received from header `Last-Event-ID` or query `last_event_id`.
+// returns 0 when the client connects for the first time.
+func lastEventID(c *gin.Context) (uint, error) {
+	s := c.GetHeader("Last-Event-ID")
+
+	if s == "" {
+		s = c.DefaultQuery("last_event_id", "0")
+	}
+
+	id, err := strconv.ParseUint(s, 10, 64)
+	return uint(id), err
+}
+
+// StreamBoardActivities streams activities of a board to the login user as server-sent events.
+// each event has an id of the activity, and activities after the last event id are sent first, so that a client resumes where it left off.
+// a heartbeat comment is sent at eventHeartbeatInterval, and the stream is closed when the login user is no longer a member of the board.
+// the login user is looking at the board while the stream is open, and presence events of other users are sent as well.
+// a browser authenticates the stream with a token created by CreateStreamToken, because EventSource can not send an auth header.
+func (h ActivityHandler) StreamBoardActivities(c *gin.Context) {
+	bid := getIDParam(c, "boardID")
+	uid := currentUserID(c)
+
+	if err := h.repository.ValidateUID(bid, uid); err != nil {
+		log.Println("uid is not a member of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	last, err := lastEventID(c)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	// subscribe before activities are sent again, so that no activity is missed between them.
+	ch, unsubscribe := h.repository.Subscribe(bid)
+	defer unsubscribe()
+
+	pch, unsubscribePresence := h.presence.Subscribe(bid)
+	defer unsubscribePresence()
+
+	h.presence.Enter(bid, uid)
+	defer h.presence.Exit(bid, uid)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if last > 0 {
+		for {
+			as := h.repository.GetSince(bid, last)
+
+			if len(*as) == 0 {
+				break
+			}
+
+			for i := range *as {
+				writeActivityEvent(c.Writer, &(*as)[i])
+				last = (*as)[i].ID
+			}
+		}
+	}
+
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(eventHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case a, ok := <-ch:
+			if !ok {
+				log.Println("subscriber fell behind activities of the board")
+				return
+			}
+
+			// activities of a board are published in order of their ids, so that an activity up to the last id has been sent again already.
+			if a.ID <= last {
+				continue
+			}
+
+			last = a.ID
+			writeActivityEvent(c.Writer, &a)
+			c.Writer.Flush()
+		case e, ok := <-pch:
+			if !ok {
+				log.Println("subscriber fell behind presence events of the board")
+				return
+			}
+
+			if e.Presence.UserID == uid {
+				continue
+			}
+
+			writePresenceEvent(c.Writer, &e)
+			c.Writer.Flush()
+		case <-ticker.C:
+			if err := h.repository.ValidateUID(bid, uid); err != nil {
+				log.Println("uid is no longer a member of the board")
+				return
+			}
+
+			h.presence.Sweep(bid)
+
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestIndexBoardActivitiesHandlerShouldReturnsStatusOKWithActivities(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ah := NewActivityHandler(repository.NewActivityRepository(db), repository.NewPresenceRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/board/1/activities", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activities` WHERE (board_id = ?)")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activities` WHERE (board_id = ?)")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "board_id", "card_id", "actor_id", "action", "target_type", "target_id", "before", "after"}).
+				AddRow(uint(2), uint(1), uint(4), uint(3), "update", "card", uint(4), `{"title":"before"}`, `{"title":"after"}`))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uint(3), "gopher"))
+
+	r.GET("/board/:boardID/activities", ah.IndexBoardActivities)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := struct {
+		Activities []struct {
+			Action    string            `json:"action"`
+			Before    map[string]string `json:"before"`
+			After     map[string]string `json:"after"`
+			ActorName string            `json:"actor_name"`
+		} `json:"activities"`
+		Total int `json:"total"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "update", res.Activities[0].Action)
+	assert.Equal(t, "before", res.Activities[0].Before["title"])
+	assert.Equal(t, "after", res.Activities[0].After["title"])
+	assert.Equal(t, "gopher", res.Activities[0].ActorName)
+}
+
+func TestIndexCardActivitiesHandlerShouldReturnsStatusBadRequestWithInvalidPage(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ah := NewActivityHandler(repository.NewActivityRepository(db), repository.NewPresenceRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/card/1/activities?page=0", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	r.GET("/card/:cardID/activities", ah.IndexCardActivities)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, ErrorInvalidParameter, res["errors"][0].Text)
+}
+
+func TestRestoreCardHandlerShouldReturnsStatusOKWithRestoredCard(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCardHandler(repository.NewCardRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/card/1/restore", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `cards`.* FROM `cards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "list_id"}).AddRow(uint(1), "card", uint(2)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `index` FROM `cards`")).
+		WithArgs(uint(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"index"}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `cards` SET `deleted_at` = ?, `index` = ?")).
+		WithArgs(nil, 0, uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT lists.board_id FROM `cards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id"}).AddRow(uint(3)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activities`")).
+		WithArgs(utils.AnyTime{}, uint(3), uint(1), uint(1), "restore", "card", uint(1), "", `{"list_id":2,"title":"card"}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	r.POST("/card/:cardID/restore", ch.RestoreCard)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := struct {
+		Card struct {
+			ID    uint   `json:"id"`
+			Title string `json:"title"`
+		} `json:"card"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, uint(1), res.Card.ID)
+	assert.Equal(t, "card", res.Card.Title)
+}
+
+func TestStreamBoardActivitiesHandlerShouldSendActivitiesAfterLastEventID(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ah := NewActivityHandler(repository.NewActivityRepository(db), repository.NewPresenceRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/board/1/events", nil)
+	req.Header.Set("Last-Event-ID", "3")
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT name FROM `users` WHERE (id = ?)")).
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("gopher"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activities` WHERE (board_id = ? AND id > ?) ORDER BY id asc LIMIT 100")).
+		WithArgs(uint(1), uint(3)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "board_id", "card_id", "actor_id", "action", "target_type", "target_id", "before", "after"}).
+				AddRow(uint(4), uint(1), uint(2), uint(1), "move", "card", uint(2), `{"list_id":1}`, `{"list_id":3}`))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uint(1), "gopher"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activities` WHERE (board_id = ? AND id > ?) ORDER BY id asc LIMIT 100")).
+		WithArgs(uint(1), uint(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.GET("/board/:boardID/events", ah.StreamBoardActivities)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "id: 4\nevent: card.move\ndata: {")
+	assert.Contains(t, w.Body.String(), `"after":{"list_id":3}`)
+}
+
+func TestStreamBoardActivitiesHandlerShouldCloseStreamWhenUserIsNoLongerMember(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	interval := eventHeartbeatInterval
+	eventHeartbeatInterval = 10 * time.Millisecond
+	defer func() { eventHeartbeatInterval = interval }()
+
+	ah := NewActivityHandler(repository.NewActivityRepository(db), repository.NewPresenceRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/board/1/events", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT name FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("gopher"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.GET("/board/:boardID/events", ah.StreamBoardActivities)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Nil(t, ctx.Err())
+	assert.Equal(t, ": heartbeat\n\n", w.Body.String())
+}
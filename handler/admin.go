@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+type userRoleParams struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
+
+func adminUserResponse(u *entity.User) gin.H {
+	r := profileResponse(u)
+	r["disabled_at"] = u.DisabledAt
+
+	return r
+}
+
+// IndexUsers returns status 200 and users whose name or email contains query `q` as http response.
+// users are paginated by query `page`, and the number of all users that matched is returned as total.
+func (h UserHandler) IndexUsers(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	us, total := h.repository.SearchUsers(c.Query("q"), page)
+
+	r := []gin.H{}
+
+	for i := range *us {
+		r = append(r, adminUserResponse(&(*us)[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": r, "total": total})
+}
+
+// DisableUser call a function that disable a user and sign out the user from every device.
+// if update was successful, returns status 200.
+// if update was failure, returns status 400 and error with messages.
+func (h UserHandler) DisableUser(c *gin.Context) {
+	uid, ok := h.otherUserID(c)
+
+	if !ok {
+		return
+	}
+
+	if err := h.repository.DisableUser(uid); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// EnableUser call a function that enable a disabled user.
+// if update was successful, returns status 200.
+// if update was failure, returns status 400 and error with messages.
+func (h UserHandler) EnableUser(c *gin.Context) {
+	uid, ok := h.otherUserID(c)
+
+	if !ok {
+		return
+	}
+
+	if err := h.repository.EnableUser(uid); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// UpdateUserRole call a function that update a role of a user.
+// if update was successful, returns status 200.
+// if update was failure, returns status 400 and error with messages.
+func (h UserHandler) UpdateUserRole(c *gin.Context) {
+	uid, ok := h.otherUserID(c)
+
+	if !ok {
+		return
+	}
+
+	var p userRoleParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	if err := h.repository.UpdateRole(uid, p.Role); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// SignOutUser call a function that delete all sessions of a user.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and error with messages.
+func (h UserHandler) SignOutUser(c *gin.Context) {
+	uid, ok := h.otherUserID(c)
+
+	if !ok {
+		return
+	}
+
+	if err := h.repository.SignOutAll(uid); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// UnlockUser call a function that unlock sign in of a user locked by failed attempts.
+// if unlock was successful, returns status 200.
+// if unlock was failure, returns status 400 and error with messages.
+func (h UserHandler) UnlockUser(c *gin.Context) {
+	if err := h.repository.UnlockUser(getIDParam(c, "userID")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// ResetUserPassword call a function that send a password reset mail to a user.
+// if sending was successful, returns status 200.
+// if sending was failure, returns status 400 or 500 and error with messages.
+func (h UserHandler) ResetUserPassword(c *gin.Context) {
+	u, err := h.repository.Find(getIDParam(c, "userID"))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if status, err := h.sendPasswordResetMail(u); err != nil {
+		c.JSON(status, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// otherUserID returns an id of the user in path params.
+// an administrator can not change the own account, so that at least one administrator remains.
+func (h UserHandler) otherUserID(c *gin.Context) (uint, bool) {
+	uid := getIDParam(c, "userID")
+
+	if uid == currentUserID(c) {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorOwnAccount)})
+		return 0, false
+	}
+
+	return uid, true
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func expectAdmin(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `users`")).
+		WithArgs(entity.RoleAdmin).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)))
+}
+
+func TestRequireAdminShouldReturnsStatusForbiddenWhenUserIsNotAdmin(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/admin/users", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), h.RequireAdmin())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `users`")).
+		WithArgs(entity.RoleAdmin).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.GET("/admin/users", h.IndexUsers)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 403)
+	assert.Equal(t, ErrorAdminRequired, res["errors"][0].Text)
+}
+
+func TestIndexUsersHandlerShouldReturnsStatusOKWithUsersAndTotal(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/admin/users?q=gopher&page=2", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), h.RequireAdmin())
+	expectAdmin(mock)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `users`")).
+		WithArgs("%gopher%", "%gopher%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(51))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE (name LIKE ? OR email LIKE ?) ORDER BY `id` LIMIT 50 OFFSET 50")).
+		WithArgs("%gopher%", "%gopher%").
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "email", "role"}).
+				AddRow(uint(51), "gopher", "gopher@sample.com", entity.RoleUser))
+
+	r.GET("/admin/users", h.IndexUsers)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := struct {
+		Users []map[string]interface{} `json:"users"`
+		Total int                      `json:"total"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, 51, res.Total)
+	assert.Len(t, res.Users, 1)
+	assert.Equal(t, "gopher@sample.com", res.Users[0]["email"])
+	assert.Equal(t, entity.RoleUser, res.Users[0]["role"])
+	assert.Nil(t, res.Users[0]["disabled_at"])
+}
+
+func TestDisableUserHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/admin/user/2/disable", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext(), h.RequireAdmin())
+	expectAdmin(mock)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(2)))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `users` WHERE (role = ? AND disabled_at IS NULL) FOR UPDATE")).
+		WithArgs(entity.RoleAdmin).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `disabled_at` = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions` WHERE (user_id = ?)")).
+		WithArgs(uint(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `access_tokens` WHERE (user_id = ?)")).
+		WithArgs(uint(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	r.PATCH("/admin/user/:userID/disable", h.DisableUser)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestDisableUserHandlerShouldReturnsStatusBadRequestWhenTargetIsOwnAccount(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/admin/user/1/disable", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext(), h.RequireAdmin())
+	expectAdmin(mock)
+
+	r.PATCH("/admin/user/:userID/disable", h.DisableUser)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, ErrorOwnAccount, res["errors"][0].Text)
+}
+
+func TestUnlockUserHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/admin/user/2/lock", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext(), h.RequireAdmin())
+	expectAdmin(mock)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(uint(2), "gopher@sample.com"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sign_in_throttles` WHERE (identifier = ?)")).
+		WithArgs("email:gopher@sample.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.DELETE("/admin/user/:userID/lock", h.UnlockUser)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestUpdateUserRoleHandlerShouldReturnsStatusBadRequestWithInvalidRole(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(userRoleParams{Role: "owner"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/admin/user/2/role", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext(), h.RequireAdmin())
+	expectAdmin(mock)
+
+	r.PATCH("/admin/user/:userID/role", h.UpdateUserRole)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, validator.ErrorOneOf("権限", "user, admin"), res["errors"][0].Text)
+}
@@ -1,12 +1,20 @@
 package handler
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
 	"local.packages/repository"
+	"local.packages/validator"
 )
 
+type backgroundImageParams struct {
+	URL   string `json:"url"`
+	Theme string `json:"theme"`
+}
+
 // BackgroundImageHandler ...
 type BackgroundImageHandler struct {
 	repository *repository.BackgroundImageRepository
@@ -25,3 +33,71 @@ func (h BackgroundImageHandler) IndexBackgroundImage(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"background_images": bs})
 }
+
+// CreateBackgroundImage call a function that create a new record to background_images table.
+// if creation was successful, returns status 201 and instance of BackgroundImage as http response.
+// if creation was failure, returns status 400 and error with messages.
+func (h BackgroundImageHandler) CreateBackgroundImage(c *gin.Context) {
+	var p backgroundImageParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		log.Printf("fail to bind JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	b, err := h.repository.Create(p.URL, p.Theme)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"background_image": b})
+}
+
+// UpdateBackgroundImage call a function that update a record in background_images table.
+// if update was successful, returns status 200 and updated instance of BackgroundImage as http response.
+// if update was failure, returns status 400 and error with messages.
+func (h BackgroundImageHandler) UpdateBackgroundImage(c *gin.Context) {
+	b, err := h.repository.Find(getIDParam(c, "backgroundImageID"))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	var p backgroundImageParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		log.Printf("fail to bind JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	if err := h.repository.Update(b, p.URL, p.Theme); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"background_image": b})
+}
+
+// DeleteBackgroundImage call a function that delete a record from background_images table.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and errors with message.
+func (h BackgroundImageHandler) DeleteBackgroundImage(c *gin.Context) {
+	b, err := h.repository.Find(getIDParam(c, "backgroundImageID"))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.Delete(b); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
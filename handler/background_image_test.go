@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -56,3 +57,48 @@ func TestShouldReturnsBackgroundImageInstancesAndStatusOKAsHTTPResponse(t *testi
 	assert.Equal(t, res["background_images"][0].URL, backgroundImageURL)
 	assert.Equal(t, res["background_images"][0].Theme, backgroundImageTheme)
 }
+
+func TestCreateBackgroundImageHandlerShouldReturnsStatusCreatedWithBackgroundImage(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	bh := NewBackgroundImageHandler(repository.NewBackgroundImageRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	url := "https://example.com/image.jpg"
+	theme := "light"
+
+	b, _ := json.Marshal(backgroundImageParams{URL: url, Theme: theme})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/admin/background_image", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), uh.RequireAdmin())
+	expectAdmin(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `background_images`")).
+		WithArgs(url, theme).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	r.POST("/admin/background_image", bh.CreateBackgroundImage)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]entity.BackgroundImage{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 201)
+	assert.Equal(t, uint(1), res["background_image"].ID)
+	assert.Equal(t, url, res["background_image"].URL)
+}
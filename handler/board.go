@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"local.packages/entity"
 	"local.packages/repository"
 	"local.packages/validator"
 )
@@ -13,6 +14,11 @@ import (
 type boardParams struct {
 	Name              string `json:"name" form:"name"`
 	BackgroundImageID uint   `json:"background_image_id"`
+	WorkspaceID       uint   `json:"workspace_id" form:"workspace_id"`
+}
+
+type boardWorkspaceParams struct {
+	WorkspaceID *uint `json:"workspace_id" binding:"required"`
 }
 
 // BoardHandler ...
@@ -26,6 +32,7 @@ func NewBoardHandler(r *repository.BoardRepository) *BoardHandler {
 }
 
 // CreateBoard call a function that create a new record to boards table.
+// if a workspace id is given, the board is created in the workspace that the login user is a member of.
 // if creation was successful, returns status 201 and instance of Board as http response.
 // if creation was failure, returns status 400 and error with messages.
 func (h BoardHandler) CreateBoard(c *gin.Context) {
@@ -37,29 +44,42 @@ func (h BoardHandler) CreateBoard(c *gin.Context) {
 		return
 	}
 
-	b, err := h.repository.Create(p.Name, p.BackgroundImageID, currentUserID(c))
+	uid := currentUserID(c)
+	b, err := h.repository.Create(p.Name, p.BackgroundImageID, uid, p.WorkspaceID)
 
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(b.ID, uid, entity.ActivityCreate, nil, entity.ActivityValues{"name": b.Name})
+
 	c.JSON(http.StatusCreated, gin.H{"board": b})
 }
 
 // UpdateBoard call a function that update a record in boards table.
-// if update was successful, returns status 200 and updated instance of Board as http response.
+// watchers of the board are notified of the update.
+// the board is updated only if its version matches an If-Match header, or if the header is not given.
+// if update was successful, returns status 200 and updated instance of Board with its ETag as http response.
+// if the board was updated by another request, returns status 412 and the current instance of Board.
 // if update was failure, returns status 400 and error with messages.
 func (h BoardHandler) UpdateBoard(c *gin.Context) {
 	id := getIDParam(c, "boardID")
-	b, err := h.repository.FindWithoutPreload(id, currentUserID(c))
+	uid := currentUserID(c)
+	b, err := h.repository.FindWithoutPreload(id, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id")
+		log.Println("uid is not a member who can edit the board")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	if !matchesIfMatch(c, b.Version) {
+		log.Println("board was updated after the client got it")
+		preconditionFailed(c, "board", b.Version, b)
+		return
+	}
+
 	var p boardParams
 
 	if err := c.ShouldBindJSON(&p); err != nil {
@@ -68,17 +88,101 @@ func (h BoardHandler) UpdateBoard(c *gin.Context) {
 		return
 	}
 
+	before := b.Name
+
 	if err := h.repository.Update(b, p.Name); err != nil {
+		if isConflict(err) {
+			if b, err = h.repository.FindWithoutPreload(id, uid); err != nil {
+				log.Println("board was deleted by another request")
+				c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+				return
+			}
+
+			preconditionFailed(c, "board", b.Version, b)
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	h.repository.RecordActivity(b.ID, uid, entity.ActivityUpdate, entity.ActivityValues{"name": before}, entity.ActivityValues{"name": b.Name})
+
+	h.repository.NotifyWatchers(b.ID, entity.NotificationBoardUpdated, uid)
+
+	c.Header("ETag", etag(b.Version))
+	c.JSON(http.StatusOK, gin.H{"board": b})
+}
+
+// UpdateBoardWorkspace call a function that moves a board into a workspace, or out of its workspace if a workspace id is zero.
+// only an owner of the board who is a member of the workspace can move it into the workspace.
+// the board is moved only if its version matches an If-Match header, or if the header is not given.
+// if update was successful, returns status 200 and updated instance of Board with its ETag as http response.
+// if the board was updated by another request, returns status 412 and the current instance of Board.
+// if update was failure, returns status 400 and error with messages.
+func (h BoardHandler) UpdateBoardWorkspace(c *gin.Context) {
+	id := getIDParam(c, "boardID")
+	uid := currentUserID(c)
+	b, err := h.repository.FindOwned(id, uid)
+
+	if err != nil {
+		log.Println("uid is not an owner of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if !matchesIfMatch(c, b.Version) {
+		log.Println("board was updated after the client got it")
+		preconditionFailed(c, "board", b.Version, b)
+		return
+	}
+
+	var p boardWorkspaceParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		log.Printf("fail to bind JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	before := b.WorkspaceID
+
+	if err := h.repository.UpdateWorkspace(b, uid, *p.WorkspaceID); err != nil {
+		if isConflict(err) {
+			if b, err = h.repository.FindOwned(id, uid); err != nil {
+				log.Println("board was deleted by another request")
+				c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+				return
+			}
+
+			preconditionFailed(c, "board", b.Version, b)
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(b.ID, uid, entity.ActivityUpdate, entity.ActivityValues{"workspace_id": before}, entity.ActivityValues{"workspace_id": b.WorkspaceID})
+
+	h.repository.NotifyWatchers(b.ID, entity.NotificationBoardUpdated, uid)
+
+	c.Header("ETag", etag(b.Version))
 	c.JSON(http.StatusOK, gin.H{"board": b})
 }
 
 // IndexBoard returns status 200 and slice of Board instance as http response.
+// if a workspace id is given as query, returns only boards of the workspace.
 func (h BoardHandler) IndexBoard(c *gin.Context) {
-	bs := h.repository.GetAll(currentUserID(c))
+	var p boardParams
+
+	if err := c.ShouldBindQuery(&p); err != nil {
+		log.Printf("fail to bind query: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	bs := h.repository.GetAll(currentUserID(c), p.WorkspaceID)
 	c.JSON(http.StatusOK, gin.H{"boards": bs})
 }
 
@@ -89,7 +193,7 @@ func (h BoardHandler) ShowBoard(c *gin.Context) {
 	b, err := h.repository.Find(id, currentUserID(c))
 
 	if err != nil {
-		log.Println("uid does not match board.user_id")
+		log.Println("uid is not a member of the board")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -98,21 +202,53 @@ func (h BoardHandler) ShowBoard(c *gin.Context) {
 }
 
 // DeleteBoard call a function that delete a record from boards table.
+// only an owner of the board can delete it, and watchers of the board are notified of the deletion.
+// when an If-Match header is given, the board is deleted only if its version matches it.
 // if deletion was successful, returns status 200.
+// if the board was updated by another request, returns status 412 and the current instance of Board.
 // if deletion was failure, returns status 400 and errors with message.
 func (h BoardHandler) DeleteBoard(c *gin.Context) {
 	id := getIDParam(c, "boardID")
+	uid := currentUserID(c)
+	b, err := h.repository.FindOwned(id, uid)
+
+	if err != nil {
+		log.Println("uid is not an owner of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if !matchesIfMatch(c, b.Version) {
+		log.Println("board was updated after the client got it")
+		preconditionFailed(c, "board", b.Version, b)
+		return
+	}
+
+	if err := h.repository.Delete(id, uid, ifMatchVersion(c, b.Version)); err != nil {
+		if isConflict(err) {
+			if b, err = h.repository.FindOwned(id, uid); err != nil {
+				log.Println("board was deleted by another request")
+				c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+				return
+			}
+
+			preconditionFailed(c, "board", b.Version, b)
+			return
+		}
 
-	if err := h.repository.Delete(id, currentUserID(c)); err != nil {
 		log.Printf("fail to delete a board: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(id, uid, entity.ActivityDelete, entity.ActivityValues{"name": b.Name}, nil)
+	h.repository.NotifyWatchers(id, entity.NotificationBoardDeleted, uid)
+
 	c.Status(http.StatusOK)
 }
 
 // SearchBoard returns status 200 and slice of Board ids as http response.
+// if a workspace id is given as query, searches only boards of the workspace.
 func (h BoardHandler) SearchBoard(c *gin.Context) {
 	var p boardParams
 
@@ -122,7 +258,7 @@ func (h BoardHandler) SearchBoard(c *gin.Context) {
 		return
 	}
 
-	ids := h.repository.Search(p.Name, currentUserID(c))
+	ids := h.repository.Search(p.Name, currentUserID(c), p.WorkspaceID)
 
 	if len(ids) == 0 {
 		ids = make([]uint, 0)
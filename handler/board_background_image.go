@@ -30,7 +30,7 @@ func (h BoardBackgroundImageHandler) UpdateBoardBackgroundImage(c *gin.Context)
 	b, err := h.repository.Find(bid, currentUserID(c))
 
 	if err != nil {
-		log.Println("uid does not match board.user_id and current user")
+		log.Println("uid is not a member who can edit the board")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -40,7 +40,7 @@ func TestShouldReturnStatusOKWhenSucceedUpdateBoardBackgroundImage(t *testing.T)
 		SELECT 'board_background_images'.*
 		FROM 'board_background_images'
 		Join boards ON board_background_images.board_id = boards.id
-		WHERE (boards.user_id = ?) AND (boards.id = ?)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND (boards.id = ?)
 		ORDER BY 'board_background_images'.'board_id' ASC
 		LIMIT 1`)
 
@@ -95,7 +95,7 @@ func TestShouldReturnStatusBadRequestWhenFailedUpdateBoardBackgroundImage(t *tes
 		SELECT 'board_background_images'.*
 		FROM 'board_background_images'
 		Join boards ON board_background_images.board_id = boards.id
-		WHERE (boards.user_id = ?) AND (boards.id = ?)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND (boards.id = ?)
 		ORDER BY 'board_background_images'.'board_id' ASC
 		LIMIT 1`)
 
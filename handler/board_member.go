@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/entity"
+	"local.packages/repository"
+	"local.packages/validator"
+)
+
+type boardMemberParams struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
+
+type boardMemberRoleParams struct {
+	Role string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
+
+// BoardMemberHandler ...
+type BoardMemberHandler struct {
+	repository *repository.BoardMemberRepository
+}
+
+// NewBoardMemberHandler is constructor for BoardMemberHandler.
+func NewBoardMemberHandler(r *repository.BoardMemberRepository) *BoardMemberHandler {
+	return &BoardMemberHandler{repository: r}
+}
+
+func boardMemberResponse(m *entity.BoardMember) gin.H {
+	r := gin.H{
+		"user_id": m.UserID,
+		"role":    m.Role,
+	}
+
+	if m.User != nil {
+		r["name"] = m.User.Name
+		r["email"] = m.User.Email
+	}
+
+	return r
+}
+
+// IndexBoardMembers returns status 200 and members of a board as http response.
+func (h BoardMemberHandler) IndexBoardMembers(c *gin.Context) {
+	ms := h.repository.GetAll(getIDParam(c, "boardID"), currentUserID(c))
+
+	r := []gin.H{}
+
+	for i := range *ms {
+		r = append(r, boardMemberResponse(&(*ms)[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": r})
+}
+
+// CreateBoardMember call a function that add the user who has an email address to members of a board.
+// only an owner of the board can add members.
+// if creation was successful, returns status 201 and the member as http response.
+// if creation was failure, returns status 400 and error with messages.
+func (h BoardMemberHandler) CreateBoardMember(c *gin.Context) {
+	var p boardMemberParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	bid := getIDParam(c, "boardID")
+
+	if err := h.repository.ValidateUID(bid, currentUserID(c)); err != nil {
+		log.Println("uid is not an owner of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	m, err := h.repository.Create(bid, p.Email, p.Role)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"member": boardMemberResponse(m)})
+}
+
+// UpdateBoardMember call a function that update a role of a member of a board.
+// only an owner of the board can change roles.
+// if update was successful, returns status 200.
+// if update was failure, returns status 400 and error with messages.
+func (h BoardMemberHandler) UpdateBoardMember(c *gin.Context) {
+	var p boardMemberRoleParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	bid := getIDParam(c, "boardID")
+
+	if err := h.repository.ValidateUID(bid, currentUserID(c)); err != nil {
+		log.Println("uid is not an owner of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	m, err := h.repository.Find(bid, getIDParam(c, "userID"))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.UpdateRole(m, p.Role); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// DeleteBoardMember call a function that remove a member from a board.
+// an owner of the board can remove any member, and every member can leave the board by oneself.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and error with messages.
+func (h BoardMemberHandler) DeleteBoardMember(c *gin.Context) {
+	bid := getIDParam(c, "boardID")
+	uid := getIDParam(c, "userID")
+
+	if uid != currentUserID(c) {
+		if err := h.repository.ValidateUID(bid, currentUserID(c)); err != nil {
+			log.Println("uid is not an owner of the board")
+			c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+			return
+		}
+	}
+
+	m, err := h.repository.Find(bid, uid)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.Delete(m); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestCreateBoardMemberHandlerShouldReturnsStatusCreatedWithMember(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	mh := NewBoardMemberHandler(repository.NewBoardMemberRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(boardMemberParams{Email: "gopher@sample.com", Role: "viewer"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/board/2/member", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WithArgs(uint(1), "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(2)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(uint(3), "gopher", "gopher@sample.com"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `board_members`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.POST("/board/:boardID/member", mh.CreateBoardMember)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 201)
+	assert.Equal(t, float64(3), res["member"]["user_id"])
+	assert.Equal(t, "viewer", res["member"]["role"])
+	assert.Equal(t, "gopher@sample.com", res["member"]["email"])
+}
+
+func TestCreateBoardMemberHandlerShouldReturnsStatusBadRequestWhenUserIsNotOwner(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	mh := NewBoardMemberHandler(repository.NewBoardMemberRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(boardMemberParams{Email: "gopher@sample.com", Role: "owner"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/board/2/member", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WithArgs(uint(1), "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.POST("/board/:boardID/member", mh.CreateBoardMember)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, repository.ErrorInvalidSession, res["errors"][0].Text)
+}
+
+func TestDeleteBoardMemberHandlerShouldLetMemberLeaveBoard(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	mh := NewBoardMemberHandler(repository.NewBoardMemberRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/board/2/member/1", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `board_members` WHERE (board_id = ? AND user_id = ?)")).
+		WithArgs(uint(2), uint(1)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"board_id", "user_id", "role"}).
+				AddRow(uint(2), uint(1), "viewer"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM `board_members`")).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(uint(3)))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `board_members`")).
+		WithArgs(uint(2), uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `card_assignees`")).
+		WithArgs(uint(1), uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	r.DELETE("/board/:boardID/member/:userID", mh.DeleteBoardMember)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
@@ -51,8 +51,8 @@ func TestCreateBoardHandlerShouldReturnsStatusCreatedWithBoardData(t *testing.T)
 	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
 
 	insertBoardQuery := utils.ReplaceQuotationForQuery(`
-		INSERT INTO 'boards' ('created_at','updated_at','deleted_at','name','user_id')
-		VALUES (?,?,?,?,?)`)
+		INSERT INTO 'boards' ('created_at','updated_at','deleted_at','name','user_id','workspace_id','version')
+		VALUES (?,?,?,?,?,?,?)`)
 
 	insertBackgroundImageQuery := utils.ReplaceQuotationForQuery(`
 		INSERT INTO 'board_background_images' ('board_id','background_image_id')
@@ -65,6 +65,9 @@ func TestCreateBoardHandlerShouldReturnsStatusCreatedWithBoardData(t *testing.T)
 	mock.ExpectExec(regexp.QuoteMeta(insertBackgroundImageQuery)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `board_members`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
 	mock.ExpectCommit()
 
 	r.POST("/board", bh.CreateBoard)
@@ -153,13 +156,16 @@ func TestUpdateBoardHandlerShouldReturnsStatusOKWithBoardData(t *testing.T) {
 
 	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id FROM `boards`"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id, workspace_id, version FROM `boards`"))
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta("UPDATE `boards` SET")).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
 
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT watches.user_id FROM `watches`")).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
 	r.PATCH("/board/:boardID", bh.UpdateBoard)
 	r.ServeHTTP(w, req)
 
@@ -229,7 +235,7 @@ func TestShouldFailureUpdateBoardHandler(t *testing.T) {
 			utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
 
 			if tc.testName == "when without name" {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id FROM `boards`"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id, workspace_id, version FROM `boards`"))
 				mock.ExpectBegin()
 			}
 
@@ -252,6 +258,100 @@ func TestShouldFailureUpdateBoardHandler(t *testing.T) {
 	}
 }
 
+func TestUpdateBoardWorkspaceHandlerShouldReturnsStatusOKWithBoardData(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	bh := NewBoardHandler(repository.NewBoardRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/board/1/workspace", bytes.NewReader([]byte(`{"workspace_id":3}`)))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id, workspace_id, version FROM `boards`")).
+		WithArgs(uint(1), "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "user_id", "version"}).AddRow(uint(1), "board", uint(1), uint(2)))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `workspace_members`")).
+		WithArgs(uint(3), uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id", "user_id", "role"}).AddRow(uint(3), uint(1), "member"))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `boards` SET")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO board_members")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activities`")).
+		WithArgs(utils.AnyTime{}, uint(1), nil, uint(1), "update", "board", uint(1), `{"workspace_id":null}`, `{"workspace_id":3}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT watches.user_id FROM `watches`")).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+	r.PATCH("/board/:boardID/workspace", bh.UpdateBoardWorkspace)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]entity.Board{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	assert.Equal(t, uint(3), *res["board"].WorkspaceID)
+}
+
+func TestUpdateBoardWorkspaceHandlerShouldReturnsStatusBadRequestWhenUserIsNotOwner(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	bh := NewBoardHandler(repository.NewBoardRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/board/1/workspace", bytes.NewReader([]byte(`{"workspace_id":0}`)))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id, workspace_id, version FROM `boards`")).
+		WithArgs(uint(1), "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.PATCH("/board/:boardID/workspace", bh.UpdateBoardWorkspace)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorRecordNotFound)
+}
+
 func TestIndexBoardHandlerShouldReturnsStatusOKWithBoardData(t *testing.T) {
 	db, mock := utils.NewDBMock(t)
 	defer db.Close()
@@ -277,9 +377,9 @@ func TestIndexBoardHandlerShouldReturnsStatusOKWithBoardData(t *testing.T) {
 	}
 
 	boardQuery := utils.ReplaceQuotationForQuery(`
-		SELECT id, updated_at, name, user_id
+		SELECT id, updated_at, name, user_id, workspace_id, version
 		FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?))`)
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?))))`)
 
 	backgroundImageQuery := utils.ReplaceQuotationForQuery(`
 		SELECT *
@@ -334,7 +434,7 @@ func TestShowBoardHandlerShouldReturnsStatusOKWithBoardData(t *testing.T) {
 
 	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id FROM `boards`")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id, workspace_id, version FROM `boards`")).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)))
 
 	r.GET("/board/:boardID", bh.ShowBoard)
@@ -368,7 +468,7 @@ func TestShowBoardHandlerShouldReturnsStatusBadRequestWhenRecordNotFound(t *test
 
 	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id FROM `boards`")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id, workspace_id, version FROM `boards`")).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	r.GET("/board/:boardID", bh.ShowBoard)
@@ -402,12 +502,37 @@ func TestDeleteBoardHandlerShouldReturnsStatusOK(t *testing.T) {
 
 	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
 
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id, workspace_id, version FROM `boards` WHERE `boards`.`deleted_at` IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?))) AND (`boards`.`id` = 1))")).
+		WithArgs(uint(1), "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "user_id", "version"}).AddRow(uint(1), "sample board", uint(1), uint(2)))
+
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta("UPDATE `boards` SET")).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
 
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activities`")).
+		WithArgs(utils.AnyTime{}, uint(1), nil, uint(1), "delete", "board", uint(1), `{"name":"sample board"}`, "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT watches.user_id FROM `watches` Join board_members ON board_members.board_id = watches.target_id AND board_members.user_id = watches.user_id WHERE (watches.target_type = ? AND watches.target_id = ?)")).
+		WithArgs("board", uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(uint(3)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM `boards` WHERE (`boards`.`id` = 1)")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uint(1), "sample board"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `notifications`")).
+		WithArgs(utils.AnyTime{}, uint(3), uint(1), "board_deleted", uint(1), nil, nil, "sample board", nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
 	r.DELETE("/board/:boardID", bh.DeleteBoard)
 	r.ServeHTTP(w, req)
 
@@ -429,14 +554,13 @@ func TestDeleteBoardHandlerShouldReturnsStatusBadRequest(t *testing.T) {
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodDelete, "/board/1", nil)
+	req.Header.Set("If-Match", `"2"`)
 
 	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
 
-	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE `boards` SET")).
-		WillReturnResult(sqlmock.NewResult(1, 0))
-
-	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id, workspace_id, version FROM `boards` WHERE `boards`.`deleted_at` IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?))) AND (`boards`.`id` = 1))")).
+		WithArgs(uint(1), "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
 	r.DELETE("/board/:boardID", bh.DeleteBoard)
 	r.ServeHTTP(w, req)
@@ -452,7 +576,7 @@ func TestDeleteBoardHandlerShouldReturnsStatusBadRequest(t *testing.T) {
 	}
 
 	assert.Equal(t, w.Code, 400)
-	assert.Equal(t, res["errors"][0].Text, repository.ErrorInvalidRequest)
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorRecordNotFound)
 }
 
 func TestSearchBoardHandlerShouldReturnsStatusOKWithBoardIDs(t *testing.T) {
@@ -476,7 +600,7 @@ func TestSearchBoardHandlerShouldReturnsStatusOKWithBoardIDs(t *testing.T) {
 
 	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
 
-	query := "SELECT id FROM `boards` WHERE `boards`.`deleted_at` IS NULL AND ((user_id = ?) AND (name LIKE ?))"
+	query := "SELECT id FROM `boards` WHERE `boards`.`deleted_at` IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?))) AND (name LIKE ?))"
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).
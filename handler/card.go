@@ -3,16 +3,21 @@ package handler
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"local.packages/entity"
 	"local.packages/repository"
 	"local.packages/validator"
 )
 
 type cardParams struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	StartAt     *time.Time `json:"start_at"`
+	DueAt       *time.Time `json:"due_at"`
+	Completed   bool       `json:"completed"`
 }
 
 // CardHandler ...
@@ -38,9 +43,10 @@ func (h CardHandler) CreateCard(c *gin.Context) {
 	}
 
 	lid := getIDParam(c, "listID")
+	uid := currentUserID(c)
 
-	if err := h.repository.ValidateUID(lid, currentUserID(c)); err != nil {
-		log.Println("uid does not match board.user_id associated with the card")
+	if err := h.repository.ValidateUID(lid, uid); err != nil {
+		log.Println("uid is not a member who can edit the board associated with the card")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -52,22 +58,35 @@ func (h CardHandler) CreateCard(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(ca, uid, entity.ActivityCreate, nil, entity.ActivityValues{"title": ca.Title, "list_id": ca.ListID})
+
 	c.JSON(http.StatusCreated, gin.H{"card": ca})
 }
 
 // UpdateCard call a function that update a record in cards table.
-// if update was successful, returns status 200 and updated instance of Card as http response.
+// an attribute is one of title, description, start_at, due_at and completed, and a date is cleared by null.
+// watchers of the card are notified of the update.
+// the card is updated only if its version matches an If-Match header, or if the header is not given.
+// if update was successful, returns status 200 and updated instance of Card with its ETag as http response.
+// if the card was updated by another request, returns status 412 and the current instance of Card.
 // if update was failure, returns status 400 and error with messages.
 func (h CardHandler) UpdateCard(c *gin.Context) {
 	id := getIDParam(c, "cardID")
-	ca, err := h.repository.Find(id, currentUserID(c))
+	uid := currentUserID(c)
+	ca, err := h.repository.Find(id, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the card")
+		log.Println("uid is not a member who can edit the board associated with the card")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	if !matchesIfMatch(c, ca.Version) {
+		log.Println("card was updated after the client got it")
+		preconditionFailed(c, "card", ca.Version, ca)
+		return
+	}
+
 	var p cardParams
 
 	if err := c.ShouldBindJSON(&p); err != nil {
@@ -76,25 +95,89 @@ func (h CardHandler) UpdateCard(c *gin.Context) {
 		return
 	}
 
+	var before, after entity.ActivityValues
+
 	switch c.Param("attribute") {
 	case "title":
+		before = entity.ActivityValues{"title": ca.Title}
+
 		if err := h.repository.UpdateTitle(ca, p.Title); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+			h.updateFailed(c, id, uid, err)
 			return
 		}
+
+		after = entity.ActivityValues{"title": ca.Title}
 	case "description":
+		before = entity.ActivityValues{"description": ca.Description}
+
 		if err := h.repository.UpdateDescription(ca, p.Description); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+			h.updateFailed(c, id, uid, err)
+			return
+		}
+
+		after = entity.ActivityValues{"description": ca.Description}
+
+		if ms, err := h.repository.SaveMentions(ca, uid); err == nil {
+			notifyMentions(ms, ca.Description)
+		}
+	case "start_at":
+		before = entity.ActivityValues{"start_at": ca.StartAt}
+
+		if err := h.repository.UpdateStartAt(ca, p.StartAt); err != nil {
+			h.updateFailed(c, id, uid, err)
+			return
+		}
+
+		after = entity.ActivityValues{"start_at": ca.StartAt}
+	case "due_at":
+		before = entity.ActivityValues{"due_at": ca.DueAt}
+
+		if err := h.repository.UpdateDueAt(ca, p.DueAt); err != nil {
+			h.updateFailed(c, id, uid, err)
+			return
+		}
+
+		after = entity.ActivityValues{"due_at": ca.DueAt}
+	case "completed":
+		before = entity.ActivityValues{"completed": ca.Completed}
+
+		if err := h.repository.UpdateCompleted(ca, p.Completed); err != nil {
+			h.updateFailed(c, id, uid, err)
 			return
 		}
+
+		after = entity.ActivityValues{"completed": ca.Completed}
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
 		return
 	}
 
+	h.repository.RecordActivity(ca, uid, entity.ActivityUpdate, before, after)
+	h.repository.NotifyWatchers(ca, entity.NotificationCardUpdated, uid)
+
+	c.Header("ETag", etag(ca.Version))
 	c.JSON(http.StatusOK, gin.H{"card": ca})
 }
 
+// updateFailed returns status 412 and the current instance of Card when the card was updated by another request.
+// otherwise returns status 400 and errors with message.
+func (h CardHandler) updateFailed(c *gin.Context, id, uid uint, err []validator.ValidationError) {
+	if !isConflict(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	ca, err := h.repository.Find(id, uid)
+
+	if err != nil {
+		log.Println("card was deleted by another request")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	preconditionFailed(c, "card", ca.Version, ca)
+}
+
 // UpdateCardIndex call a function that update cards order.
 // if update was successful, returns status 200.
 // if update was failure, returns status 400 and error with messages.
@@ -110,7 +193,7 @@ func (h CardHandler) UpdateCardIndex(c *gin.Context) {
 		return
 	}
 
-	if err := h.repository.UpdateIndex(ps); err != nil {
+	if err := h.repository.UpdateIndex(ps, currentUserID(c)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -119,26 +202,73 @@ func (h CardHandler) UpdateCardIndex(c *gin.Context) {
 }
 
 // DeleteCard call a function that delete a record from cards table.
+// watchers of the card are notified of the deletion.
+// when an If-Match header is given, the card is deleted only if its version matches it.
 // if deletion was successful, returns status 200.
+// if the card was updated by another request, returns status 412 and the current instance of Card.
 // if deletion was failure, returns status 400 and errors with message.
 func (h CardHandler) DeleteCard(c *gin.Context) {
 	id := getIDParam(c, "cardID")
-	ca, err := h.repository.Find(id, currentUserID(c))
+	uid := currentUserID(c)
+	ca, err := h.repository.Find(id, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the card")
+		log.Println("uid is not a member who can edit the board associated with the card")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
-	if err := h.repository.Delete(ca); err != nil {
+	if !matchesIfMatch(c, ca.Version) {
+		log.Println("card was updated after the client got it")
+		preconditionFailed(c, "card", ca.Version, ca)
+		return
+	}
+
+	if err := h.repository.Delete(ca, ifMatchVersion(c, ca.Version)); err != nil {
+		if isConflict(err) {
+			if ca, err = h.repository.Find(id, uid); err != nil {
+				log.Println("card was deleted by another request")
+				c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+				return
+			}
+
+			preconditionFailed(c, "card", ca.Version, ca)
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(ca, uid, entity.ActivityDelete, entity.ActivityValues{"title": ca.Title, "list_id": ca.ListID}, nil)
+	h.repository.NotifyWatchers(ca, entity.NotificationCardDeleted, uid)
+
 	c.Status(http.StatusOK)
 }
 
+// RestoreCard call a function that restore a deleted card to the end of its list.
+// if restoration was successful, returns status 200 and restored instance of Card as http response.
+// if restoration was failure, returns status 400 and errors with message.
+func (h CardHandler) RestoreCard(c *gin.Context) {
+	uid := currentUserID(c)
+	ca, err := h.repository.FindDeleted(getIDParam(c, "cardID"), uid)
+
+	if err != nil {
+		log.Println("uid is not a member who can edit the board associated with the card, or the card is not deleted")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.Restore(ca); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	h.repository.RecordActivity(ca, uid, entity.ActivityRestore, nil, entity.ActivityValues{"title": ca.Title, "list_id": ca.ListID})
+
+	c.JSON(http.StatusOK, gin.H{"card": ca})
+}
+
 // SearchCard returns status 200 and slice of Card ids as http response.
 func (h CardHandler) SearchCard(c *gin.Context) {
 	p := struct {
@@ -159,3 +289,8 @@ func (h CardHandler) SearchCard(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"card_ids": ids})
 }
+
+// IndexDueCards returns status 200 and cards that are overdue or due within a week across boards of the login user as http response.
+func (h CardHandler) IndexDueCards(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"cards": h.repository.GetDueCards(currentUserID(c), time.Now())})
+}
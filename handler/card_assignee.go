@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/repository"
+	"local.packages/validator"
+)
+
+type cardAssigneeParams struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
+
+// CardAssigneeHandler ...
+type CardAssigneeHandler struct {
+	repository *repository.CardAssigneeRepository
+}
+
+// NewCardAssigneeHandler is constructor for CardAssigneeHandler.
+func NewCardAssigneeHandler(r *repository.CardAssigneeRepository) *CardAssigneeHandler {
+	return &CardAssigneeHandler{repository: r}
+}
+
+// CreateCardAssignee call a function that create a new record to card_assignees table.
+// the assignee must be a member of the board associated with the card, and is notified of the assignment.
+// if creation was successful, returns status 201 and instance of CardAssignee as http response.
+// if creation was failure, returns status 400 and error with messages.
+func (h *CardAssigneeHandler) CreateCardAssignee(c *gin.Context) {
+	var p cardAssigneeParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		log.Printf("fail to bind JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	cid := getIDParam(c, "cardID")
+	uid := currentUserID(c)
+
+	if err := h.repository.ValidateUID(cid, p.UserID, uid); err != nil {
+		log.Println("uid is not a member who can edit the board associated with the card, or assignee is not a member of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	ca, err := h.repository.Create(cid, p.UserID, uid)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"assignee": ca})
+}
+
+// DeleteCardAssignee call a function that delete a record from card_assignees table.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and errors with message.
+func (h *CardAssigneeHandler) DeleteCardAssignee(c *gin.Context) {
+	ca, err := h.repository.Find(getIDParam(c, "cardID"), getIDParam(c, "userID"), currentUserID(c))
+
+	if err != nil {
+		log.Println("uid is not a member who can edit the board associated with the card")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.Delete(ca); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// IndexAssignedCards returns status 200 and cards assigned to the login user across boards as http response.
+func (h *CardAssigneeHandler) IndexAssignedCards(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"cards": h.repository.GetAssignedCards(currentUserID(c))})
+}
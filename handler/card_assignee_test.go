@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestCreateCardAssigneeHandlerShouldReturnsStatusCreatedWithAssignee(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCardAssigneeHandler(repository.NewCardAssigneeRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(cardAssigneeParams{UserID: uint(3)})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/card/4/assignee", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT boards.id FROM 'boards'
+		Join lists ON boards.id = lists.board_id
+		Join cards ON lists.id = cards.list_id
+		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?)
+		AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ?))
+		AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(uint(4), uint(3), uint(1), "owner", "editor").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(2)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `card_assignees` (`card_id`,`user_id`,`created_at`) VALUES (?,?,?)")).
+		WithArgs(uint(4), uint(3), utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT cards.title, cards.list_id, lists.board_id FROM `cards` Join lists ON lists.id = cards.list_id WHERE (cards.id = ?)")).
+		WithArgs(uint(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "list_id", "board_id"}).AddRow("sample title", uint(5), uint(2)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `notifications` (`created_at`,`user_id`,`actor_id`,`type`,`board_id`,`list_id`,`card_id`,`title`,`read_at`) VALUES (?,?,?,?,?,?,?,?,?)")).
+		WithArgs(utils.AnyTime{}, uint(3), uint(1), "assigned", uint(2), uint(5), uint(4), "sample title", nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	r.POST("/card/:cardID/assignee", ch.CreateCardAssignee)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 201)
+	assert.Equal(t, float64(3), res["assignee"]["user_id"])
+}
+
+func TestCreateCardAssigneeHandlerShouldReturnsStatusBadRequestWhenAssigneeIsNotMember(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCardAssigneeHandler(repository.NewCardAssigneeRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(cardAssigneeParams{UserID: uint(3)})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/card/4/assignee", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT boards.id FROM `boards`")).
+		WithArgs(uint(4), uint(3), uint(1), "owner", "editor").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.POST("/card/:cardID/assignee", ch.CreateCardAssignee)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, repository.ErrorInvalidSession, res["errors"][0].Text)
+}
+
+func TestDeleteCardAssigneeHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCardAssigneeHandler(repository.NewCardAssigneeRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/card/4/assignee/3", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `card_assignees`.* FROM `card_assignees` Join cards ON card_assignees.card_id = cards.id")).
+		WithArgs(uint(1), "owner", "editor", uint(4), uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"card_id", "user_id"}).AddRow(uint(4), uint(3)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `card_assignees` WHERE (card_id = ? AND user_id = ?)")).
+		WithArgs(uint(4), uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.DELETE("/card/:cardID/assignee/:userID", ch.DeleteCardAssignee)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestIndexAssignedCardsHandlerShouldReturnsStatusOKWithCardsAcrossBoards(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCardAssigneeHandler(repository.NewCardAssigneeRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/me/cards", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT cards.id, cards.title, cards.list_id, lists.name AS list_name, boards.id AS board_id, boards.name AS board_name
+		FROM 'cards'
+		Join lists ON lists.id = cards.list_id
+		Join boards ON boards.id = lists.board_id
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?)))
+		AND (cards.id IN (SELECT card_id FROM card_assignees WHERE user_id = ?))
+		AND (cards.deleted_at IS NULL AND lists.deleted_at IS NULL AND boards.deleted_at IS NULL)
+		ORDER BY boards.id, lists.index, cards.index`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(uint(1), "owner", "editor", "viewer", uint(1)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "title", "list_id", "list_name", "board_id", "board_name"}).
+				AddRow(uint(4), "card", uint(3), "list", uint(2), "board").
+				AddRow(uint(7), "another card", uint(6), "list", uint(5), "another board"))
+
+	r.GET("/me/cards", ch.IndexAssignedCards)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]repository.AssignedCard{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, 2, len(res["cards"]))
+	assert.Equal(t, "another board", res["cards"][1].BoardName)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"local.packages/entity"
 	"local.packages/repository"
 	"local.packages/validator"
 )
@@ -37,9 +38,10 @@ func (h *CardLabelHandler) CreateCardLabel(c *gin.Context) {
 	}
 
 	cid := getIDParam(c, "cardID")
+	uid := currentUserID(c)
 
-	if err := h.repository.ValidateUID(p.LabelID, cid, currentUserID(c)); err != nil {
-		log.Println("uid does not match board.user_id associated with the card or label")
+	if err := h.repository.ValidateUID(p.LabelID, cid, uid); err != nil {
+		log.Println("uid is not a member who can edit the board associated with the card or label")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -51,6 +53,8 @@ func (h *CardLabelHandler) CreateCardLabel(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(&entity.CardLabel{CardID: cid, LabelID: p.LabelID}, uid, entity.ActivityCreate, nil, entity.ActivityValues{"name": l.Name, "color": l.Color})
+
 	c.JSON(http.StatusCreated, gin.H{"label": l})
 }
 
@@ -60,11 +64,12 @@ func (h *CardLabelHandler) CreateCardLabel(c *gin.Context) {
 func (h *CardLabelHandler) DeleteCardLabel(c *gin.Context) {
 	cid := getIDParam(c, "cardID")
 	lid := getIDParam(c, "labelID")
+	uid := currentUserID(c)
 
-	cl, err := h.repository.Find(lid, cid, currentUserID(c))
+	cl, err := h.repository.Find(lid, cid, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the card or label")
+		log.Println("uid is not a member who can edit the board associated with the card or label")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -74,5 +79,7 @@ func (h *CardLabelHandler) DeleteCardLabel(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(cl, uid, entity.ActivityDelete, entity.ActivityValues{"label_id": cl.LabelID}, nil)
+
 	c.Status(http.StatusOK)
 }
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -21,6 +22,7 @@ import (
 type cardRequestBody struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
+	Completed   bool   `json:"completed"`
 }
 
 func TestCreateCardHandlerShouldReturnsStatusCreatedWithCardData(t *testing.T) {
@@ -134,6 +136,12 @@ func TestUpdateCardHandlerShouldReturnsStatusOK(t *testing.T) {
 			cardRequestBody: cardRequestBody{
 				Description: "sample card description",
 			},
+		}, {
+			testName:  "when completed",
+			attribute: "completed",
+			cardRequestBody: cardRequestBody{
+				Completed: true,
+			},
 		},
 	}
 
@@ -168,6 +176,14 @@ func TestUpdateCardHandlerShouldReturnsStatusOK(t *testing.T) {
 
 			mock.ExpectCommit()
 
+			if tc.attribute == "description" {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `mentions` WHERE (card_id = ? AND comment_id IS NULL)")).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectCommit()
+			}
+
 			r.PATCH("/card/:cardID/:attribute", ch.UpdateCard)
 			r.ServeHTTP(w, req)
 
@@ -187,6 +203,8 @@ func TestUpdateCardHandlerShouldReturnsStatusOK(t *testing.T) {
 				assert.Equal(t, res["card"].Title, tc.cardRequestBody.Title)
 			} else if tc.attribute == "description" {
 				assert.Equal(t, res["card"].Description, tc.cardRequestBody.Description)
+			} else if tc.attribute == "completed" {
+				assert.True(t, res["card"].Completed)
 			}
 		})
 	}
@@ -266,6 +284,109 @@ func TestShouldFailureUpdateCardHandler(t *testing.T) {
 	}
 }
 
+func TestUpdateCardHandlerShouldReturnsStatusPreconditionFailedWhenIfMatchIsStale(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCardHandler(repository.NewCardRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, err := json.Marshal(cardRequestBody{Title: "new title"})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/card/1/title", bytes.NewReader(b))
+	req.Header.Set("If-Match", `"1"`)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `cards`.* FROM `cards` Join lists ON lists.id = cards.list_id Join boards ON boards.id = lists.board_id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "version"}).AddRow(uint(1), "current title", uint(2)))
+
+	r.PATCH("/card/:cardID/:attribute", ch.UpdateCard)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := struct {
+		Card   entity.Card                 `json:"card"`
+		Errors []validator.ValidationError `json:"errors"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 412)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	assert.Equal(t, "current title", res.Card.Title)
+	assert.Equal(t, repository.ErrorConflict, res.Errors[0].Text)
+}
+
+func TestUpdateCardHandlerShouldReturnsStatusPreconditionFailedWhenCardIsUpdatedConcurrently(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCardHandler(repository.NewCardRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, err := json.Marshal(cardRequestBody{Title: "new title"})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/card/1/title", bytes.NewReader(b))
+	req.Header.Set("If-Match", `"1"`)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `cards`.* FROM `cards` Join lists ON lists.id = cards.list_id Join boards ON boards.id = lists.board_id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "version"}).AddRow(uint(1), "sample title", uint(1)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `cards` SET `title` = ?, `updated_at` = ?, `version` = version + 1 WHERE `cards`.`deleted_at` IS NULL AND `cards`.`id` = ? AND ((version = ?))")).
+		WithArgs("new title", utils.AnyTime{}, uint(1), 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `cards`.* FROM `cards` Join lists ON lists.id = cards.list_id Join boards ON boards.id = lists.board_id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "version"}).AddRow(uint(1), "other title", uint(2)))
+
+	r.PATCH("/card/:cardID/:attribute", ch.UpdateCard)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := struct {
+		Card   entity.Card                 `json:"card"`
+		Errors []validator.ValidationError `json:"errors"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 412)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	assert.Equal(t, "other title", res.Card.Title)
+	assert.Equal(t, uint(2), res.Card.Version)
+	assert.Equal(t, repository.ErrorConflict, res.Errors[0].Text)
+}
+
 func TestUpdateCardIndexShouldReturnsStatusOK(t *testing.T) {
 	db, mock := utils.NewDBMock(t)
 	defer db.Close()
@@ -294,6 +415,12 @@ func TestUpdateCardIndexShouldReturnsStatusOK(t *testing.T) {
 
 	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
 
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, list_id FROM `cards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}).AddRow(uint(1), uint(1)))
+
 	q := "UPDATE `cards` SET `index` = ELT(FIELD(id,1,2,3),1,3,2), `list_id` = ELT(FIELD(id,1,2,3),1,1,1) WHERE id IN (1,2,3)"
 	mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnResult(sqlmock.NewResult(1, 3))
 
@@ -377,7 +504,7 @@ func TestDeleteCardHandlerShouldReturnsStatusBadRequest(t *testing.T) {
 	}
 
 	assert.Equal(t, w.Code, 400)
-	assert.Equal(t, res["errors"][0].Text, repository.ErrorInvalidRequest)
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorRecordNotFound)
 }
 
 func TestSearchCardHandlerShouldReturnsStatusOKWithCardData(t *testing.T) {
@@ -424,3 +551,120 @@ func TestSearchCardHandlerShouldReturnsStatusOKWithCardData(t *testing.T) {
 	assert.Equal(t, w.Code, 200)
 	assert.Equal(t, res["card_ids"][0], cardID)
 }
+
+func TestUpdateCardIndexShouldReturnsStatusBadRequestWhenListIsNotEditable(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCardHandler(repository.NewCardRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal([]struct {
+		ID     uint `json:"id"`
+		Index  int  `json:"index"`
+		ListID uint `json:"list_id"`
+	}{
+		{ID: 1, Index: 0, ListID: 1},
+		{ID: 2, Index: 0, ListID: 2},
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/cards/index", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	r.PATCH("/cards/index", ch.UpdateCardIndex)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+}
+
+func TestIndexDueCardsHandlerShouldReturnsStatusOKWithOverdueCards(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCardHandler(repository.NewCardRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/me/due", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT cards.id, cards.title, cards.list_id, lists.name AS list_name, boards.id AS board_id, boards.name AS board_name, cards.start_at, cards.due_at FROM `cards`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "title", "list_id", "list_name", "board_id", "board_name", "due_at"}).
+				AddRow(uint(4), "card", uint(3), "list", uint(2), "board", time.Now().Add(-time.Hour)))
+
+	r.GET("/me/due", ch.IndexDueCards)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]repository.DueCard{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, 1, len(res["cards"]))
+	assert.Equal(t, "board", res["cards"][0].BoardName)
+	assert.True(t, res["cards"][0].Overdue)
+}
+
+func TestUpdateCardHandlerShouldReturnsStatusBadRequestWhenDueAtIsBeforeStartAt(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCardHandler(repository.NewCardRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	startAt := time.Date(2020, 4, 2, 9, 0, 0, 0, time.UTC)
+	dueAt := startAt.Add(-24 * time.Hour)
+
+	b, err := json.Marshal(map[string]time.Time{"due_at": dueAt})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/card/1/due_at", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `cards`.* FROM `cards` Join lists ON lists.id = cards.list_id Join boards ON boards.id = lists.board_id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "start_at"}).AddRow(uint(1), "sample title", startAt))
+
+	r.PATCH("/card/:cardID/:attribute", ch.UpdateCard)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, repository.ErrorDueBeforeStart, res["errors"][0].Text)
+}
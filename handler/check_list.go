@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"local.packages/entity"
 	"local.packages/repository"
 	"local.packages/validator"
 )
@@ -24,6 +25,7 @@ func NewCheckListHandler(r *repository.CheckListRepository) *CheckListHandler {
 }
 
 // CreateCheckList call a function that create a new record to check_lists table.
+// watchers of the card are notified of the new check list.
 // if creation was successful, returns status 201 and instance of CheckList as http response.
 // if creation was failure, returns status 400 and error with messages.
 func (h CheckListHandler) CreateCheckList(c *gin.Context) {
@@ -36,9 +38,10 @@ func (h CheckListHandler) CreateCheckList(c *gin.Context) {
 	}
 
 	cid := getIDParam(c, "cardID")
+	uid := currentUserID(c)
 
-	if err := h.repository.ValidateUID(cid, currentUserID(c)); err != nil {
-		log.Println("uid does not match board.user_id associated with the card")
+	if err := h.repository.ValidateUID(cid, uid); err != nil {
+		log.Println("uid is not a member who can edit the board associated with the card")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -50,12 +53,17 @@ func (h CheckListHandler) CreateCheckList(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(cl, uid, entity.ActivityCreate, nil, entity.ActivityValues{"title": cl.Title})
+	h.repository.NotifyWatchers(cl, uid)
+
 	c.JSON(http.StatusCreated, gin.H{"check_list": cl})
 }
 
 // UpdateCheckList call a function that update a record in check_lists table.
-// if deletion was successful, returns status 200.
-// if deletion was failure, returns status 400 and errors with message.
+// the check list is updated only if its version matches an If-Match header, or if the header is not given.
+// if update was successful, returns status 200 with its ETag.
+// if the check list was updated by another request, returns status 412 and the current instance of CheckList.
+// if update was failure, returns status 400 and errors with message.
 func (h CheckListHandler) UpdateCheckList(c *gin.Context) {
 	var p checkListParams
 
@@ -66,42 +74,87 @@ func (h CheckListHandler) UpdateCheckList(c *gin.Context) {
 	}
 
 	cid := getIDParam(c, "checkListID")
+	uid := currentUserID(c)
 
-	cl, err := h.repository.Find(cid, currentUserID(c))
+	cl, err := h.repository.Find(cid, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the check_list")
+		log.Println("uid is not a member who can edit the board associated with the check_list")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	if !matchesIfMatch(c, cl.Version) {
+		log.Println("check list was updated after the client got it")
+		preconditionFailed(c, "check_list", cl.Version, cl)
+		return
+	}
+
+	before := cl.Title
+
 	if err := h.repository.Update(cl, p.Title); err != nil {
+		if isConflict(err) {
+			if cl, err = h.repository.Find(cid, uid); err != nil {
+				log.Println("check list was deleted by another request")
+				c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+				return
+			}
+
+			preconditionFailed(c, "check_list", cl.Version, cl)
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(cl, uid, entity.ActivityUpdate, entity.ActivityValues{"title": before}, entity.ActivityValues{"title": cl.Title})
+
+	c.Header("ETag", etag(cl.Version))
 	c.Status(http.StatusOK)
 }
 
 // DeleteCheckList call a function that delete a record from check_lists table.
+// when an If-Match header is given, the check list is deleted only if its version matches it.
 // if deletion was successful, returns status 200.
+// if the check list was updated by another request, returns status 412 and the current instance of CheckList.
 // if deletion was failure, returns status 400 and errors with message.
 func (h CheckListHandler) DeleteCheckList(c *gin.Context) {
 	id := getIDParam(c, "checkListID")
+	uid := currentUserID(c)
 
-	cl, err := h.repository.Find(id, currentUserID(c))
+	cl, err := h.repository.Find(id, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the check_list")
+		log.Println("uid is not a member who can edit the board associated with the check_list")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
-	if err := h.repository.Delete(cl); err != nil {
+	if !matchesIfMatch(c, cl.Version) {
+		log.Println("check list was updated after the client got it")
+		preconditionFailed(c, "check_list", cl.Version, cl)
+		return
+	}
+
+	if err := h.repository.Delete(cl, ifMatchVersion(c, cl.Version)); err != nil {
+		if isConflict(err) {
+			if cl, err = h.repository.Find(id, uid); err != nil {
+				log.Println("check list was deleted by another request")
+				c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+				return
+			}
+
+			preconditionFailed(c, "check_list", cl.Version, cl)
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(cl, uid, entity.ActivityDelete, entity.ActivityValues{"title": cl.Title}, nil)
+
 	c.Status(http.StatusOK)
 }
 
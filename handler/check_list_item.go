@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"local.packages/entity"
 	"local.packages/repository"
 	"local.packages/validator"
 )
@@ -37,9 +38,10 @@ func (h CheckListItemHandler) CreateCheckListItem(c *gin.Context) {
 	}
 
 	cid := getIDParam(c, "checkListID")
+	uid := currentUserID(c)
 
-	if err := h.repository.ValidateUID(cid, currentUserID(c)); err != nil {
-		log.Println("uid does not match board.user_id associated with the check_list")
+	if err := h.repository.ValidateUID(cid, uid); err != nil {
+		log.Println("uid is not a member who can edit the board associated with the check_list")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -51,6 +53,8 @@ func (h CheckListItemHandler) CreateCheckListItem(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(item, uid, entity.ActivityCreate, nil, entity.ActivityValues{"name": item.Name})
+
 	c.JSON(http.StatusCreated, gin.H{"check_list_item": item})
 }
 
@@ -67,31 +71,44 @@ func (h CheckListItemHandler) UpdateCheckListItem(c *gin.Context) {
 	}
 
 	id := getIDParam(c, "checkListItemID")
+	uid := currentUserID(c)
 
-	item, err := h.repository.Find(id, currentUserID(c))
+	item, err := h.repository.Find(id, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the check_list_item")
+		log.Println("uid is not a member who can edit the board associated with the check_list_item")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	var before, after entity.ActivityValues
+
 	switch c.Param("attribute") {
 	case "name":
+		before = entity.ActivityValues{"name": item.Name}
+
 		if err := h.repository.Update(item, p.Name); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 			return
 		}
+
+		after = entity.ActivityValues{"name": item.Name}
 	case "check":
+		before = entity.ActivityValues{"check": item.Check}
+
 		if err := h.repository.Check(item, p.Check); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 			return
 		}
+
+		after = entity.ActivityValues{"check": item.Check}
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
 		return
 	}
 
+	h.repository.RecordActivity(item, uid, entity.ActivityUpdate, before, after)
+
 	c.Status(http.StatusOK)
 }
 
@@ -100,11 +117,12 @@ func (h CheckListItemHandler) UpdateCheckListItem(c *gin.Context) {
 // if deletion was failure, returns status 400 and errors with message.
 func (h CheckListItemHandler) DeleteCheckListItem(c *gin.Context) {
 	id := getIDParam(c, "checkListItemID")
+	uid := currentUserID(c)
 
-	item, err := h.repository.Find(id, currentUserID(c))
+	item, err := h.repository.Find(id, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the check_list_item")
+		log.Println("uid is not a member who can edit the board associated with the check_list_item")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -114,5 +132,7 @@ func (h CheckListItemHandler) DeleteCheckListItem(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(item, uid, entity.ActivityDelete, entity.ActivityValues{"name": item.Name}, nil)
+
 	c.Status(http.StatusOK)
 }
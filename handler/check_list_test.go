@@ -276,7 +276,7 @@ func TestDeleteCheckListHandlerShouldReturnsStatusBadRequest(t *testing.T) {
 	}
 
 	assert.Equal(t, w.Code, 400)
-	assert.Equal(t, res["errors"][0].Text, repository.ErrorInvalidRequest)
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorRecordNotFound)
 }
 
 func TestIndexCheckListHandlerShouldReturnsStatusOKWithCheckListData(t *testing.T) {
@@ -309,7 +309,7 @@ func TestIndexCheckListHandlerShouldReturnsStatusOKWithCheckListData(t *testing.
 	}
 
 	checkListQuery := utils.ReplaceQuotationForQuery(`
-		SELECT check_lists.id, check_lists.title, check_lists.card_id
+		SELECT check_lists.id, check_lists.title, check_lists.card_id, check_lists.version
 		FROM 'check_lists'`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(checkListQuery)).
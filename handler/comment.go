@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/entity"
+	"local.packages/repository"
+	"local.packages/validator"
+)
+
+type commentParams struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+// CommentHandler ...
+type CommentHandler struct {
+	repository *repository.CommentRepository
+}
+
+// NewCommentHandler is constructor for CommentHandler.
+func NewCommentHandler(r *repository.CommentRepository) *CommentHandler {
+	return &CommentHandler{repository: r}
+}
+
+func commentResponse(cm *entity.Comment) gin.H {
+	r := gin.H{
+		"id":         cm.ID,
+		"card_id":    cm.CardID,
+		"user_id":    cm.UserID,
+		"body":       cm.Body,
+		"created_at": cm.CreatedAt,
+		"edited_at":  cm.EditedAt,
+	}
+
+	if cm.User != nil {
+		r["user_name"] = cm.User.Name
+	}
+
+	return r
+}
+
+// IndexComments returns status 200 and comments of a card as http response.
+// comments are paginated by query `page` newest first, and the number of all comments is returned as total.
+func (h CommentHandler) IndexComments(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	cid := getIDParam(c, "cardID")
+
+	if err := h.repository.ValidateUID(cid, currentUserID(c)); err != nil {
+		log.Println("uid is not a member of the board associated with the card")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	cms, total := h.repository.GetAll(cid, page)
+
+	r := []gin.H{}
+
+	for i := range *cms {
+		r = append(r, commentResponse(&(*cms)[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": r, "total": total})
+}
+
+// CreateComment call a function that create a new record to comments table.
+// every member of the board can comment on its cards.
+// members mentioned by `@handle` in the comment are notified.
+// if creation was successful, returns status 201 and the comment as http response.
+// if creation was failure, returns status 400 and error with messages.
+func (h CommentHandler) CreateComment(c *gin.Context) {
+	var p commentParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	cid := getIDParam(c, "cardID")
+
+	if err := h.repository.ValidateUID(cid, currentUserID(c)); err != nil {
+		log.Println("uid is not a member of the board associated with the card")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	cm, err := h.repository.Create(cid, currentUserID(c), p.Body)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if ms, err := h.repository.SaveMentions(cm); err == nil {
+		notifyMentions(ms, cm.Body)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"comment": commentResponse(cm)})
+}
+
+// UpdateComment call a function that update a body of a comment.
+// only the author can edit the comment. members who are newly mentioned are notified.
+// if update was successful, returns status 200 and the comment as http response.
+// if update was failure, returns status 400 and error with messages.
+func (h CommentHandler) UpdateComment(c *gin.Context) {
+	var p commentParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	cm, err := h.repository.Find(getIDParam(c, "commentID"), currentUserID(c))
+
+	if err != nil {
+		log.Println("uid is not an author of the comment")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.Update(cm, p.Body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if ms, err := h.repository.SaveMentions(cm); err == nil {
+		notifyMentions(ms, cm.Body)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comment": commentResponse(cm)})
+}
+
+// DeleteComment call a function that delete a record from comments table.
+// only the author can delete the comment.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and errors with message.
+func (h CommentHandler) DeleteComment(c *gin.Context) {
+	cm, err := h.repository.Find(getIDParam(c, "commentID"), currentUserID(c))
+
+	if err != nil {
+		log.Println("uid is not an author of the comment")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.Delete(cm); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestCreateCommentHandlerShouldReturnsStatusCreatedWithComment(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCommentHandler(repository.NewCommentRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(commentParams{Body: "looks good"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/card/4/comment", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT boards.id FROM `boards` Join lists ON boards.id = lists.board_id Join cards ON lists.id = cards.list_id")).
+		WithArgs(uint(4), uint(1), "owner", "editor", "viewer").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(2)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `comments`")).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, uint(4), uint(1), "looks good", nil).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `mentions` WHERE (card_id = ? AND comment_id = ?)")).
+		WithArgs(uint(4), uint(7)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	r.POST("/card/:cardID/comment", ch.CreateComment)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 201)
+	assert.Equal(t, float64(7), res["comment"]["id"])
+	assert.Nil(t, res["comment"]["edited_at"])
+}
+
+func TestIndexCommentsHandlerShouldReturnsStatusBadRequestWithInvalidPage(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCommentHandler(repository.NewCommentRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/card/4/comments?page=0", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	r.GET("/card/:cardID/comments", ch.IndexComments)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+}
+
+func TestUpdateCommentHandlerShouldReturnsStatusBadRequestWhenUserIsNotAuthor(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCommentHandler(repository.NewCommentRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(commentParams{Body: "edited"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/comment/7", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT 'comments'.* FROM 'comments'
+		Join cards ON cards.id = comments.card_id
+		Join lists ON lists.id = cards.list_id
+		Join boards ON boards.id = lists.board_id
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?)))
+		AND (comments.user_id = ?) AND ('comments'.'id' = 7)`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(uint(1), "owner", "editor", "viewer", uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.PATCH("/comment/:commentID", ch.UpdateComment)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, repository.ErrorRecordNotFound, res["errors"][0].Text)
+}
+
+func TestDeleteCommentHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ch := NewCommentHandler(repository.NewCommentRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/comment/7", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `comments`.* FROM `comments`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "card_id", "user_id"}).AddRow(uint(7), uint(4), uint(1)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `comments` WHERE `comments`.`id` = ?")).
+		WithArgs(uint(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.DELETE("/comment/:commentID", ch.DeleteComment)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"local.packages/entity"
 	"local.packages/repository"
 	"local.packages/validator"
 )
@@ -30,9 +31,10 @@ func NewCoverHandler(r *repository.CoverRepository) *CoverHandler {
 func (h CoverHandler) CreateCover(c *gin.Context) {
 	cid := getIDParam(c, "cardID")
 	fid := getIDParam(c, "fileID")
+	uid := currentUserID(c)
 
-	if err := h.repository.ValidateUID(cid, currentUserID(c)); err != nil {
-		log.Println("uid does not match board.user_id associated with the card")
+	if err := h.repository.ValidateUID(cid, uid); err != nil {
+		log.Println("uid is not a member who can edit the board associated with the card")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -45,6 +47,8 @@ func (h CoverHandler) CreateCover(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(co, uid, entity.ActivityCreate, nil, entity.ActivityValues{"file_id": co.FileID})
+
 	c.JSON(http.StatusCreated, gin.H{"cover": co})
 }
 
@@ -60,19 +64,24 @@ func (h CoverHandler) UpdateCover(c *gin.Context) {
 		return
 	}
 
-	co, err := h.repository.Find(p.CardID, currentUserID(c))
+	uid := currentUserID(c)
+	co, err := h.repository.Find(p.CardID, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the cover")
+		log.Println("uid is not a member who can edit the board associated with the cover")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	before := co.FileID
+
 	if err := h.repository.Update(co, p.FileID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(co, uid, entity.ActivityUpdate, entity.ActivityValues{"file_id": before}, entity.ActivityValues{"file_id": p.FileID})
+
 	c.Status(http.StatusOK)
 }
 
@@ -81,11 +90,12 @@ func (h CoverHandler) UpdateCover(c *gin.Context) {
 // if deletion was failure, returns status 400 and errors with message.
 func (h CoverHandler) DeleteCover(c *gin.Context) {
 	cid := getIDParam(c, "cardID")
+	uid := currentUserID(c)
 
-	co, err := h.repository.Find(cid, currentUserID(c))
+	co, err := h.repository.Find(cid, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the cover")
+		log.Println("uid is not a member who can edit the board associated with the cover")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -95,5 +105,7 @@ func (h CoverHandler) DeleteCover(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(co, uid, entity.ActivityDelete, entity.ActivityValues{"file_id": co.FileID}, nil)
+
 	c.Status(http.StatusOK)
 }
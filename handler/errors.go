@@ -7,4 +7,20 @@ const (
 	ErrorOverMaxFileSize string = "ファイルサイズが8MBを超えています"
 	// ErrorMustBeAnInteger is an error text that when data type is not integer.
 	ErrorMustBeAnInteger string = "は数値である必要があります"
+	// ErrorFailedSendMail is an error text when sending an email was failed.
+	ErrorFailedSendMail string = "メールの送信に失敗しました"
+	// ErrorEmailNotVerified is an error text when an email address has not been verified yet.
+	ErrorEmailNotVerified string = "メールアドレスの確認が完了していません"
+	// ErrorInsufficientScope is an error text when a request is not allowed by the scope of an access token.
+	ErrorInsufficientScope string = "アクセストークンの権限が不足しています"
+	// ErrorSignInLocked is an error text when sign in is locked by repeated failures.
+	ErrorSignInLocked string = "ログインの試行回数が上限に達しました。しばらく時間をおいてから再度お試しください"
+	// ErrorOIDCNotConfigured is an error text when sign in with OpenID Connect is not configured.
+	ErrorOIDCNotConfigured string = "外部アカウントでのログインは利用できません"
+	// ErrorOIDCFailed is an error text when an authorization by OpenID Provider was failed.
+	ErrorOIDCFailed string = "外部アカウントでの認証に失敗しました"
+	// ErrorAdminRequired is an error text when a request requires the admin role.
+	ErrorAdminRequired string = "管理者権限が必要です"
+	// ErrorOwnAccount is an error text when an administrator tries to change the own account by the administration API.
+	ErrorOwnAccount string = "自分自身のアカウントは変更できません"
 )
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"local.packages/entity"
 	"local.packages/repository"
 	"local.packages/validator"
 )
@@ -23,6 +24,7 @@ func NewFileHandler(r *repository.FileRepository) *FileHandler {
 }
 
 // UploadFile call a function that upload a file to storage and create a new record to files table.
+// watchers of the card are notified of the new file.
 // if creation was successful, returns status 201 and instance of File as http response.
 // if creation was failure, returns status 400 and error with messages.
 func (h FileHandler) UploadFile(c *gin.Context) {
@@ -35,9 +37,10 @@ func (h FileHandler) UploadFile(c *gin.Context) {
 	}
 
 	cid := getIDParam(c, "cardID")
+	uid := currentUserID(c)
 
-	if err := h.repository.ValidateUID(cid, currentUserID(c)); err != nil {
-		log.Println("uid does not match board.user_id associated with the card")
+	if err := h.repository.ValidateUID(cid, uid); err != nil {
+		log.Println("uid is not a member who can edit the board associated with the card")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -55,6 +58,9 @@ func (h FileHandler) UploadFile(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(f, uid, entity.ActivityCreate, nil, entity.ActivityValues{"display_name": f.DisplayName})
+	h.repository.NotifyWatchers(f, uid)
+
 	c.JSON(http.StatusCreated, gin.H{"file": f})
 }
 
@@ -63,8 +69,9 @@ func (h FileHandler) UploadFile(c *gin.Context) {
 // if deletion was failure, returns status 400 and errors with message.
 func (h FileHandler) DeleteFile(c *gin.Context) {
 	fid := getIDParam(c, "fileID")
+	uid := currentUserID(c)
 
-	f, err := h.repository.Find(fid, currentUserID(c))
+	f, err := h.repository.Find(fid, uid)
 
 	if err != nil {
 		log.Printf("file was not fonund. %v", err)
@@ -78,6 +85,7 @@ func (h FileHandler) DeleteFile(c *gin.Context) {
 	}
 
 	h.repository.DeleteObject(fmt.Sprintf("%d/%s", fid, f.Key))
+	h.repository.RecordActivity(f, uid, entity.ActivityDelete, entity.ActivityValues{"display_name": f.DisplayName}, nil)
 
 	c.Status(http.StatusOK)
 }
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/validator"
+)
+
+type invitationParams struct {
+	Role      string     `json:"role" binding:"required,oneof=owner editor viewer"`
+	SingleUse bool       `json:"single_use"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type acceptInvitationParams struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// CreateInvitation call a function that create an invitation link to a board with a preset role.
+// only an owner of the board can invite.
+// if creation was successful, returns status 201 and the invitation with its token as http response.
+// the token is shown only once.
+// if creation was failure, returns status 400 and error with messages.
+func (h BoardMemberHandler) CreateInvitation(c *gin.Context) {
+	var p invitationParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	if p.ExpiresAt != nil && p.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	bid := getIDParam(c, "boardID")
+
+	if err := h.repository.ValidateUID(bid, currentUserID(c)); err != nil {
+		log.Println("uid is not an owner of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	inv, t, err := h.repository.CreateInvitation(bid, currentUserID(c), p.Role, p.SingleUse, p.ExpiresAt)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"invitation": inv, "token": t})
+}
+
+// IndexInvitations returns status 200 and invitations of a board that can still be accepted as http response.
+// only an owner of the board can see invitations.
+func (h BoardMemberHandler) IndexInvitations(c *gin.Context) {
+	bid := getIDParam(c, "boardID")
+
+	if err := h.repository.ValidateUID(bid, currentUserID(c)); err != nil {
+		log.Println("uid is not an owner of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": h.repository.GetInvitations(bid)})
+}
+
+// RevokeInvitation call a function that delete an invitation of a board.
+// only an owner of the board can revoke invitations.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and error with messages.
+func (h BoardMemberHandler) RevokeInvitation(c *gin.Context) {
+	bid := getIDParam(c, "boardID")
+
+	if err := h.repository.ValidateUID(bid, currentUserID(c)); err != nil {
+		log.Println("uid is not an owner of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.DeleteInvitation(getIDParam(c, "invitationID"), bid); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// AcceptInvitation call a function that add the current user to members of a board of an invitation.
+// if acceptance was successful, returns status 200 and the membership as http response.
+// if acceptance was failure, returns status 400 and error with messages.
+func (h UserHandler) AcceptInvitation(c *gin.Context) {
+	var p acceptInvitationParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	m, err := h.repository.AcceptInvitation(p.Token, currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"board_id": m.BoardID, "role": m.Role})
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestCreateInvitationHandlerShouldReturnsStatusCreatedWithToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	mh := NewBoardMemberHandler(repository.NewBoardMemberRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(invitationParams{Role: "editor", SingleUse: true})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/board/2/invitations", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WithArgs(uint(1), "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(2)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `invitations`")).
+		WithArgs(utils.AnyTime{}, uint(2), uint(1), "editor", sqlmock.AnyArg(), true, utils.AnyTime{}, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	r.POST("/board/:boardID/invitations", mh.CreateInvitation)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 201)
+	assert.NotEmpty(t, res["token"])
+	assert.Equal(t, "editor", res["invitation"].(map[string]interface{})["role"])
+	assert.Nil(t, res["invitation"].(map[string]interface{})["digest"])
+}
+
+func TestCreateInvitationHandlerShouldReturnsStatusBadRequestWhenExpiresAtHasPassed(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	mh := NewBoardMemberHandler(repository.NewBoardMemberRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	e := time.Now().Add(-time.Hour)
+	b, _ := json.Marshal(invitationParams{Role: "viewer", ExpiresAt: &e})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/board/2/invitations", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	r.POST("/board/:boardID/invitations", mh.CreateInvitation)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, ErrorInvalidParameter, res["errors"][0].Text)
+}
+
+func TestIndexInvitationsHandlerShouldReturnsStatusBadRequestWhenUserIsNotOwner(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	mh := NewBoardMemberHandler(repository.NewBoardMemberRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/board/2/invitations", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WithArgs(uint(1), "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.GET("/board/:boardID/invitations", mh.IndexInvitations)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, repository.ErrorInvalidSession, res["errors"][0].Text)
+}
+
+func TestAcceptInvitationHandlerShouldReturnsStatusOKWithMembership(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(acceptInvitationParams{Token: "sampletoken"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/invitations/accept", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `invitations`")).
+		WithArgs(utils.AnyTime{}, false, utils.DigestToken("sampletoken")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "board_id", "role", "single_use"}).
+				AddRow(uint(5), uint(2), "editor", false))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(2)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `board_members`")).
+		WithArgs(uint(2), uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id", "user_id"}))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `board_members`")).
+		WithArgs(uint(2), uint(1), "editor", utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.POST("/invitations/accept", h.AcceptInvitation)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, float64(2), res["board_id"])
+	assert.Equal(t, "editor", res["role"])
+}
+
+func TestCreateUserHandlerShouldReturnsStatusBadRequestWhenInvitationTokenIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/user", h.CreateUser)
+
+	b, _ := json.Marshal(userParams{
+		Name:                 "gopher",
+		Email:                "gopher@sample.com",
+		Password:             "12345678",
+		PasswordConfirmation: "12345678",
+		InvitationToken:      "sampletoken",
+	})
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `invitations`")).
+		WithArgs(utils.AnyTime{}, false, utils.DigestToken("sampletoken")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/user", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, repository.ErrorInvalidToken, res["errors"][0].Text)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"local.packages/entity"
 	"local.packages/repository"
 	"local.packages/validator"
 )
@@ -38,9 +39,10 @@ func (h LabelHandler) CreateLabel(c *gin.Context) {
 	}
 
 	bid := getIDParam(c, "boardID")
+	uid := currentUserID(c)
 
-	if err := h.repository.ValidateUID(bid, currentUserID(c)); err != nil {
-		log.Println("uid does not match board.user_id associated with the label")
+	if err := h.repository.ValidateUID(bid, uid); err != nil {
+		log.Println("uid is not a member who can edit the board associated with the label")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -52,22 +54,33 @@ func (h LabelHandler) CreateLabel(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(l, uid, entity.ActivityCreate, nil, entity.ActivityValues{"name": l.Name, "color": l.Color})
+
 	c.JSON(http.StatusCreated, gin.H{"label": l})
 }
 
 // UpdateLabel call a function that update a record in labels table.
-// if update was successful, returns status 200 and updated instance of Label as http response.
+// the label is updated only if its version matches an If-Match header, or if the header is not given.
+// if update was successful, returns status 200 and updated instance of Label with its ETag as http response.
+// if the label was updated by another request, returns status 412 and the current instance of Label.
 // if update was failure, returns status 400 and error with messages.
 func (h LabelHandler) UpdateLabel(c *gin.Context) {
 	id := getIDParam(c, "labelID")
-	l, err := h.repository.Find(id, currentUserID(c))
+	uid := currentUserID(c)
+	l, err := h.repository.Find(id, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the label")
+		log.Println("uid is not a member who can edit the board associated with the label")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	if !matchesIfMatch(c, l.Version) {
+		log.Println("label was updated after the client got it")
+		preconditionFailed(c, "label", l.Version, l)
+		return
+	}
+
 	var p labelParams
 
 	if err := c.ShouldBindJSON(&p); err != nil {
@@ -76,11 +89,27 @@ func (h LabelHandler) UpdateLabel(c *gin.Context) {
 		return
 	}
 
+	before := entity.ActivityValues{"name": l.Name, "color": l.Color}
+
 	if err := h.repository.Update(l, p.Name, p.Color); err != nil {
+		if isConflict(err) {
+			if l, err = h.repository.Find(id, uid); err != nil {
+				log.Println("label was deleted by another request")
+				c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+				return
+			}
+
+			preconditionFailed(c, "label", l.Version, l)
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(l, uid, entity.ActivityUpdate, before, entity.ActivityValues{"name": l.Name, "color": l.Color})
+
+	c.Header("ETag", etag(l.Version))
 	c.JSON(http.StatusOK, gin.H{"label": l})
 }
 
@@ -93,22 +122,44 @@ func (h LabelHandler) IndexLabel(c *gin.Context) {
 }
 
 // DeleteLabel call a function that delete a record from labels table.
+// when an If-Match header is given, the label is deleted only if its version matches it.
 // if deletion was successful, returns status 200.
+// if the label was updated by another request, returns status 412 and the current instance of Label.
 // if deletion was failure, returns status 400 and errors with message.
 func (h LabelHandler) DeleteLabel(c *gin.Context) {
 	id := getIDParam(c, "labelID")
-	l, err := h.repository.Find(id, currentUserID(c))
+	uid := currentUserID(c)
+	l, err := h.repository.Find(id, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the label")
+		log.Println("uid is not a member who can edit the board associated with the label")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
-	if err := h.repository.Delete(l); err != nil {
+	if !matchesIfMatch(c, l.Version) {
+		log.Println("label was updated after the client got it")
+		preconditionFailed(c, "label", l.Version, l)
+		return
+	}
+
+	if err := h.repository.Delete(l, ifMatchVersion(c, l.Version)); err != nil {
+		if isConflict(err) {
+			if l, err = h.repository.Find(id, uid); err != nil {
+				log.Println("label was deleted by another request")
+				c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+				return
+			}
+
+			preconditionFailed(c, "label", l.Version, l)
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(l, uid, entity.ActivityDelete, entity.ActivityValues{"name": l.Name, "color": l.Color}, nil)
+
 	c.Status(http.StatusOK)
 }
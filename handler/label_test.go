@@ -50,7 +50,7 @@ func TestCreateLabelHandlerShouldReturnsStatusCreatedWithLabelData(t *testing.T)
 
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM `boards`"))
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `labels` (`created_at`,`updated_at`,`deleted_at`,`name`,`color`,`board_id`)")).
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `labels` (`created_at`,`updated_at`,`deleted_at`,`name`,`color`,`board_id`,`version`)")).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -281,7 +281,7 @@ func TestIndexLabelHandlerShouldReturnsStatusOKWithLabelData(t *testing.T) {
 
 	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT labels.id, labels.name, labels.color, labels.board_id FROM `labels` Join boards on boards.id = labels.board_id")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT labels.id, labels.name, labels.color, labels.board_id, labels.version FROM `labels` Join boards on boards.id = labels.board_id")).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)))
 
 	r.GET("/board/:boardID/labels", lh.IndexLabel)
@@ -367,5 +367,5 @@ func TestDeleteLabelHandlerShouldReturnsStatusBadRequest(t *testing.T) {
 	}
 
 	assert.Equal(t, w.Code, 400)
-	assert.Equal(t, res["errors"][0].Text, repository.ErrorInvalidRequest)
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorRecordNotFound)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"local.packages/entity"
 	"local.packages/repository"
 	"local.packages/validator"
 )
@@ -37,9 +38,10 @@ func (h ListHandler) CreateList(c *gin.Context) {
 	}
 
 	bid := getIDParam(c, "boardID")
+	uid := currentUserID(c)
 
-	if err := h.repository.ValidateUID(bid, currentUserID(c)); err != nil {
-		log.Println("uid does not match board.user_id associated with the list")
+	if err := h.repository.ValidateUID(bid, uid); err != nil {
+		log.Println("uid is not a member who can edit the board associated with the list")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -51,22 +53,34 @@ func (h ListHandler) CreateList(c *gin.Context) {
 		return
 	}
 
+	h.repository.RecordActivity(l, uid, entity.ActivityCreate, nil, entity.ActivityValues{"name": l.Name})
+
 	c.JSON(http.StatusCreated, gin.H{"list": l})
 }
 
 // UpdateList call a function that update a record in lists table.
-// if update was successful, returns status 200 and updated instance of List as http response.
+// watchers of the list are notified of the update.
+// the list is updated only if its version matches an If-Match header, or if the header is not given.
+// if update was successful, returns status 200 and updated instance of List with its ETag as http response.
+// if the list was updated by another request, returns status 412 and the current instance of List.
 // if update was failure, returns status 400 and error with messages.
 func (h ListHandler) UpdateList(c *gin.Context) {
 	id := getIDParam(c, "listID")
-	l, err := h.repository.Find(id, currentUserID(c))
+	uid := currentUserID(c)
+	l, err := h.repository.Find(id, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the list")
+		log.Println("uid is not a member who can edit the board associated with the list")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	if !matchesIfMatch(c, l.Version) {
+		log.Println("list was updated after the client got it")
+		preconditionFailed(c, "list", l.Version, l)
+		return
+	}
+
 	var p listParams
 
 	if err := c.ShouldBindJSON(&p); err != nil {
@@ -75,11 +89,29 @@ func (h ListHandler) UpdateList(c *gin.Context) {
 		return
 	}
 
+	before := l.Name
+
 	if err := h.repository.Update(l, p.Name); err != nil {
+		if isConflict(err) {
+			if l, err = h.repository.Find(id, uid); err != nil {
+				log.Println("list was deleted by another request")
+				c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+				return
+			}
+
+			preconditionFailed(c, "list", l.Version, l)
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(l, uid, entity.ActivityUpdate, entity.ActivityValues{"name": before}, entity.ActivityValues{"name": l.Name})
+
+	h.repository.NotifyWatchers(l, entity.NotificationListUpdated, uid)
+
+	c.Header("ETag", etag(l.Version))
 	c.JSON(http.StatusOK, gin.H{"list": l})
 }
 
@@ -96,7 +128,7 @@ func (h ListHandler) UpdateListIndex(c *gin.Context) {
 		return
 	}
 
-	if err := h.repository.UpdateIndex(ps); err != nil {
+	if err := h.repository.UpdateIndex(ps, currentUserID(c)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -105,22 +137,69 @@ func (h ListHandler) UpdateListIndex(c *gin.Context) {
 }
 
 // DeleteList call a function that delete a record from lists table.
+// watchers of the list are notified of the deletion.
+// when an If-Match header is given, the list is deleted only if its version matches it.
 // if deletion was successful, returns status 200.
+// if the list was updated by another request, returns status 412 and the current instance of List.
 // if deletion was failure, returns status 400 and errors with message.
 func (h ListHandler) DeleteList(c *gin.Context) {
 	id := getIDParam(c, "listID")
-	l, err := h.repository.Find(id, currentUserID(c))
+	uid := currentUserID(c)
+	l, err := h.repository.Find(id, uid)
 
 	if err != nil {
-		log.Println("uid does not match board.user_id associated with the list")
+		log.Println("uid is not a member who can edit the board associated with the list")
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
-	if err := h.repository.Delete(l); err != nil {
+	if !matchesIfMatch(c, l.Version) {
+		log.Println("list was updated after the client got it")
+		preconditionFailed(c, "list", l.Version, l)
+		return
+	}
+
+	if err := h.repository.Delete(l, ifMatchVersion(c, l.Version)); err != nil {
+		if isConflict(err) {
+			if l, err = h.repository.Find(id, uid); err != nil {
+				log.Println("list was deleted by another request")
+				c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+				return
+			}
+
+			preconditionFailed(c, "list", l.Version, l)
+			return
+		}
+
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
 
+	h.repository.RecordActivity(l, uid, entity.ActivityDelete, entity.ActivityValues{"name": l.Name}, nil)
+	h.repository.NotifyWatchers(l, entity.NotificationListDeleted, uid)
+
 	c.Status(http.StatusOK)
 }
+
+// RestoreList call a function that restore a deleted list to the end of its board.
+// if restoration was successful, returns status 200 and restored instance of List as http response.
+// if restoration was failure, returns status 400 and errors with message.
+func (h ListHandler) RestoreList(c *gin.Context) {
+	uid := currentUserID(c)
+	l, err := h.repository.FindDeleted(getIDParam(c, "listID"), uid)
+
+	if err != nil {
+		log.Println("uid is not a member who can edit the board associated with the list, or the list is not deleted")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.Restore(l); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	h.repository.RecordActivity(l, uid, entity.ActivityRestore, nil, entity.ActivityValues{"name": l.Name, "index": l.Index})
+
+	c.JSON(http.StatusOK, gin.H{"list": l})
+}
@@ -275,6 +275,133 @@ func TestDeleteListShouldReturnsStatusOK(t *testing.T) {
 	assert.Equal(t, w.Code, 200)
 }
 
+func TestDeleteListHandlerShouldReturnsStatusPreconditionFailedWhenIfMatchIsStale(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	lh := NewListHandler(repository.NewListRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/list/1", nil)
+	req.Header.Set("If-Match", `"3"`)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `lists`.* FROM `lists` Join boards on boards.id = lists.board_id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(uint(1), "renamed list", uint(4)))
+
+	r.DELETE("/list/:listID", lh.DeleteList)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := struct {
+		List   entity.List                 `json:"list"`
+		Errors []validator.ValidationError `json:"errors"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 412)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	assert.Equal(t, "renamed list", res.List.Name)
+	assert.Equal(t, repository.ErrorConflict, res.Errors[0].Text)
+}
+
+func TestDeleteListHandlerShouldReturnsStatusPreconditionFailedWhenListIsUpdatedBeforeDeletion(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	lh := NewListHandler(repository.NewListRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/list/1", nil)
+	req.Header.Set("If-Match", `"3"`)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `lists`.* FROM `lists` Join boards on boards.id = lists.board_id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(uint(1), "list", uint(3)))
+
+	// another request updates the list between finding and deleting it.
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `lists` SET `deleted_at` = ?, `index` = ? WHERE `lists`.`deleted_at` IS NULL AND `lists`.`id` = ? AND ((version = ?))")).
+		WithArgs(utils.AnyTime{}, 0, uint(1), uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `lists`.* FROM `lists` Join boards on boards.id = lists.board_id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(uint(1), "renamed list", uint(4)))
+
+	r.DELETE("/list/:listID", lh.DeleteList)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 412)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+}
+
+func TestDeleteListHandlerShouldReturnsStatusBadRequestWhenListIsDeletedBeforeDeletion(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	lh := NewListHandler(repository.NewListRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/list/1", nil)
+	req.Header.Set("If-Match", `"3"`)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `lists`.* FROM `lists` Join boards on boards.id = lists.board_id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(uint(1), "list", uint(3)))
+
+	// another request deletes the list between finding and deleting it.
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `lists` SET `deleted_at` = ?, `index` = ? WHERE `lists`.`deleted_at` IS NULL AND `lists`.`id` = ? AND ((version = ?))")).
+		WithArgs(utils.AnyTime{}, 0, uint(1), uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `lists`.* FROM `lists` Join boards on boards.id = lists.board_id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.DELETE("/list/:listID", lh.DeleteList)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, "", w.Header().Get("ETag"))
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorRecordNotFound)
+}
+
 func TestDeleteListHandlerShouldReturnsStatusBadRequest(t *testing.T) {
 	db, mock := utils.NewDBMock(t)
 	defer db.Close()
@@ -310,5 +437,5 @@ func TestDeleteListHandlerShouldReturnsStatusBadRequest(t *testing.T) {
 	}
 
 	assert.Equal(t, w.Code, 400)
-	assert.Equal(t, res["errors"][0].Text, repository.ErrorInvalidRequest)
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorRecordNotFound)
 }
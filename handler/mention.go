@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/config"
+	"local.packages/entity"
+	"local.packages/mailer"
+	"local.packages/repository"
+)
+
+// mentionExcerptLength is the max number of characters of a text quoted in a mention mail.
+const mentionExcerptLength = 100
+
+// notifyMentions sends a mail to each user who is newly mentioned in a text.
+// mails are sent in the background, so that a slow mail server does not delay the response.
+// the text is saved already, so that a failure is only logged.
+func notifyMentions(ms []entity.Mention, text string) {
+	if len(ms) == 0 {
+		return
+	}
+
+	excerpt := []rune(text)
+
+	if len(excerpt) > mentionExcerptLength {
+		excerpt = append(excerpt[:mentionExcerptLength], []rune("…")...)
+	}
+
+	var msgs []*mailer.Message
+
+	for _, m := range ms {
+		link := fmt.Sprintf("%s/board/%d?card=%d", config.Config.Web.Origin, m.BoardID, m.CardID)
+		msgs = append(msgs, mailer.NewMentionMessage(m.User.Email, m.User.Name, m.Actor.Name, string(excerpt), link))
+	}
+
+	go func() {
+		for _, msg := range msgs {
+			if err := mailer.Get().Send(msg); err != nil {
+				log.Printf("fail to send mention mail: %v", err)
+			}
+		}
+	}()
+}
+
+// SearchMentionMembers returns status 200 and members of a board whose name starts with query `q` as http response.
+// each member has a handle to mention the member in a comment or a description of a card.
+// members whose name has no character that can be in a handle are left out, because they can not be mentioned.
+func (h BoardMemberHandler) SearchMentionMembers(c *gin.Context) {
+	us := h.repository.SearchMembers(getIDParam(c, "boardID"), currentUserID(c), c.Query("q"))
+
+	r := []gin.H{}
+
+	for _, u := range *us {
+		handle := repository.MentionHandle(u.Name)
+
+		if handle == "" {
+			continue
+		}
+
+		r = append(r, gin.H{"user_id": u.ID, "name": u.Name, "handle": handle})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": r})
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/repository"
+	"local.packages/utils"
+)
+
+func TestSearchMentionMembersHandlerShouldReturnsStatusOKWithHandles(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	mh := NewBoardMemberHandler(repository.NewBoardMemberRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/board/2/members/search?q=go", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT users.id, users.name FROM `users`")).
+		WithArgs(uint(1), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), uint(2), "go%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uint(3), "go pher"))
+
+	r.GET("/board/:boardID/members/search", mh.SearchMentionMembers)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, float64(3), res["members"][0]["user_id"])
+	assert.Equal(t, "gopher", res["members"][0]["handle"])
+}
@@ -6,33 +6,176 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 
 	"local.packages/config"
+	"local.packages/entity"
+	"local.packages/jwt"
+	"local.packages/repository"
 	"local.packages/validator"
 )
 
 // Authenticate call a function that validate a session token.
-// map a login user id to context if authentication was valid.
+// map a login user id and session id to context if authentication was valid.
+// a personal access token is also accepted instead of a session token.
+// if access tokens are signed, their signatures are verified without database access,
+// and their sessions are looked up at most once per interval, so that a revoked session can not be used until the token expires.
 func (h UserHandler) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.Request.Header.Get("X-Auth-Token")
 
-		u, ok := h.repository.IsSignedIn(token)
+		if token == "" {
+			token = strings.TrimPrefix(c.Request.Header.Get("Authorization"), "Bearer ")
+		}
+
+		if strings.HasPrefix(token, entity.AccessTokenPrefix) {
+			h.authenticateAccessToken(c, token)
+			return
+		}
+
+		if jwt.Enabled() && jwt.IsSigned(token) {
+			h.authenticateSignedToken(c, token)
+			return
+		}
+
+		s, ok := h.repository.IsSignedIn(token)
 
 		if !ok {
 			c.AbortWithStatus(401)
 			return
 		}
 
-		if u.ExpiresAt.Before(time.Now()) {
+		if s.ExpiresAt.Before(time.Now()) {
 			c.AbortWithStatusJSON(401, gin.H{"reason": "expired"})
 			return
 		}
-		c.Set("uid", u.ID)
+
+		h.repository.Touch(s)
+
+		c.Set("uid", s.UserID)
+		c.Set("sid", s.ID)
+	}
+}
+
+// AuthenticateStream call a function that validate a stream token in query `stream_token`, or authenticate a request as Authenticate does.
+// EventSource of browsers can not send an auth header, so that a short-lived token is sent in the URL instead.
+// map a login user id to context if authentication was valid.
+func (h UserHandler) AuthenticateStream() gin.HandlerFunc {
+	authenticate := h.Authenticate()
+
+	return func(c *gin.Context) {
+		token := c.Query("stream_token")
+
+		if token == "" {
+			authenticate(c)
+			return
+		}
+
+		uid, err := h.repository.UseStreamToken(token)
+
+		if err != nil {
+			c.AbortWithStatusJSON(401, gin.H{"errors": err})
+			return
+		}
+
+		c.Set("uid", uid)
+	}
+}
+
+// authenticateSignedToken validate a signature and expiry of a signed access token, and check that its session still exists.
+// map a login user id and session id to context if authentication was valid.
+func (h UserHandler) authenticateSignedToken(c *gin.Context, token string) {
+	claims, err := jwt.Verify(token, time.Now())
+
+	if err == jwt.ErrExpired {
+		c.AbortWithStatusJSON(401, gin.H{"reason": "expired"})
+		return
+	}
+
+	if err != nil {
+		c.AbortWithStatus(401)
+		return
+	}
+
+	uid, err := claims.UserID()
+
+	if err != nil || !h.repository.IsSessionAlive(claims.SessionID, uid) {
+		c.AbortWithStatus(401)
+		return
+	}
+
+	c.Set("uid", uid)
+	c.Set("sid", claims.SessionID)
+}
+
+// authenticateAccessToken validate a personal access token and its scope.
+// map a login user id and access token id to context if authentication was valid.
+func (h UserHandler) authenticateAccessToken(c *gin.Context, token string) {
+	at, ok := h.repository.FindAccessToken(token)
+
+	if !ok {
+		c.AbortWithStatus(401)
+		return
+	}
+
+	if at.Scope == entity.ScopeRead && c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"errors": validator.NewValidationErrors(ErrorInsufficientScope)})
+		return
+	}
+
+	if at.BoardID != nil && !h.isInBoardScope(c, *at.BoardID) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"errors": validator.NewValidationErrors(ErrorInsufficientScope)})
+		return
+	}
+
+	h.repository.TouchAccessToken(at)
+
+	c.Set("uid", at.UserID)
+	c.Set("atid", at.ID)
+}
+
+// isInBoardScope check if every record specified by URL params belongs to the board.
+// a request that does not specify any record of a board is out of the scope.
+func (h UserHandler) isInBoardScope(c *gin.Context, bid uint) bool {
+	scoped := false
+
+	for _, p := range c.Params {
+		id, ok := c.Keys[p.Key].(uint)
+
+		if !ok {
+			continue
+		}
+
+		b, ok := h.repository.BoardIDOfParam(p.Key, id)
+
+		if !ok {
+			continue
+		}
+
+		if b != bid {
+			return false
+		}
+
+		scoped = true
+	}
+
+	return scoped
+}
+
+// RejectAccessToken reject a request that was authenticated by a personal access token.
+// it is used for routes that manage the account or credentials, so that a leaked token can not take over the account.
+func RejectAccessToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("atid"); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"errors": validator.NewValidationErrors(ErrorInsufficientScope)})
+			return
+		}
+
+		c.Next()
 	}
 }
 
@@ -69,8 +212,8 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", config.Config.Web.Origin)
 		c.Writer.Header().Set("Access-Control-Max-Age", "86400")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE, PATCH")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, X-Auth-Token")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, X-Auth-Token, Authorization, Last-Event-ID, If-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, ETag")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		if c.Request.Method == "OPTIONS" {
@@ -121,10 +264,57 @@ func (h UserHandler) RejectTester() gin.HandlerFunc {
 	}
 }
 
+// RejectTestUserAccount reject a request by the test user that changes the account.
+// test users are shared by anyone, so that their profile, password and existence must not be changed.
+func (h UserHandler) RejectTestUserAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.repository.IsTestUserID(currentUserID(c)) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"errors": validator.NewValidationErrors(repository.ErrorInvalidRequest)})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireVerified reject a request by the user whose email address has not been verified.
+// does nothing unless `auth.require_verification` is enabled in config file.
+func (h UserHandler) RequireVerified() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.Config.Auth.RequireVerification {
+			c.Next()
+			return
+		}
+
+		if !h.repository.IsVerified(currentUserID(c)) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"errors": validator.NewValidationErrors(ErrorEmailNotVerified)})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAdmin reject a request by the user who does not have the admin role.
+func (h UserHandler) RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.repository.IsAdmin(currentUserID(c)) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"errors": validator.NewValidationErrors(ErrorAdminRequired)})
+			return
+		}
+
+		c.Next()
+	}
+}
+
 func currentUserID(c *gin.Context) uint {
 	return c.Keys["uid"].(uint)
 }
 
+func currentSessionID(c *gin.Context) uint {
+	return c.Keys["sid"].(uint)
+}
+
 func getIDParam(c *gin.Context, key string) uint {
 	return c.Keys[key].(uint)
 }
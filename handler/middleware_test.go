@@ -14,6 +14,9 @@ import (
 	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
 
+	"local.packages/config"
+	"local.packages/entity"
+	"local.packages/jwt"
 	"local.packages/repository"
 	"local.packages/utils"
 	"local.packages/validator"
@@ -24,10 +27,13 @@ func TestShouldSetUserIDToContextUponSuccessfullyAuthenticate(t *testing.T) {
 	defer db.Close()
 
 	userID := uint(1)
+	sessionID := uint(2)
 	expire := time.Now().Add(time.Hour * 1)
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "expires_at"}).AddRow(userID, expire))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sessions`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "last_seen_at", "expires_at"}).
+				AddRow(sessionID, userID, time.Now(), expire))
 
 	w := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
@@ -38,6 +44,7 @@ func TestShouldSetUserIDToContextUponSuccessfullyAuthenticate(t *testing.T) {
 	r.Use(uh.Authenticate())
 	r.GET("/test", func(c *gin.Context) {
 		assert.Equal(t, c.Keys["uid"], userID)
+		assert.Equal(t, c.Keys["sid"], sessionID)
 		c.Status(200)
 	})
 
@@ -47,11 +54,86 @@ func TestShouldSetUserIDToContextUponSuccessfullyAuthenticate(t *testing.T) {
 	assert.Equal(t, w.Code, 200)
 }
 
+func TestShouldUpdateLastSeenUponAuthenticateWhenSessionWasNotSeenRecently(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	expire := time.Now().Add(time.Hour * 1)
+	lastSeen := time.Now().Add(time.Hour * -1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sessions`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "last_seen_at", "expires_at"}).
+				AddRow(uint(2), uint(1), lastSeen, expire))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `sessions` SET `last_seen_at` = ? WHERE `sessions`.`id` = ?")).
+		WithArgs(utils.AnyTime{}, uint(2)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, r := gin.CreateTestContext(w)
+
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r.Use(uh.Authenticate())
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "/test", nil)
+	r.ServeHTTP(w, c.Request)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestShouldReturnsStatusUnAuthorizationWhenSessionHasExpired(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	expire := time.Now().Add(time.Hour * -1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sessions`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "last_seen_at", "expires_at"}).
+				AddRow(uint(2), uint(1), expire, expire))
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, r := gin.CreateTestContext(w)
+
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r.Use(uh.Authenticate())
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "/test", nil)
+	r.ServeHTTP(w, c.Request)
+
+	res := map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 401)
+	assert.Equal(t, res["reason"], "expired")
+}
+
 func TestShouldReturnsStatusUnAuthorizationWhenRequestTokenIsInvalid(t *testing.T) {
 	db, mock := utils.NewDBMock(t)
 	defer db.Close()
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sessions`")).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	w := httptest.NewRecorder()
@@ -71,6 +153,221 @@ func TestShouldReturnsStatusUnAuthorizationWhenRequestTokenIsInvalid(t *testing.
 	assert.Equal(t, w.Code, 401)
 }
 
+func setUpSignedToken() func() {
+	config.Config.Token.Signed = true
+	config.Config.Token.KeyID = "test"
+	config.Config.Token.Keys = map[string]string{"test": "secret"}
+
+	return func() {
+		config.Config.Token.Signed = false
+		config.Config.Token.KeyID = ""
+		config.Config.Token.Keys = nil
+	}
+}
+
+func TestShouldAuthenticateSignedTokenWhenSessionExists(t *testing.T) {
+	defer setUpSignedToken()()
+
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	userID := uint(1)
+	sessionID := uint(201)
+
+	token, _ := jwt.Sign(jwt.NewClaims(userID, sessionID, time.Now().Add(time.Hour*1)))
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, r := gin.CreateTestContext(w)
+
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `sessions`")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(sessionID))
+
+	r.Use(uh.Authenticate())
+	r.GET("/test", func(c *gin.Context) {
+		assert.Equal(t, c.Keys["uid"], userID)
+		assert.Equal(t, c.Keys["sid"], sessionID)
+		c.Status(200)
+	})
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "/test", nil)
+	c.Request.Header.Add("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, c.Request)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestShouldReturnsStatusUnAuthorizationWhenSessionOfSignedTokenWasRevoked(t *testing.T) {
+	defer setUpSignedToken()()
+
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	userID := uint(1)
+	sessionID := uint(202)
+
+	token, _ := jwt.Sign(jwt.NewClaims(userID, sessionID, time.Now().Add(time.Hour*1)))
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, r := gin.CreateTestContext(w)
+
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `sessions`")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.Use(uh.Authenticate())
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "/test", nil)
+	c.Request.Header.Add("X-Auth-Token", token)
+	r.ServeHTTP(w, c.Request)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 401)
+}
+
+func TestShouldReturnsStatusUnAuthorizationWhenSignedTokenHasExpired(t *testing.T) {
+	defer setUpSignedToken()()
+
+	db, _ := utils.NewDBMock(t)
+	defer db.Close()
+
+	token, _ := jwt.Sign(jwt.NewClaims(uint(1), uint(2), time.Now().Add(time.Hour*-1)))
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, r := gin.CreateTestContext(w)
+
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r.Use(uh.Authenticate())
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "/test", nil)
+	c.Request.Header.Add("X-Auth-Token", token)
+	r.ServeHTTP(w, c.Request)
+
+	assert.Equal(t, w.Code, 401)
+	assert.JSONEq(t, `{"reason":"expired"}`, w.Body.String())
+}
+
+func TestShouldReturnsStatusUnAuthorizationWhenSignedTokenIsForged(t *testing.T) {
+	defer setUpSignedToken()()
+
+	db, _ := utils.NewDBMock(t)
+	defer db.Close()
+
+	config.Config.Token.Keys = map[string]string{"test": "another secret"}
+	token, _ := jwt.Sign(jwt.NewClaims(uint(1), uint(2), time.Now().Add(time.Hour*1)))
+	config.Config.Token.Keys = map[string]string{"test": "secret"}
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, r := gin.CreateTestContext(w)
+
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r.Use(uh.Authenticate())
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "/test", nil)
+	c.Request.Header.Add("X-Auth-Token", token)
+	r.ServeHTTP(w, c.Request)
+
+	assert.Equal(t, w.Code, 401)
+}
+
+func TestShouldAuthenticateStreamByStreamToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	userID := uint(1)
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, r := gin.CreateTestContext(w)
+
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `one_time_tokens`")).
+		WithArgs(sqlmock.AnyArg(), entity.PurposeEventStream, utils.AnyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(3), userID))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `one_time_tokens`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+
+	mock.ExpectCommit()
+
+	r.Use(uh.AuthenticateStream())
+	r.GET("/test", func(c *gin.Context) {
+		assert.Equal(t, c.Keys["uid"], userID)
+		c.Status(200)
+	})
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "/test?stream_token=sampletoken", nil)
+	r.ServeHTTP(w, c.Request)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestShouldReturnsStatusUnAuthorizationWhenStreamTokenIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, r := gin.CreateTestContext(w)
+
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `one_time_tokens`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}))
+
+	mock.ExpectRollback()
+
+	r.Use(uh.AuthenticateStream())
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "/test?stream_token=sampletoken", nil)
+	r.ServeHTTP(w, c.Request)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 401)
+}
+
 func TestShouldSetIDToContextWhenRequestParamKeyContainsSuffixID(t *testing.T) {
 	w := httptest.NewRecorder()
 	gin.SetMode(gin.TestMode)
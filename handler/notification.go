@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/entity"
+	"local.packages/repository"
+	"local.packages/validator"
+)
+
+// NotificationHandler ...
+type NotificationHandler struct {
+	repository *repository.NotificationRepository
+}
+
+// NewNotificationHandler is constructor for NotificationHandler.
+func NewNotificationHandler(r *repository.NotificationRepository) *NotificationHandler {
+	return &NotificationHandler{repository: r}
+}
+
+func notificationResponse(n *entity.Notification) gin.H {
+	r := gin.H{
+		"id":         n.ID,
+		"type":       n.Type,
+		"board_id":   n.BoardID,
+		"card_id":    n.CardID,
+		"title":      n.Title,
+		"actor_id":   n.ActorID,
+		"created_at": n.CreatedAt,
+		"read_at":    n.ReadAt,
+	}
+
+	if n.Actor != nil {
+		r["actor_name"] = n.Actor.Name
+	}
+
+	return r
+}
+
+// IndexNotifications returns status 200 and notifications of the login user as http response.
+// notifications are paginated by query `page` newest first, and only unread ones are returned when query `unread` is true.
+// the number of notifications that match is returned as total, and the number of unread ones as unread.
+func (h NotificationHandler) IndexNotifications(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	unread, err := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	uid := currentUserID(c)
+	ns, total := h.repository.GetAll(uid, page, unread)
+
+	r := []gin.H{}
+
+	for i := range *ns {
+		r = append(r, notificationResponse(&(*ns)[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": r, "total": total, "unread": h.repository.CountUnread(uid)})
+}
+
+// ReadNotification call a function that mark a notification of the login user as read.
+// if update was successful, returns status 200 and the notification as http response.
+// if update was failure, returns status 400 and error with messages.
+func (h NotificationHandler) ReadNotification(c *gin.Context) {
+	n, err := h.repository.Read(getIDParam(c, "notificationID"), currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notification": notificationResponse(n)})
+}
+
+// ReadAllNotifications call a function that mark all notifications of the login user as read.
+// if update was successful, returns status 200.
+// if update was failure, returns status 400 and error with messages.
+func (h NotificationHandler) ReadAllNotifications(c *gin.Context) {
+	if err := h.repository.ReadAll(currentUserID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestIndexNotificationsHandlerShouldReturnsStatusOKWithNotifications(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	nh := NewNotificationHandler(repository.NewNotificationRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/notifications?unread=true", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `notifications` WHERE (user_id = ?) AND (read_at IS NULL)")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `notifications` WHERE (user_id = ?) AND (read_at IS NULL)")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "actor_id", "type", "board_id", "card_id", "title"}).
+				AddRow(uint(2), uint(1), uint(3), "mentioned", uint(5), uint(4), "card"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uint(3), "gopher"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `notifications` WHERE (user_id = ? AND read_at IS NULL)")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	r.GET("/notifications", nh.IndexNotifications)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := struct {
+		Notifications []map[string]interface{} `json:"notifications"`
+		Total         int                      `json:"total"`
+		Unread        int                      `json:"unread"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, 1, res.Unread)
+	assert.Equal(t, "mentioned", res.Notifications[0]["type"])
+	assert.Equal(t, "gopher", res.Notifications[0]["actor_name"])
+}
+
+func TestIndexNotificationsHandlerShouldReturnsStatusBadRequestWithInvalidUnread(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	nh := NewNotificationHandler(repository.NewNotificationRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/notifications?unread=maybe", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	r.GET("/notifications", nh.IndexNotifications)
+	r.ServeHTTP(w, req)
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, ErrorInvalidParameter, res["errors"][0].Text)
+}
+
+func TestReadAllNotificationsHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	nh := NewNotificationHandler(repository.NewNotificationRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/notifications/read", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `notifications` SET `read_at` = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectCommit()
+
+	r.PATCH("/notifications/read", nh.ReadAllNotifications)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
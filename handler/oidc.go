@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/oidc"
+	"local.packages/repository"
+	"local.packages/validator"
+)
+
+type oidcSessionParams struct {
+	Code   string `json:"code" binding:"required"`
+	State  string `json:"state" binding:"required"`
+	Device string `json:"device" binding:"max=100"`
+}
+
+// AuthorizeOIDC call a function that start an authorization request to OpenID Provider.
+// returns status 200 with a URL of the authorization endpoint that the client redirects to.
+// returns status 400 if OpenID Connect is not configured.
+func (h UserHandler) AuthorizeOIDC(c *gin.Context) {
+	p, err := oidc.Get()
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorOIDCNotConfigured)})
+		return
+	}
+
+	var values [3]string
+
+	for i := range values {
+		if values[i], err = oidc.NewRandomString(); err != nil {
+			log.Printf("fail to create random string: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"errors": validator.NewValidationErrors(repository.ErrorAuthenticationFailed)})
+			return
+		}
+	}
+
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	u, err := p.AuthCodeURL(state, nonce, verifier)
+
+	if err != nil {
+		log.Printf("fail to discover oidc provider: %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{"errors": validator.NewValidationErrors(ErrorOIDCFailed)})
+		return
+	}
+
+	if err := h.repository.CreateOIDCAuthRequest(state, nonce, verifier); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"authorization_url": u})
+}
+
+// CreateOIDCSession call a function that complete a sign in by an authorization code of OpenID Provider.
+// returns access token, refresh token and expires if an ID token was valid.
+// returns a challenge token instead if two-factor authentication of the user is enabled.
+func (h UserHandler) CreateOIDCSession(c *gin.Context) {
+	var p oidcSessionParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	pr, err := oidc.Get()
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorOIDCNotConfigured)})
+		return
+	}
+
+	ar, verr := h.repository.UseOIDCAuthRequest(p.State)
+
+	if verr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": verr})
+		return
+	}
+
+	raw, err := pr.Exchange(p.Code, ar.CodeVerifier)
+
+	if err != nil {
+		log.Printf("fail to exchange authorization code: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorOIDCFailed)})
+		return
+	}
+
+	claims, err := pr.Verify(raw, ar.Nonce)
+
+	if err != nil {
+		log.Printf("fail to verify id token: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorOIDCFailed)})
+		return
+	}
+
+	u, verr := h.repository.SignInWithIdentity(pr.Issuer(), claims.Subject, claims.Email, claims.Name, bool(claims.EmailVerified))
+
+	if verr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": verr})
+		return
+	}
+
+	h.startSession(c, u, p.Device)
+}
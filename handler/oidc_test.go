@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/config"
+	"local.packages/oidc"
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func setUpStubIdP(t *testing.T) *utils.StubIdP {
+	idp, err := utils.NewStubIdP("kanban", "secret")
+
+	if err != nil {
+		t.Fatalf("fail to start stub idp: %v", err)
+	}
+
+	config.Config.OIDC.Issuer = idp.Issuer()
+	config.Config.OIDC.ClientID = idp.ClientID
+	config.Config.OIDC.ClientSecret = idp.ClientSecret
+	config.Config.OIDC.RedirectURL = "http://localhost:3000/oidc/callback"
+
+	return idp
+}
+
+func tearDownStubIdP(idp *utils.StubIdP) {
+	idp.Close()
+	config.Config.OIDC.Issuer = ""
+}
+
+func TestAuthorizeOIDCHandlerShouldReturnsStatusOKWithAuthorizationURL(t *testing.T) {
+	idp := setUpStubIdP(t)
+	defer tearDownStubIdP(idp)
+
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.GET("/oidc/authorize", h.AuthorizeOIDC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `oidc_auth_requests`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/oidc/authorize", nil)
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]string{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Regexp(t, "^"+regexp.QuoteMeta(idp.Issuer()+"/authorize?"), res["authorization_url"])
+}
+
+func TestAuthorizeOIDCHandlerShouldReturnsStatusBadRequestWhenNotConfigured(t *testing.T) {
+	db, _ := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.GET("/oidc/authorize", h.AuthorizeOIDC)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/oidc/authorize", nil)
+
+	r.ServeHTTP(w, req)
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, ErrorOIDCNotConfigured, res["errors"][0].Text)
+}
+
+func TestCreateOIDCSessionHandlerShouldReturnsStatusOKWithSessionToken(t *testing.T) {
+	idp := setUpStubIdP(t)
+	defer tearDownStubIdP(idp)
+
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/session/oidc", h.CreateOIDCSession)
+
+	p, _ := oidc.Get()
+
+	authURL, err := p.AuthCodeURL("samplestate", "samplenonce", "sampleverifier")
+
+	if err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	code, err := idp.Authorize(authURL, "subject", "gopher@sample.com", true, "gopher")
+
+	if err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	b, _ := json.Marshal(oidcSessionParams{Code: code, State: "samplestate"})
+
+	userID := uint(9)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `oidc_auth_requests`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "nonce", "code_verifier"}).
+				AddRow(uint(1), "samplenonce", "sampleverifier"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `oidc_auth_requests`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `identities`")).
+		WithArgs(idp.Issuer(), "subject").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(1), userID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "email"}).
+				AddRow(userID, "gopher", "gopher@sample.com"))
+
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `sessions`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/session/oidc", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, "gopher@sample.com", res["email"])
+	assert.NotEmpty(t, res["access_token"])
+	assert.NotEmpty(t, res["refresh_token"])
+}
+
+func TestCreateOIDCSessionHandlerShouldReturnsStatusBadRequestWhenCodeVerifierDoesNotMatch(t *testing.T) {
+	idp := setUpStubIdP(t)
+	defer tearDownStubIdP(idp)
+
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/session/oidc", h.CreateOIDCSession)
+
+	p, _ := oidc.Get()
+
+	authURL, _ := p.AuthCodeURL("samplestate", "samplenonce", "sampleverifier")
+	code, _ := idp.Authorize(authURL, "subject", "gopher@sample.com", true, "gopher")
+
+	b, _ := json.Marshal(oidcSessionParams{Code: code, State: "samplestate"})
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `oidc_auth_requests`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "nonce", "code_verifier"}).
+				AddRow(uint(1), "samplenonce", "anotherverifier"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `oidc_auth_requests`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/session/oidc", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, ErrorOIDCFailed, res["errors"][0].Text)
+}
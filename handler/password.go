@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/config"
+	"local.packages/entity"
+	"local.packages/mailer"
+	"local.packages/validator"
+)
+
+type forgotPasswordParams struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type resetPasswordParams struct {
+	Token                string `json:"token" binding:"required"`
+	Password             string `json:"password" binding:"required,min=8,eqfield=PasswordConfirmation"`
+	PasswordConfirmation string `json:"password_confirmation" binding:"required"`
+}
+
+// ForgotPassword call a function that create a password reset token and send it by email.
+// returns status 200 even if the email is not registered or the mail could not be sent, so that registered emails are not disclosed.
+func (h UserHandler) ForgotPassword(c *gin.Context) {
+	var p forgotPasswordParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	u, err := h.repository.FindByEmail(p.Email)
+
+	if err != nil {
+		log.Printf("password reset was requested for unregistered email: %v", p.Email)
+		c.Status(http.StatusOK)
+		return
+	}
+
+	if _, err := h.sendPasswordResetMail(u); err != nil {
+		log.Printf("fail to send password reset mail to registered email: %v", err)
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// sendPasswordResetMail creates a password reset token of the user and send it by email.
+// returns status of http response with errors if it was failure.
+func (h UserHandler) sendPasswordResetMail(u *entity.User) (int, []validator.ValidationError) {
+	t, err := h.repository.CreatePasswordResetToken(u.ID)
+
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	link := fmt.Sprintf("%s/password/reset?token=%s", config.Config.Web.Origin, url.QueryEscape(t))
+
+	if err := mailer.Get().Send(mailer.NewPasswordResetMessage(u.Email, u.Name, link)); err != nil {
+		log.Printf("fail to send password reset mail: %v", err)
+		return http.StatusInternalServerError, validator.NewValidationErrors(ErrorFailedSendMail)
+	}
+
+	return http.StatusOK, nil
+}
+
+// ResetPassword call a function that update a password by a password reset token.
+// if update was successful, returns status 200.
+// if update was failure, returns status 400 and error with messages.
+func (h UserHandler) ResetPassword(c *gin.Context) {
+	var p resetPasswordParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	passwordDigest, err := h.repository.EncryptPassword(p.Password)
+
+	if err != nil {
+		log.Printf("fail to encrypted password: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.ResetPassword(p.Token, passwordDigest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
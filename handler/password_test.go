@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+type forgotPasswordRequestBody struct {
+	Email string `json:"email"`
+}
+
+type resetPasswordRequestBody struct {
+	Token                string `json:"token"`
+	Password             string `json:"password"`
+	PasswordConfirmation string `json:"password_confirmation"`
+}
+
+func TestForgotPasswordHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/password/forgot", h.ForgotPassword)
+
+	email := "gopher@sample.com"
+
+	b, err := json.Marshal(forgotPasswordRequestBody{Email: email})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(uint(1), "gopher", email))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `one_time_tokens`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `one_time_tokens`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/password/forgot", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestForgotPasswordHandlerShouldReturnsStatusOKWhenResetTokenCannotBeCreated(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/password/forgot", h.ForgotPassword)
+
+	email := "gopher@sample.com"
+
+	b, err := json.Marshal(forgotPasswordRequestBody{Email: email})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(uint(1), "gopher", email))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `one_time_tokens`")).
+		WillReturnError(fmt.Errorf("connection refused"))
+
+	mock.ExpectRollback()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/password/forgot", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestForgotPasswordHandlerShouldReturnsStatusOKWhenEmailIsNotRegistered(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/password/forgot", h.ForgotPassword)
+
+	email := "gopher@sample.com"
+
+	b, err := json.Marshal(forgotPasswordRequestBody{Email: email})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WithArgs(email).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/password/forgot", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestForgotPasswordHandlerShouldReturnsStatusBadRequestWhenEmailIsInvalid(t *testing.T) {
+	db, _ := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/password/forgot", h.ForgotPassword)
+
+	b, err := json.Marshal(forgotPasswordRequestBody{Email: "gopher"})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/password/forgot", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, res["errors"][0].Text, validator.ErrorEmail("メールアドレス"))
+}
+
+func TestResetPasswordHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/password/reset", h.ResetPassword)
+
+	b, err := json.Marshal(resetPasswordRequestBody{
+		Token:                "sampletoken",
+		Password:             "12345678",
+		PasswordConfirmation: "12345678",
+	})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `one_time_tokens`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(3), uint(1)))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `one_time_tokens`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, email FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(uint(1), "gopher@sample.com"))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `password_digest`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sign_in_throttles`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/password/reset", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestShouldFailureResetPasswordHandler(t *testing.T) {
+	type testCase struct {
+		testName                 string
+		expectedError            string
+		resetPasswordRequestBody resetPasswordRequestBody
+	}
+
+	testCases := []testCase{
+		{
+			testName:      "when without a token",
+			expectedError: validator.ErrorRequired("トークン"),
+			resetPasswordRequestBody: resetPasswordRequestBody{
+				Token:                "",
+				Password:             "12345678",
+				PasswordConfirmation: "12345678",
+			},
+		}, {
+			testName:      "when does not match password and password confirmation",
+			expectedError: validator.ErrorEqualField("パスワード", "パスワード（確認用）"),
+			resetPasswordRequestBody: resetPasswordRequestBody{
+				Token:                "sampletoken",
+				Password:             "12345678",
+				PasswordConfirmation: "123456789",
+			},
+		}, {
+			testName:      "when password less than 8 characters",
+			expectedError: validator.ErrorTooShort("パスワード", "8"),
+			resetPasswordRequestBody: resetPasswordRequestBody{
+				Token:                "sampletoken",
+				Password:             "1234567",
+				PasswordConfirmation: "1234567",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			db, _ := utils.NewDBMock(t)
+			defer db.Close()
+
+			h := NewUserHandler(repository.NewUserRepository(db))
+
+			r := utils.SetUpRouter()
+			r.POST("/password/reset", h.ResetPassword)
+
+			b, err := json.Marshal(tc.resetPasswordRequestBody)
+
+			if err != nil {
+				t.Fatalf("fail to marshal json: %v", err)
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/password/reset", bytes.NewReader(b))
+
+			r.ServeHTTP(w, req)
+
+			res := map[string][]validator.ValidationError{}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatalf("fail to unmarshal response body. %v", err)
+			}
+
+			assert.Equal(t, w.Code, 400)
+			assert.Equal(t, res["errors"][0].Text, tc.expectedError)
+		})
+	}
+}
+
+func TestResetPasswordHandlerShouldReturnsStatusBadRequestWhenTokenIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/password/reset", h.ResetPassword)
+
+	b, err := json.Marshal(resetPasswordRequestBody{
+		Token:                "sampletoken",
+		Password:             "12345678",
+		PasswordConfirmation: "12345678",
+	})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `one_time_tokens`")).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	mock.ExpectRollback()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/password/reset", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorInvalidToken)
+}
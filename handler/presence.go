@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/repository"
+	"local.packages/validator"
+)
+
+type presenceParams struct {
+	CardID *uint `json:"card_id"`
+}
+
+// PresenceHandler ...
+type PresenceHandler struct {
+	repository *repository.PresenceRepository
+}
+
+// NewPresenceHandler is constructor for PresenceHandler.
+func NewPresenceHandler(r *repository.PresenceRepository) *PresenceHandler {
+	return &PresenceHandler{repository: r}
+}
+
+// IndexPresence returns status 200 and users who are looking at a board with cards that they have open as http response.
+func (h PresenceHandler) IndexPresence(c *gin.Context) {
+	bid := getIDParam(c, "boardID")
+
+	if err := h.repository.ValidateUID(bid, currentUserID(c)); err != nil {
+		log.Println("uid is not a member of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"presences": h.repository.GetAll(bid)})
+}
+
+// UpdatePresence call a function that mark the login user as looking at a board with a card open, or with no card open when card_id is null.
+// a client without a stream of events calls it periodically, or the user leaves the board after a timeout.
+// if update was successful, returns status 200 and the presence of the login user as http response.
+// if update was failure, returns status 400 and error with messages.
+func (h PresenceHandler) UpdatePresence(c *gin.Context) {
+	var p presenceParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		log.Printf("fail to bind JSON: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	bid := getIDParam(c, "boardID")
+	uid := currentUserID(c)
+
+	if err := h.repository.ValidateUID(bid, uid); err != nil {
+		log.Println("uid is not a member of the board")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if p.CardID != nil {
+		if err := h.repository.ValidateCard(*p.CardID, bid); err != nil {
+			log.Println("card is not on the board")
+			c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"presence": h.repository.See(bid, uid, p.CardID)})
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestUpdatePresenceHandlerShouldReturnsStatusOKWithPresence(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ph := NewPresenceHandler(repository.NewPresenceRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(map[string]uint{"card_id": 5})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/board/201/presence", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(201)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `cards` Join lists ON lists.id = cards.list_id")).
+		WithArgs(uint(5), uint(201)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT name FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("gopher"))
+
+	r.PATCH("/board/:boardID/presence", ph.UpdatePresence)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := struct {
+		Presence struct {
+			UserID uint   `json:"user_id"`
+			Name   string `json:"name"`
+			CardID uint   `json:"card_id"`
+		} `json:"presence"`
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, uint(1), res.Presence.UserID)
+	assert.Equal(t, "gopher", res.Presence.Name)
+	assert.Equal(t, uint(5), res.Presence.CardID)
+}
+
+func TestUpdatePresenceHandlerShouldReturnsStatusBadRequestWhenCardIsNotOnBoard(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ph := NewPresenceHandler(repository.NewPresenceRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(map[string]uint{"card_id": 5})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/board/202/presence", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(202)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `cards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	r.PATCH("/board/:boardID/presence", ph.UpdatePresence)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, repository.ErrorRecordNotFound, res["errors"][0].Text)
+}
+
+func TestIndexPresenceHandlerShouldReturnsStatusOKWithEmptyPresences(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	ph := NewPresenceHandler(repository.NewPresenceRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/board/203/presence", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(203)))
+
+	r.GET("/board/:boardID/presence", ph.IndexPresence)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.JSONEq(t, `{"presences":[]}`, w.Body.String())
+}
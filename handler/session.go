@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 
+	"local.packages/entity"
+	"local.packages/jwt"
+	"local.packages/repository"
 	"local.packages/utils"
 	"local.packages/validator"
 )
@@ -13,10 +19,13 @@ import (
 type sessionParams struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	Device   string `json:"device" binding:"max=100"`
 }
 
 // CreateSession call a function that authenticate by request params.
 // returns access token, refresh token and expires if authentication was valid.
+// if two-factor authentication is enabled, returns a challenge token instead of a session.
+// failed attempts are counted, and sign in is locked for a while when they are repeated.
 func (h UserHandler) CreateSession(c *gin.Context) {
 	var p sessionParams
 
@@ -25,8 +34,94 @@ func (h UserHandler) CreateSession(c *gin.Context) {
 		return
 	}
 
+	u, ok := h.signIn(c, p)
+
+	if !ok {
+		return
+	}
+
+	h.startSession(c, u, p.Device)
+}
+
+// CreateTesterSession call a function that authenticate a test user by request params.
+// boards of the test user are reset to the seed fixture before the session is started.
+func (h UserHandler) CreateTesterSession(c *gin.Context) {
+	var p sessionParams
+
+	if err := c.ShouldBindBodyWith(&p, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	u, ok := h.signIn(c, p)
+
+	if !ok {
+		return
+	}
+
+	if err := h.repository.ResetTestUser(u.ID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	h.respondSession(c, u, p.Device)
+}
+
+// signIn returns an instance of User that authenticated by an email and password.
+// responds an error and returns `false` if sign in is locked or authentication was failed.
+func (h UserHandler) signIn(c *gin.Context, p sessionParams) (*entity.User, bool) {
+	if h.rejectLockedSignIn(c, p.Email) {
+		return nil, false
+	}
+
 	u, err := h.repository.SignIn(p.Email, p.Password)
 
+	if err != nil {
+		h.repository.RecordFailedSignIn(p.Email, c.ClientIP())
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return nil, false
+	}
+
+	h.repository.ClearSignInFailures(p.Email)
+
+	return u, true
+}
+
+// startSession responds a challenge token if two-factor authentication of the user is enabled.
+// otherwise responds access token, refresh token and expires of a new session.
+func (h UserHandler) startSession(c *gin.Context, u *entity.User, device string) {
+	if u.TOTPEnabledAt != nil {
+		t, err := h.repository.CreateSignInChallenge(u.ID)
+
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"totp_required": true, "challenge_token": t})
+		return
+	}
+
+	h.respondSession(c, u, device)
+}
+
+// respondSession responds access token, refresh token and expires of a new session of the user.
+// a disabled user is rejected here, so that every way of signing in is covered.
+func (h UserHandler) respondSession(c *gin.Context, u *entity.User, device string) {
+	if u.DisabledAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(repository.ErrorUserDisabled)})
+		return
+	}
+
+	s, err := h.repository.CreateSession(u.ID, device, c.Request.UserAgent(), c.ClientIP())
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	at, err := accessToken(s)
+
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
@@ -37,13 +132,62 @@ func (h UserHandler) CreateSession(c *gin.Context) {
 		gin.H{
 			"email":         u.Email,
 			"name":          u.Name,
-			"access_token":  u.RememberToken,
-			"refresh_token": u.RefreshToken,
-			"expires_in":    utils.CalcExpiresIn(u.ExpiresAt),
+			"session_id":    s.ID,
+			"access_token":  at,
+			"refresh_token": s.RefreshToken,
+			"expires_in":    utils.CalcExpiresIn(s.ExpiresAt),
 		},
 	)
 }
 
+// validateRefreshToken returns a session that found by an access token and a refresh token.
+// a signed access token identifies the session even if it has expired, and the refresh token is checked in database.
+func (h UserHandler) validateRefreshToken(at, rt string) (*entity.Session, bool) {
+	if !jwt.Enabled() || !jwt.IsSigned(at) {
+		return h.repository.ValidateToken(at, rt)
+	}
+
+	claims, err := jwt.Verify(at, time.Now())
+
+	if err != nil && err != jwt.ErrExpired {
+		return nil, false
+	}
+
+	return h.repository.ValidateRefreshToken(claims.SessionID, rt)
+}
+
+// accessToken returns an access token of a session that is sent to the client.
+// it is a token signed with claims of the session if signed access tokens are enabled.
+func accessToken(s *entity.Session) (string, []validator.ValidationError) {
+	if !jwt.Enabled() {
+		return s.RememberToken, nil
+	}
+
+	t, err := jwt.Sign(jwt.NewClaims(s.UserID, s.ID, s.ExpiresAt))
+
+	if err != nil {
+		log.Printf("fail to sign access token: %v", err)
+		return "", validator.NewValidationErrors(repository.ErrorAuthenticationFailed)
+	}
+
+	return t, nil
+}
+
+// rejectLockedSignIn responds status 429 if sign in by an email address or from the client IP address is locked.
+// returns `true` if the request was rejected.
+func (h UserHandler) rejectLockedSignIn(c *gin.Context, email string) bool {
+	d, locked := h.repository.IsLocked(email, c.ClientIP())
+
+	if !locked {
+		return false
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(d.Seconds())+1))
+	c.JSON(http.StatusTooManyRequests, gin.H{"errors": validator.NewValidationErrors(ErrorSignInLocked)})
+
+	return true
+}
+
 // UpdateSession call a function that update access token and refresh token.
 // returns access token, refresh token and expires if authentication was valid.
 func (h UserHandler) UpdateSession(c *gin.Context) {
@@ -58,14 +202,29 @@ func (h UserHandler) UpdateSession(c *gin.Context) {
 		return
 	}
 
-	u, ok := h.repository.ValidateToken(at, p.RefreshToken)
+	s, ok := h.validateRefreshToken(at, p.RefreshToken)
 
 	if !ok {
+		h.repository.RevokeReusedRefreshToken(p.RefreshToken)
 		c.JSON(http.StatusOK, gin.H{"ok": false})
 		return
 	}
 
-	if err := h.repository.UpdateUserSession(u); err != nil {
+	u, err := h.repository.Find(s.UserID)
+
+	if err != nil || u.DisabledAt != nil {
+		c.JSON(http.StatusOK, gin.H{"ok": false})
+		return
+	}
+
+	if err := h.repository.UpdateSession(s); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	at, err = accessToken(s)
+
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -76,16 +235,45 @@ func (h UserHandler) UpdateSession(c *gin.Context) {
 			"ok":            true,
 			"name":          u.Name,
 			"email":         u.Email,
-			"access_token":  u.RememberToken,
-			"refresh_token": u.RefreshToken,
-			"expires_in":    utils.CalcExpiresIn(u.ExpiresAt),
+			"session_id":    s.ID,
+			"access_token":  at,
+			"refresh_token": s.RefreshToken,
+			"expires_in":    utils.CalcExpiresIn(s.ExpiresAt),
 		},
 	)
 }
 
-// DeleteSession call a function that delete access token and refresh token.
+// DeleteSession call a function that delete the session of current device.
 func (h UserHandler) DeleteSession(c *gin.Context) {
-	if err := h.repository.SignOut(currentUserID(c)); err != nil {
+	if err := h.repository.SignOut(currentSessionID(c), currentUserID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// IndexSessions returns status 200 and slice of Session instance that belongs to the login user.
+func (h UserHandler) IndexSessions(c *gin.Context) {
+	ss := h.repository.GetSessions(currentUserID(c))
+	sid := currentSessionID(c)
+
+	for i := range *ss {
+		(*ss)[i].Current = (*ss)[i].ID == sid
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": ss})
+}
+
+// RevokeSession call a function that delete a session of the login user.
+// signs out the device that the session belongs to.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and errors with message.
+func (h UserHandler) RevokeSession(c *gin.Context) {
+	id := getIDParam(c, "sessionID")
+
+	if err := h.repository.DeleteSession(id, currentUserID(c)); err != nil {
+		log.Printf("fail to revoke session: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -8,12 +8,15 @@ import (
 	"net/http/httptest"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
 
+	"local.packages/entity"
+	"local.packages/jwt"
 	"local.packages/repository"
 	"local.packages/utils"
 	"local.packages/validator"
@@ -52,6 +55,9 @@ func TestShouldReturnsStatusOKWithSessionTokenUponUserSignIn(t *testing.T) {
 
 	passwordDigest, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sign_in_throttles`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
 		WithArgs(email).
 		WillReturnRows(
@@ -59,7 +65,13 @@ func TestShouldReturnsStatusOKWithSessionTokenUponUserSignIn(t *testing.T) {
 				AddRow(uint(1), name, email, passwordDigest))
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET")).
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sign_in_throttles`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `sessions`")).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -82,6 +94,7 @@ func TestShouldReturnsStatusOKWithSessionTokenUponUserSignIn(t *testing.T) {
 	assert.Equal(t, w.Code, 200)
 	assert.Equal(t, res["name"], name)
 	assert.Equal(t, res["email"], email)
+	assert.Equal(t, res["session_id"], float64(1))
 	assert.NotNil(t, res["access_token"])
 	assert.NotNil(t, res["refresh_token"])
 	assert.NotNil(t, res["expires_in"])
@@ -163,21 +176,33 @@ func TestShouldReturnsStatusOKWithNewSessionPayload(t *testing.T) {
 	refreshToken := "oirjnoinoiaec"
 
 	findQuery := utils.ReplaceQuotationForQuery(`
-		SELECT * FROM 'users'
+		SELECT * FROM 'sessions'
 		WHERE (remember_token = ?) AND (refresh_token = ?)
+		ORDER BY 'sessions'.'id' ASC
+		LIMIT 1`)
+
+	findUserQuery := utils.ReplaceQuotationForQuery(`
+		SELECT * FROM 'users'
+		WHERE ('users'.'id' = 1)
 		ORDER BY 'users'.'id' ASC
 		LIMIT 1`)
 
 	updateQuery := utils.ReplaceQuotationForQuery(`
-		UPDATE 'users'
-		SET 'expires_at' = ?, 'refresh_token' = ?, 'remember_token' = ?, 'updated_at' = ?
-		WHERE 'users'.'id' = ?`)
+		UPDATE 'sessions'
+		SET 'expires_at' = ?, 'last_seen_at' = ?, 'refresh_token' = ?, 'remember_token' = ?, 'updated_at' = ?
+		WHERE 'sessions'.'id' = ? AND ((refresh_token = ?))`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(findQuery)).
-		WithArgs(accessToken, refreshToken).
+		WithArgs(utils.DigestToken(accessToken), utils.DigestToken(refreshToken)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(2), uint(1)))
+
+	mock.ExpectQuery(regexp.QuoteMeta(findUserQuery)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(uint(1), name, email))
 
 	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `rotated_refresh_tokens`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
 	mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -212,11 +237,82 @@ func TestShouldReturnsStatusOKWithNewSessionPayload(t *testing.T) {
 	assert.Equal(t, res["ok"], true)
 	assert.Equal(t, res["name"], name)
 	assert.Equal(t, res["email"], email)
+	assert.Equal(t, res["session_id"], float64(2))
 	assert.NotNil(t, res["access_token"])
 	assert.NotNil(t, res["refresh_token"])
 	assert.NotNil(t, res["expires_in"])
 }
 
+func TestShouldRefreshSessionByExpiredSignedToken(t *testing.T) {
+	defer setUpSignedToken()()
+
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.PATCH("/session", h.UpdateSession)
+
+	userID := uint(1)
+	sessionID := uint(2)
+	refreshToken := "oirjnoinoiaec"
+
+	accessToken, _ := jwt.Sign(jwt.NewClaims(userID, sessionID, time.Now().Add(time.Hour*-1)))
+
+	findQuery := utils.ReplaceQuotationForQuery(`
+		SELECT * FROM 'sessions'
+		WHERE (id = ?) AND (refresh_token = ?)
+		ORDER BY 'sessions'.'id' ASC
+		LIMIT 1`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(findQuery)).
+		WithArgs(sessionID, utils.DigestToken(refreshToken)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(sessionID, userID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(userID, "gopher", "gopher@sample.com"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `rotated_refresh_tokens`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `sessions`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	b, _ := json.Marshal(tokenRequestBody{RefreshToken: refreshToken})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/session", bytes.NewReader(b))
+
+	req.Header.Add("X-Auth-Token", accessToken)
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, res["ok"], true)
+
+	c, err := jwt.Verify(res["access_token"].(string), time.Now())
+
+	if err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	assert.Equal(t, sessionID, c.SessionID)
+}
+
 func TestShouldReturnsStatusOKWithoutNewSessionPayloadWhenTokenIsInvalid(t *testing.T) {
 	db, mock := utils.NewDBMock(t)
 	defer db.Close()
@@ -230,15 +326,19 @@ func TestShouldReturnsStatusOKWithoutNewSessionPayloadWhenTokenIsInvalid(t *test
 	refreshToken := "oirjnoinoiaec"
 
 	findQuery := utils.ReplaceQuotationForQuery(`
-		SELECT * FROM 'users'
+		SELECT * FROM 'sessions'
 		WHERE (remember_token = ?) AND (refresh_token = ?)
-		ORDER BY 'users'.'id' ASC
+		ORDER BY 'sessions'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(findQuery)).
-		WithArgs(accessToken, refreshToken).
+		WithArgs(utils.DigestToken(accessToken), utils.DigestToken(refreshToken)).
 		WillReturnError(gorm.ErrRecordNotFound)
 
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `rotated_refresh_tokens`")).
+		WithArgs(utils.DigestToken(refreshToken)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
 	b, err := json.Marshal(tokenRequestBody{
 		RefreshToken: refreshToken,
 	})
@@ -271,6 +371,51 @@ func TestShouldReturnsStatusOKWithoutNewSessionPayloadWhenTokenIsInvalid(t *test
 	assert.Nil(t, res["expires_in"])
 }
 
+func TestShouldRevokeSessionWhenRotatedRefreshTokenIsReused(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.PATCH("/session", h.UpdateSession)
+
+	accessToken := "ercmewaorijno"
+	refreshToken := "oirjnoinoiaec"
+	sessionID := uint(2)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sessions`")).
+		WithArgs(utils.DigestToken(accessToken), utils.DigestToken(refreshToken)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `rotated_refresh_tokens`")).
+		WithArgs(utils.DigestToken(refreshToken)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id"}).AddRow(uint(1), sessionID))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions` WHERE (id = ?)")).
+		WithArgs(sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	b, _ := json.Marshal(tokenRequestBody{RefreshToken: refreshToken})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPatch, "/session", bytes.NewReader(b))
+
+	req.Header.Add("X-Auth-Token", accessToken)
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.JSONEq(t, `{"ok":false}`, w.Body.String())
+}
+
 func TestShouldReturnsStatusBadRequestWhenFailedUpdateUser(t *testing.T) {
 	db, mock := utils.NewDBMock(t)
 	defer db.Close()
@@ -284,21 +429,33 @@ func TestShouldReturnsStatusBadRequestWhenFailedUpdateUser(t *testing.T) {
 	refreshToken := "oirjnoinoiaec"
 
 	findQuery := utils.ReplaceQuotationForQuery(`
-		SELECT * FROM 'users'
+		SELECT * FROM 'sessions'
 		WHERE (remember_token = ?) AND (refresh_token = ?)
+		ORDER BY 'sessions'.'id' ASC
+		LIMIT 1`)
+
+	findUserQuery := utils.ReplaceQuotationForQuery(`
+		SELECT * FROM 'users'
+		WHERE ('users'.'id' = 1)
 		ORDER BY 'users'.'id' ASC
 		LIMIT 1`)
 
 	updateQuery := utils.ReplaceQuotationForQuery(`
-		UPDATE 'users'
-		SET 'expires_at' = ?, 'refresh_token' = ?, 'remember_token' = ?, 'updated_at' = ?
-		WHERE 'users'.'id' = ?`)
+		UPDATE 'sessions'
+		SET 'expires_at' = ?, 'last_seen_at' = ?, 'refresh_token' = ?, 'remember_token' = ?, 'updated_at' = ?
+		WHERE 'sessions'.'id' = ? AND ((refresh_token = ?))`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(findQuery)).
-		WithArgs(accessToken, refreshToken).
+		WithArgs(utils.DigestToken(accessToken), utils.DigestToken(refreshToken)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(2), uint(1)))
+
+	mock.ExpectQuery(regexp.QuoteMeta(findUserQuery)).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)))
 
 	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `rotated_refresh_tokens`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
 	mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
 		WillReturnError(errors.New("some error"))
 
@@ -346,14 +503,11 @@ func TestShouldReturnStatusOKWhenSucceedDeleteSession(t *testing.T) {
 
 	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
 
-	query := utils.ReplaceQuotationForQuery(`
-		UPDATE 'users'
-		SET 'expires_at' = ?, 'refresh_token' = ?, 'remember_token' = ?
-		WHERE (id = ?)`)
+	query := "DELETE FROM `sessions` WHERE (id = ? AND user_id = ?)"
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(nil, nil, nil, uint(1)).
+		WithArgs(uint(1), uint(1)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -381,14 +535,11 @@ func TestShouldReturnStatusBadRequestWhenFailedDeleteSession(t *testing.T) {
 
 	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
 
-	query := utils.ReplaceQuotationForQuery(`
-		UPDATE 'users'
-		SET 'expires_at' = ?, 'refresh_token' = ?, 'remember_token' = ?
-		WHERE (id = ?)`)
+	query := "DELETE FROM `sessions` WHERE (id = ? AND user_id = ?)"
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(nil, nil, nil, uint(1)).
+		WithArgs(uint(1), uint(1)).
 		WillReturnError(errors.New("some error"))
 
 	mock.ExpectRollback()
@@ -409,3 +560,263 @@ func TestShouldReturnStatusBadRequestWhenFailedDeleteSession(t *testing.T) {
 	assert.Equal(t, w.Code, 400)
 	assert.Equal(t, res["errors"][0].Text, repository.ErrorAuthenticationFailed)
 }
+
+func TestIndexSessionsHandlerShouldReturnsStatusOKWithSessions(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/sessions", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT id, created_at, device, user_agent, ip_address, last_seen_at, expires_at
+		FROM 'sessions'
+		WHERE (user_id = ?)
+		ORDER BY last_seen_at desc`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(uint(1)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "device", "user_agent", "ip_address"}).
+				AddRow(uint(1), "laptop", "Mozilla/5.0", "192.0.2.1").
+				AddRow(uint(2), "phone", "Mozilla/5.0", "192.0.2.2"))
+
+	r.GET("/sessions", h.IndexSessions)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]entity.Session{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, len(res["sessions"]), 2)
+	assert.Equal(t, res["sessions"][0].Device, "laptop")
+	assert.True(t, res["sessions"][0].Current)
+	assert.Equal(t, res["sessions"][1].Device, "phone")
+	assert.False(t, res["sessions"][1].Current)
+}
+
+func TestRevokeSessionHandlerShouldReturnsStatusOK(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/sessions/2", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions` WHERE (id = ? AND user_id = ?)")).
+		WithArgs(uint(2), uint(1)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	r.DELETE("/sessions/:sessionID", h.RevokeSession)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+}
+
+func TestRevokeSessionHandlerShouldReturnsStatusBadRequestWhenSessionDoesNotBelongToUser(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/sessions/2", nil)
+
+	utils.SetUpAuthentication(r, req, mock, h.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions` WHERE (id = ? AND user_id = ?)")).
+		WithArgs(uint(2), uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	r.DELETE("/sessions/:sessionID", h.RevokeSession)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorRecordNotFound)
+}
+
+func TestCreateSessionHandlerShouldReturnsStatusTooManyRequestsWhenSignInIsLocked(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/session", h.CreateSession)
+
+	b, err := json.Marshal(sessionRequestBody{Email: "gopher@sample.com", Password: "12345678"})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sign_in_throttles`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "locked_until"}).AddRow(uint(1), time.Now().Add(time.Minute*1)))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/session", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 429)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Equal(t, res["errors"][0].Text, ErrorSignInLocked)
+}
+
+func TestCreateSessionHandlerShouldRecordFailureWhenPasswordIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/session", h.CreateSession)
+
+	email := "gopher@sample.com"
+
+	b, err := json.Marshal(sessionRequestBody{Email: email, Password: "12345678"})
+
+	if err != nil {
+		t.Fatalf("fail to marshal json: %v", err)
+	}
+
+	passwordDigest, _ := bcrypt.GenerateFromPassword([]byte("87654321"), bcrypt.DefaultCost)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sign_in_throttles`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_digest"}).AddRow(uint(1), email, passwordDigest))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO sign_in_throttles")).
+		WithArgs("email:"+email, utils.AnyTime{}, utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sign_in_throttles`")).
+		WithArgs("email:" + email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "failures"}).AddRow(uint(1), 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `sign_in_throttles` SET `locked_until` = ?")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/session", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, res["errors"][0].Text, repository.ErrorInvalidPassword)
+}
+
+func TestCreateTesterSessionHandlerShouldReturnsStatusBadRequestWhenUserIsNotTestUser(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	h := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+	r.POST("/tester", h.CreateTesterSession)
+
+	email := "gopher@sample.com"
+	password := "12345678"
+
+	b, _ := json.Marshal(sessionRequestBody{Email: email, Password: password})
+
+	passwordDigest, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sign_in_throttles`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WithArgs(email).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "email", "password_digest"}).
+				AddRow(uint(1), email, passwordDigest))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sign_in_throttles`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/tester", bytes.NewReader(b))
+
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, repository.ErrorInvalidRequest, res["errors"][0].Text)
+}
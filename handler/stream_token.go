@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateStreamToken call a function that create a token to open a stream of events of a board.
+// EventSource of browsers can not send an auth header, so that the token is sent in query `stream_token` instead.
+// the token can be used once within a minute, and a new token is created when the stream is opened again.
+// if creation was successful, returns status 201 with the token.
+func (h UserHandler) CreateStreamToken(c *gin.Context) {
+	t, err := h.repository.CreateStreamToken(currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"stream_token": t})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/validator"
+)
+
+type totpCodeParams struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type disableTOTPParams struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type totpSessionParams struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+	Device         string `json:"device" binding:"max=100"`
+}
+
+// SetUpTOTP call a function that generate a TOTP secret of the login user.
+// returns status 200 with the secret and a provisioning URI that is shown as QR code by the client.
+func (h UserHandler) SetUpTOTP(c *gin.Context) {
+	u, err := h.repository.Find(currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	secret, uri, err := h.repository.SetUpTOTP(u)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"secret": secret, "provisioning_uri": uri})
+}
+
+// EnableTOTP call a function that enable two-factor authentication of the login user.
+// if a code was valid, returns status 200 and recovery codes as http response.
+// if a code was invalid, returns status 400 and error with messages.
+func (h UserHandler) EnableTOTP(c *gin.Context) {
+	var p totpCodeParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	u, err := h.repository.Find(currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	codes, err := h.repository.EnableTOTP(u, p.Code)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// DisableTOTP call a function that disable two-factor authentication of the login user.
+// if disabling was successful, returns status 200.
+// if disabling was failure, returns status 400 and error with messages.
+func (h UserHandler) DisableTOTP(c *gin.Context) {
+	var p disableTOTPParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	u, err := h.repository.Find(currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.DisableTOTP(u, p.Password, p.Code); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// CreateTOTPSession call a function that complete a sign in by a challenge token and a second factor.
+// returns access token, refresh token and expires if authentication was valid.
+func (h UserHandler) CreateTOTPSession(c *gin.Context) {
+	var p totpSessionParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	if h.rejectLockedSignIn(c, "") {
+		return
+	}
+
+	u, err := h.repository.PassSignInChallenge(p.ChallengeToken, p.Code)
+
+	if err != nil {
+		h.repository.RecordFailedSignIn(u.Email, c.ClientIP())
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	h.respondSession(c, u, p.Device)
+}
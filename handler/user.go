@@ -3,6 +3,7 @@ package handler
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -16,6 +17,7 @@ type userParams struct {
 	Email                string `json:"email" binding:"required,email"`
 	Password             string `json:"password" binding:"required,min=8,eqfield=PasswordConfirmation"`
 	PasswordConfirmation string `json:"password_confirmation" binding:"required"`
+	InvitationToken      string `json:"invitation_token"`
 }
 
 // UserHandler ...
@@ -42,11 +44,17 @@ func (h UserHandler) IndexTestUsers(c *gin.Context) {
 	var r []response
 
 	for _, u := range *us {
+		var e time.Time
+
+		if len(u.Sessions) > 0 {
+			e = u.Sessions[0].ExpiresAt
+		}
+
 		r = append(r, response{
 			ID:        u.ID,
 			Name:      u.Name,
 			Email:     u.Email,
-			ExpiresIn: utils.CalcExpiresIn(u.ExpiresAt),
+			ExpiresIn: utils.CalcExpiresIn(e),
 		})
 	}
 
@@ -54,7 +62,8 @@ func (h UserHandler) IndexTestUsers(c *gin.Context) {
 }
 
 // CreateUser call function that create a new record to users table.
-// if creation was successful, returns status 201 and a session token as http response.
+// if creation was successful, send a verification mail and returns status 201 and a session token as http response.
+// if an invitation token is given, the new user joins the board of the invitation.
 // if creation was failure, returns status 400 and error with messages.
 func (h UserHandler) CreateUser(c *gin.Context) {
 	var p userParams
@@ -64,6 +73,13 @@ func (h UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
+	if p.InvitationToken != "" {
+		if _, err := h.repository.FindInvitation(p.InvitationToken); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+			return
+		}
+	}
+
 	passwordDigest, err := h.repository.EncryptPassword(p.Password)
 
 	if err != nil {
@@ -72,7 +88,9 @@ func (h UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	if _, err := h.repository.Create(p.Name, p.Email, passwordDigest); err != nil {
+	nu, err := h.repository.Create(p.Name, p.Email, passwordDigest)
+
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
 		return
 	}
@@ -84,14 +102,43 @@ func (h UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(
-		http.StatusCreated,
-		gin.H{
-			"name":          u.Name,
-			"email":         u.Email,
-			"access_token":  u.RememberToken,
-			"refresh_token": u.RefreshToken,
-			"expires_in":    utils.CalcExpiresIn(u.ExpiresAt),
-		},
-	)
+	s, err := h.repository.CreateSession(u.ID, "", c.Request.UserAgent(), c.ClientIP())
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	at, err := accessToken(s)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	// the user can request the mail again, so that a failure does not cancel the sign up.
+	if err := h.sendVerificationMail(nu); err != nil {
+		log.Printf("fail to send verification mail on sign up: %v", err)
+	}
+
+	r := gin.H{
+		"name":          u.Name,
+		"email":         u.Email,
+		"verified":      false,
+		"session_id":    s.ID,
+		"access_token":  at,
+		"refresh_token": s.RefreshToken,
+		"expires_in":    utils.CalcExpiresIn(s.ExpiresAt),
+	}
+
+	// the invitation may be revoked or used up while signing up, so that a failure does not cancel the sign up.
+	if p.InvitationToken != "" {
+		if m, err := h.repository.AcceptInvitation(p.InvitationToken, u.ID); err != nil {
+			log.Printf("fail to accept invitation on sign up: %v", err)
+		} else {
+			r["board_id"] = m.BoardID
+		}
+	}
+
+	c.JSON(http.StatusCreated, r)
 }
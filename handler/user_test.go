@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
 
+	"local.packages/entity"
 	"local.packages/repository"
 	"local.packages/utils"
 	"local.packages/validator"
@@ -61,7 +62,16 @@ func TestShouldReturnsStatusCreatedWithSessionTokenUponUserSignUp(t *testing.T)
 				AddRow(uint(1), name, email, passwordDigest))
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET")).
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `sessions`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `one_time_tokens`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `one_time_tokens`")).
+		WithArgs(utils.AnyTime{}, uint(1), entity.PurposeEmailVerification, sqlmock.AnyArg(), utils.AnyTime{}, nil, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -84,6 +94,7 @@ func TestShouldReturnsStatusCreatedWithSessionTokenUponUserSignUp(t *testing.T)
 	assert.Equal(t, w.Code, 201)
 	assert.Equal(t, res["name"], name)
 	assert.Equal(t, res["email"], email)
+	assert.Equal(t, res["verified"], false)
 	assert.NotNil(t, res["access_token"])
 	assert.NotNil(t, res["refresh_token"])
 	assert.NotNil(t, res["expires_in"])
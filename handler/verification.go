@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/config"
+	"local.packages/entity"
+	"local.packages/mailer"
+	"local.packages/repository"
+	"local.packages/validator"
+)
+
+// VerifyEmail call a function that mark an email address as verified by a verification token.
+// if verification was successful, returns status 200.
+// if verification was failure, returns status 400 and error with messages.
+func (h UserHandler) VerifyEmail(c *gin.Context) {
+	p := struct {
+		Token string `form:"token" binding:"required"`
+	}{}
+
+	if err := c.ShouldBindQuery(&p); err != nil {
+		log.Printf("fail to bind query: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(ErrorInvalidParameter)})
+		return
+	}
+
+	if err := h.repository.VerifyEmail(p.Token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// ResendVerification send a verification mail to the login user again.
+// returns status 400 if an email address has already been verified.
+func (h UserHandler) ResendVerification(c *gin.Context) {
+	u, err := h.repository.Find(currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if u.VerifiedAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.NewValidationErrors(repository.ErrorAlreadyVerified)})
+		return
+	}
+
+	if err := h.sendVerificationMail(u); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h UserHandler) sendVerificationMail(u *entity.User) []validator.ValidationError {
+	t, err := h.repository.CreateVerificationToken(u.ID)
+
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/user/verify?token=%s", config.Config.Web.Origin, url.QueryEscape(t))
+
+	if err := mailer.Get().Send(mailer.NewVerificationMessage(u.Email, u.Name, link)); err != nil {
+		log.Printf("fail to send verification mail: %v", err)
+		return validator.NewValidationErrors(ErrorFailedSendMail)
+	}
+
+	return nil
+}
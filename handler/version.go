@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/repository"
+	"local.packages/validator"
+)
+
+// etag returns an entity tag of a version of a record.
+func etag(version uint) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// matchesIfMatch reports whether a version of a record matches an If-Match header of a request.
+// a request without the header or with "*" matches any version.
+func matchesIfMatch(c *gin.Context, version uint) bool {
+	h := c.GetHeader("If-Match")
+
+	if h == "" {
+		return true
+	}
+
+	for _, t := range strings.Split(h, ",") {
+		t = strings.TrimSpace(t)
+
+		if t == "*" || t == etag(version) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ifMatchVersion returns a version that a record must still have when it is deleted, or nil if a request has no If-Match header.
+func ifMatchVersion(c *gin.Context, version uint) *uint {
+	if c.GetHeader("If-Match") == "" {
+		return nil
+	}
+
+	return &version
+}
+
+// isConflict reports whether errors returned by a repository mean that a record was updated by another request.
+func isConflict(errs []validator.ValidationError) bool {
+	return len(errs) > 0 && errs[0].Text == repository.ErrorConflict
+}
+
+// preconditionFailed returns status 412 with the current representation of a record and an error with a message as http response.
+func preconditionFailed(c *gin.Context, key string, version uint, v interface{}) {
+	c.Header("ETag", etag(version))
+	c.JSON(http.StatusPreconditionFailed, gin.H{key: v, "errors": validator.NewValidationErrors(repository.ErrorConflict)})
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/entity"
+	"local.packages/repository"
+)
+
+// WatchHandler ...
+type WatchHandler struct {
+	repository *repository.WatchRepository
+}
+
+// NewWatchHandler is constructor for WatchHandler.
+func NewWatchHandler(r *repository.WatchRepository) *WatchHandler {
+	return &WatchHandler{repository: r}
+}
+
+// watchTarget returns a type and an id of a target to watch from a path parameter.
+func watchTarget(c *gin.Context) (string, uint) {
+	for _, t := range []string{entity.WatchTargetCard, entity.WatchTargetList, entity.WatchTargetBoard} {
+		if _, ok := c.Keys[t+"ID"]; ok {
+			return t, getIDParam(c, t+"ID")
+		}
+	}
+
+	return "", 0
+}
+
+// IndexWatches returns status 200 and boards, lists and cards that the login user watches as http response.
+func (h WatchHandler) IndexWatches(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"watches": h.repository.GetAll(currentUserID(c))})
+}
+
+// CreateWatch call a function that create a new record to watches table.
+// every member of the board can watch it, its lists and its cards.
+// if creation was successful, returns status 201 and instance of Watch as http response.
+// if creation was failure, returns status 400 and error with messages.
+func (h WatchHandler) CreateWatch(c *gin.Context) {
+	t, id := watchTarget(c)
+	uid := currentUserID(c)
+
+	if err := h.repository.ValidateUID(t, id, uid); err != nil {
+		log.Println("uid is not a member of the board associated with the target")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	w, err := h.repository.Create(t, id, uid)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"watch": w})
+}
+
+// DeleteWatch call a function that delete a record from watches table.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and errors with message.
+func (h WatchHandler) DeleteWatch(c *gin.Context) {
+	t, id := watchTarget(c)
+
+	if err := h.repository.Delete(t, id, currentUserID(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
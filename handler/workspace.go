@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"local.packages/entity"
+	"local.packages/repository"
+	"local.packages/validator"
+)
+
+type workspaceParams struct {
+	Name        string `json:"name" binding:"required"`
+	DefaultRole string `json:"default_role" binding:"required,oneof=editor viewer"`
+}
+
+type workspaceMemberParams struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=owner member"`
+}
+
+// WorkspaceHandler ...
+type WorkspaceHandler struct {
+	repository *repository.WorkspaceRepository
+}
+
+// NewWorkspaceHandler is constructor for WorkspaceHandler.
+func NewWorkspaceHandler(r *repository.WorkspaceRepository) *WorkspaceHandler {
+	return &WorkspaceHandler{repository: r}
+}
+
+func workspaceMemberResponse(m *entity.WorkspaceMember) gin.H {
+	r := gin.H{
+		"user_id": m.UserID,
+		"role":    m.Role,
+	}
+
+	if m.User != nil {
+		r["name"] = m.User.Name
+		r["email"] = m.User.Email
+	}
+
+	return r
+}
+
+// CreateWorkspace call a function that create a new record to workspaces table.
+// the login user becomes an owner of the workspace.
+// if creation was successful, returns status 201 and instance of Workspace as http response.
+// if creation was failure, returns status 400 and error with messages.
+func (h WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	var p workspaceParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	w, err := h.repository.Create(p.Name, p.DefaultRole, currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"workspace": w})
+}
+
+// IndexWorkspaces returns status 200 and workspaces that the login user is a member of as http response.
+func (h WorkspaceHandler) IndexWorkspaces(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"workspaces": h.repository.GetAll(currentUserID(c))})
+}
+
+// UpdateWorkspace call a function that update a name and a default role of a workspace.
+// only an owner of the workspace can change them.
+// if update was successful, returns status 200 and updated instance of Workspace as http response.
+// if update was failure, returns status 400 and error with messages.
+func (h WorkspaceHandler) UpdateWorkspace(c *gin.Context) {
+	var p workspaceParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	wid := getIDParam(c, "workspaceID")
+
+	if err := h.repository.ValidateUID(wid, currentUserID(c)); err != nil {
+		log.Println("uid is not an owner of the workspace")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	w, err := h.repository.Find(wid, currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.Update(w, p.Name, p.DefaultRole); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"workspace": w})
+}
+
+// DeleteWorkspace call a function that delete a workspace.
+// only an owner of the workspace can delete it. boards of the workspace are kept.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and errors with message.
+func (h WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	wid := getIDParam(c, "workspaceID")
+
+	if err := h.repository.ValidateUID(wid, currentUserID(c)); err != nil {
+		log.Println("uid is not an owner of the workspace")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.Delete(wid); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// IndexWorkspaceMembers returns status 200 and members of a workspace as http response.
+func (h WorkspaceHandler) IndexWorkspaceMembers(c *gin.Context) {
+	ms := h.repository.GetMembers(getIDParam(c, "workspaceID"), currentUserID(c))
+
+	r := []gin.H{}
+
+	for i := range *ms {
+		r = append(r, workspaceMemberResponse(&(*ms)[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": r})
+}
+
+// CreateWorkspaceMember call a function that add the user who has an email address to members of a workspace.
+// only an owner of the workspace can add members. the user joins boards of the workspace with the default role.
+// if creation was successful, returns status 201 and the member as http response.
+// if creation was failure, returns status 400 and error with messages.
+func (h WorkspaceHandler) CreateWorkspaceMember(c *gin.Context) {
+	var p workspaceMemberParams
+
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": validator.FormattedValidationError(err)})
+		return
+	}
+
+	wid := getIDParam(c, "workspaceID")
+
+	if err := h.repository.ValidateUID(wid, currentUserID(c)); err != nil {
+		log.Println("uid is not an owner of the workspace")
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	w, err := h.repository.Find(wid, currentUserID(c))
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	m, err := h.repository.CreateMember(w, p.Email, p.Role)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"member": workspaceMemberResponse(m)})
+}
+
+// DeleteWorkspaceMember call a function that remove a member from a workspace.
+// an owner of the workspace can remove any member, and every member can leave the workspace by oneself.
+// if deletion was successful, returns status 200.
+// if deletion was failure, returns status 400 and error with messages.
+func (h WorkspaceHandler) DeleteWorkspaceMember(c *gin.Context) {
+	wid := getIDParam(c, "workspaceID")
+	uid := getIDParam(c, "userID")
+
+	if uid != currentUserID(c) {
+		if err := h.repository.ValidateUID(wid, currentUserID(c)); err != nil {
+			log.Println("uid is not an owner of the workspace")
+			c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+			return
+		}
+	}
+
+	m, err := h.repository.FindMember(wid, uid)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	if err := h.repository.DeleteMember(m); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": err})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/repository"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestCreateWorkspaceHandlerShouldReturnsStatusCreatedWithWorkspace(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	wh := NewWorkspaceHandler(repository.NewWorkspaceRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(workspaceParams{Name: "team", DefaultRole: "editor"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/workspace", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `workspaces`")).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `workspace_members`")).
+		WithArgs(uint(3), uint(1), "owner", utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.POST("/workspace", wh.CreateWorkspace)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string]map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 201)
+	assert.Equal(t, float64(3), res["workspace"]["id"])
+	assert.Equal(t, "editor", res["workspace"]["default_role"])
+}
+
+func TestCreateWorkspaceHandlerShouldReturnsStatusBadRequestWithInvalidDefaultRole(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	wh := NewWorkspaceHandler(repository.NewWorkspaceRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(workspaceParams{Name: "team", DefaultRole: "owner"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/workspace", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	r.POST("/workspace", wh.CreateWorkspace)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+}
+
+func TestCreateWorkspaceMemberHandlerShouldReturnsStatusBadRequestWhenUserIsNotOwner(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	wh := NewWorkspaceHandler(repository.NewWorkspaceRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	b, _ := json.Marshal(workspaceMemberParams{Email: "gopher@sample.com", Role: "member"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/workspace/3/member", bytes.NewReader(b))
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT id FROM 'workspaces'
+		WHERE (workspaces.id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ? AND role IN (?))) AND ('workspaces'.'id' = 3)`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(uint(1), "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	r.POST("/workspace/:workspaceID/member", wh.CreateWorkspaceMember)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]validator.ValidationError{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 400)
+	assert.Equal(t, repository.ErrorInvalidSession, res["errors"][0].Text)
+}
+
+func TestIndexBoardHandlerShouldFilterBoardsByWorkspace(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	bh := NewBoardHandler(repository.NewBoardRepository(db))
+	uh := NewUserHandler(repository.NewUserRepository(db))
+
+	r := utils.SetUpRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/boards?workspace_id=3", nil)
+
+	utils.SetUpAuthentication(r, req, mock, uh.Authenticate(), MapIDParamsToContext())
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, updated_at, name, user_id, workspace_id, version FROM `boards`")).
+		WithArgs(uint(1), "owner", "editor", "viewer", uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "workspace_id"}).AddRow(uint(5), "board", uint(3)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `board_background_images`")).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id", "background_image_id"}))
+
+	r.GET("/boards", bh.IndexBoard)
+	r.ServeHTTP(w, req)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	res := map[string][]map[string]interface{}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("fail to unmarshal response body. %v", err)
+	}
+
+	assert.Equal(t, w.Code, 200)
+	assert.Equal(t, float64(3), res["boards"][0]["workspace_id"])
+}
@@ -0,0 +1,156 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"local.packages/config"
+)
+
+var (
+	// ErrInvalid is returned when a token is malformed or its signature is invalid.
+	ErrInvalid = errors.New("jwt: invalid token")
+	// ErrExpired is returned when a token has a valid signature but has expired.
+	ErrExpired = errors.New("jwt: token has expired")
+	// ErrNoKey is returned when a signing key is not specified in config file.
+	ErrNoKey = errors.New("jwt: signing key is not configured")
+)
+
+// Claims are claims of an access token that identifies a session of a user.
+type Claims struct {
+	Subject   string `json:"sub"`
+	SessionID uint   `json:"sid"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// NewClaims returns claims for a session of a user that expire at the time.
+func NewClaims(uid, sid uint, expiresAt time.Time) Claims {
+	return Claims{
+		Subject:   strconv.FormatUint(uint64(uid), 10),
+		SessionID: sid,
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	}
+}
+
+// UserID returns an id of the user who is the subject of the claims.
+func (c *Claims) UserID() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 64)
+	return uint(id), err
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// Enabled check if access tokens are signed instead of stored in database.
+func Enabled() bool {
+	return config.Config.Token.Signed && secretOf(config.Config.Token.KeyID) != ""
+}
+
+// IsSigned check if a token has the form of JWS compact serialization.
+func IsSigned(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// Sign returns a token that contains the claims and is signed by HS256 with the current key.
+func Sign(c Claims) (string, error) {
+	kid := config.Config.Token.KeyID
+	secret := secretOf(kid)
+
+	if secret == "" {
+		return "", ErrNoKey
+	}
+
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT", Kid: kid})
+
+	if err != nil {
+		return "", err
+	}
+
+	p, err := json.Marshal(c)
+
+	if err != nil {
+		return "", err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(p)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign(signed, secret)), nil
+}
+
+// Verify validates a signature of a token and returns its claims.
+// tokens signed by any key in config file are accepted, so that keys can be rotated without signing out users.
+// if the token has expired, the claims are returned with ErrExpired.
+func Verify(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != 3 {
+		return nil, ErrInvalid
+	}
+
+	var h header
+
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != "HS256" {
+		return nil, ErrInvalid
+	}
+
+	secret := secretOf(h.Kid)
+
+	if secret == "" {
+		return nil, ErrInvalid
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+
+	if err != nil || !hmac.Equal(sig, sign(parts[0]+"."+parts[1], secret)) {
+		return nil, ErrInvalid
+	}
+
+	var c Claims
+
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, ErrInvalid
+	}
+
+	if now.Unix() >= c.ExpiresAt {
+		return &c, ErrExpired
+	}
+
+	return &c, nil
+}
+
+// secretOf returns a secret of a key id.
+// keys of a map are lower cased by config loader, so that a key id is compared case-insensitively.
+func secretOf(kid string) string {
+	if kid == "" {
+		return ""
+	}
+
+	return config.Config.Token.Keys[strings.ToLower(kid)]
+}
+
+func sign(s, secret string) []byte {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(s))
+	return m.Sum(nil)
+}
+
+func decodeSegment(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,115 @@
+package jwt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/config"
+)
+
+func setUpKeys(kid string, keys map[string]string) func() {
+	config.Config.Token.Signed = true
+	config.Config.Token.KeyID = kid
+	config.Config.Token.Keys = keys
+
+	return func() {
+		config.Config.Token.Signed = false
+		config.Config.Token.KeyID = ""
+		config.Config.Token.Keys = nil
+	}
+}
+
+func TestShouldSignAndVerifyClaims(t *testing.T) {
+	defer setUpKeys("2026a", map[string]string{"2026a": "secret"})()
+
+	assert.True(t, Enabled())
+
+	token, err := Sign(NewClaims(uint(1), uint(2), time.Now().Add(time.Hour)))
+
+	if err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	c, err := Verify(token, time.Now())
+
+	if err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	uid, _ := c.UserID()
+
+	assert.True(t, IsSigned(token))
+	assert.Equal(t, uint(1), uid)
+	assert.Equal(t, uint(2), c.SessionID)
+}
+
+func TestShouldReturnClaimsWithErrExpiredWhenTokenHasExpired(t *testing.T) {
+	defer setUpKeys("2026a", map[string]string{"2026a": "secret"})()
+
+	token, _ := Sign(NewClaims(uint(1), uint(2), time.Now().Add(time.Hour)))
+
+	c, err := Verify(token, time.Now().Add(time.Hour*2))
+
+	assert.Equal(t, ErrExpired, err)
+	assert.Equal(t, uint(2), c.SessionID)
+}
+
+func TestShouldVerifyTokenSignedByPreviousKeyAfterRotation(t *testing.T) {
+	restore := setUpKeys("2026a", map[string]string{"2026a": "old secret"})
+	defer restore()
+
+	token, _ := Sign(NewClaims(uint(1), uint(2), time.Now().Add(time.Hour)))
+
+	config.Config.Token.KeyID = "2026B"
+	config.Config.Token.Keys = map[string]string{"2026a": "old secret", "2026b": "new secret"}
+
+	if _, err := Verify(token, time.Now()); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	rotated, _ := Sign(NewClaims(uint(1), uint(2), time.Now().Add(time.Hour)))
+
+	if _, err := Verify(rotated, time.Now()); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	// the previous key is removed after access tokens signed by it have expired.
+	config.Config.Token.Keys = map[string]string{"2026b": "new secret"}
+
+	_, err := Verify(token, time.Now())
+
+	assert.Equal(t, ErrInvalid, err)
+}
+
+func TestShouldFailureVerifyWhenTokenIsTampered(t *testing.T) {
+	defer setUpKeys("2026a", map[string]string{"2026a": "secret"})()
+
+	token, _ := Sign(NewClaims(uint(1), uint(2), time.Now().Add(time.Hour)))
+	forged, _ := Sign(NewClaims(uint(3), uint(2), time.Now().Add(time.Hour)))
+
+	parts := strings.Split(token, ".")
+	parts[1] = strings.Split(forged, ".")[1]
+
+	_, err := Verify(strings.Join(parts, "."), time.Now())
+
+	assert.Equal(t, ErrInvalid, err)
+
+	config.Config.Token.Keys = map[string]string{"2026a": "another secret"}
+
+	_, err = Verify(token, time.Now())
+
+	assert.Equal(t, ErrInvalid, err)
+}
+
+func TestShouldNotBeEnabledWithoutSigningKey(t *testing.T) {
+	defer setUpKeys("2026a", map[string]string{})()
+
+	assert.False(t, Enabled())
+
+	_, err := Sign(NewClaims(uint(1), uint(2), time.Now().Add(time.Hour)))
+
+	assert.Equal(t, ErrNoKey, err)
+}
@@ -0,0 +1,124 @@
+package mailer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"local.packages/config"
+)
+
+// Message represents an email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer is the interface that wraps the Send method.
+type Mailer interface {
+	Send(m *Message) error
+}
+
+// SMTPMailer sends messages via SMTP server.
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPMailer is constructor for SMTPMailer.
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+// Send sends a message to SMTP server.
+func (s *SMTPMailer) Send(m *Message) error {
+	var auth smtp.Auth
+
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	addr := fmt.Sprintf("%s:%d", s.Host, s.Port)
+
+	return smtp.SendMail(addr, auth, s.From, []string{m.To}, format(s.From, m))
+}
+
+// FileMailer writes messages to files in a directory instead of sending them.
+// writes messages to log if a directory is not specified.
+// it is intended for development and testing.
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+// NewFileMailer is constructor for FileMailer.
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{
+		Dir:  dir,
+		From: from,
+	}
+}
+
+// Send writes a message to a file or log.
+func (f *FileMailer) Send(m *Message) error {
+	b := format(f.From, m)
+
+	if f.Dir == "" {
+		log.Printf("mail:\n%s", b)
+		return nil
+	}
+
+	if err := os.MkdirAll(f.Dir, 0755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(m.To))
+
+	return ioutil.WriteFile(filepath.Join(f.Dir, name), b, 0644)
+}
+
+var mailer Mailer
+
+func init() {
+	c := config.Config.Mail
+
+	switch c.Driver {
+	case "smtp":
+		mailer = NewSMTPMailer(c.Host, c.Port, c.Username, c.Password, c.From)
+	default:
+		mailer = NewFileMailer(c.Dir, c.From)
+	}
+}
+
+// Get returns an instance of Mailer that was selected by config file.
+func Get() Mailer {
+	return mailer
+}
+
+func format(from string, m *Message) []byte {
+	h := []string{
+		fmt.Sprintf("From: %s", from),
+		fmt.Sprintf("To: %s", m.To),
+		fmt.Sprintf("Subject: %s", mime.QEncoding.Encode("UTF-8", m.Subject)),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+
+	return []byte(strings.Join(h, "\r\n") + "\r\n\r\n" + m.Body)
+}
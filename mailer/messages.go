@@ -0,0 +1,55 @@
+package mailer
+
+import "fmt"
+
+// NewPasswordResetMessage returns a message that contains an URL to reset password.
+func NewPasswordResetMessage(to, name, url string) *Message {
+	return &Message{
+		To:      to,
+		Subject: "【kanban】パスワード再設定のご案内",
+		Body: fmt.Sprintf(`%s 様
+
+パスワード再設定のリクエストを受け付けました。
+以下のURLから1時間以内に新しいパスワードを設定してください。
+
+%s
+
+このメールに心当たりがない場合は破棄してください。パスワードは変更されません。
+`, name, url),
+	}
+}
+
+// NewVerificationMessage returns a message that contains an URL to verify an email address.
+func NewVerificationMessage(to, name, url string) *Message {
+	return &Message{
+		To:      to,
+		Subject: "【kanban】メールアドレスの確認",
+		Body: fmt.Sprintf(`%s 様
+
+kanbanへのご登録ありがとうございます。
+以下のURLから24時間以内にメールアドレスの確認を完了してください。
+
+%s
+
+このメールに心当たりがない場合は破棄してください。
+`, name, url),
+	}
+}
+
+// NewMentionMessage returns a message that tells a user was mentioned on a card.
+func NewMentionMessage(to, name, actor, excerpt, url string) *Message {
+	return &Message{
+		To:      to,
+		Subject: fmt.Sprintf("【kanban】%s さんがあなたをメンションしました", actor),
+		Body: fmt.Sprintf(`%s 様
+
+%s さんがカードであなたをメンションしました。
+
+%s
+
+以下のURLからカードを確認できます。
+
+%s
+`, name, actor, excerpt, url),
+	}
+}
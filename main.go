@@ -19,12 +19,20 @@ var (
 	listHandler                 *handler.ListHandler
 	cardHandler                 *handler.CardHandler
 	cardLabelHandler            *handler.CardLabelHandler
+	cardAssigneeHandler         *handler.CardAssigneeHandler
+	commentHandler              *handler.CommentHandler
 	checkListHandler            *handler.CheckListHandler
 	checkListItemHandler        *handler.CheckListItemHandler
 	fileHandler                 *handler.FileHandler
 	coverHandler                *handler.CoverHandler
 	backgroundImageHandler      *handler.BackgroundImageHandler
 	boardBackgroundImageHandler *handler.BoardBackgroundImageHandler
+	boardMemberHandler          *handler.BoardMemberHandler
+	workspaceHandler            *handler.WorkspaceHandler
+	notificationHandler         *handler.NotificationHandler
+	watchHandler                *handler.WatchHandler
+	activityHandler             *handler.ActivityHandler
+	presenceHandler             *handler.PresenceHandler
 )
 
 func main() {
@@ -39,12 +47,20 @@ func main() {
 	listHandler = handler.NewListHandler(repository.NewListRepository(db))
 	cardHandler = handler.NewCardHandler(repository.NewCardRepository(db))
 	cardLabelHandler = handler.NewCardLabelHandler(repository.NewCardLabelRepository(db))
+	cardAssigneeHandler = handler.NewCardAssigneeHandler(repository.NewCardAssigneeRepository(db))
+	commentHandler = handler.NewCommentHandler(repository.NewCommentRepository(db))
 	checkListHandler = handler.NewCheckListHandler(repository.NewCheckListRepository(db))
 	checkListItemHandler = handler.NewCheckListItemHandler(repository.NewCheckListItemRepository(db))
 	fileHandler = handler.NewFileHandler(repository.NewFileRepository(db))
 	coverHandler = handler.NewCoverHandler(repository.NewCoverRepository(db))
 	backgroundImageHandler = handler.NewBackgroundImageHandler(repository.NewBackgroundImageRepository(db))
 	boardBackgroundImageHandler = handler.NewBoardBackgroundImageHandler(repository.NewBoardBackgroundImageRepository(db))
+	boardMemberHandler = handler.NewBoardMemberHandler(repository.NewBoardMemberRepository(db))
+	workspaceHandler = handler.NewWorkspaceHandler(repository.NewWorkspaceRepository(db))
+	notificationHandler = handler.NewNotificationHandler(repository.NewNotificationRepository(db))
+	watchHandler = handler.NewWatchHandler(repository.NewWatchRepository(db))
+	activityHandler = handler.NewActivityHandler(repository.NewActivityRepository(db), repository.NewPresenceRepository(db))
+	presenceHandler = handler.NewPresenceHandler(repository.NewPresenceRepository(db))
 
 	migration.Migrate()
 	startServer()
@@ -62,21 +78,62 @@ func startServer() {
 	rejectTesterSignIn := r.Group("/", userHandler.RejectTester())
 
 	r.GET("/testers", userHandler.IndexTestUsers)
-	testerSignIn.POST("/tester", userHandler.CreateSession)
+	testerSignIn.POST("/tester", userHandler.CreateTesterSession)
 
 	r.POST("/user", userHandler.CreateUser)
+	authorized.GET("/user", userHandler.ShowUser)
+	authorized.PATCH("/user", handler.RejectAccessToken(), userHandler.RejectTestUserAccount(), userHandler.UpdateUser)
+	authorized.PUT("/user/password", handler.RejectAccessToken(), userHandler.RejectTestUserAccount(), userHandler.UpdatePassword)
+	authorized.DELETE("/user", handler.RejectAccessToken(), userHandler.RejectTestUserAccount(), userHandler.DeleteUser)
+	r.GET("/user/verify", userHandler.VerifyEmail)
+	authorized.POST("/user/verification", handler.RejectAccessToken(), userHandler.ResendVerification)
+
+	authorized.POST("/user/totp", handler.RejectAccessToken(), userHandler.RejectTestUserAccount(), userHandler.SetUpTOTP)
+	authorized.POST("/user/totp/enable", handler.RejectAccessToken(), userHandler.RejectTestUserAccount(), userHandler.EnableTOTP)
+	authorized.DELETE("/user/totp", handler.RejectAccessToken(), userHandler.DisableTOTP)
+
+	authorized.GET("/access_tokens", handler.RejectAccessToken(), userHandler.IndexAccessTokens)
+	authorized.POST("/access_tokens", handler.RejectAccessToken(), userHandler.RejectTestUserAccount(), userHandler.CreateAccessToken)
+	authorized.DELETE("/access_tokens/:accessTokenID", handler.RejectAccessToken(), userHandler.RevokeAccessToken)
+
+	r.POST("/password/forgot", userHandler.ForgotPassword)
+	r.POST("/password/reset", userHandler.ResetPassword)
 
 	rejectTesterSignIn.POST("/session", userHandler.CreateSession)
+	r.POST("/session/totp", userHandler.CreateTOTPSession)
+	r.GET("/oidc/authorize", userHandler.AuthorizeOIDC)
+	r.POST("/session/oidc", userHandler.CreateOIDCSession)
 	r.PATCH("/session", userHandler.UpdateSession)
-	authorized.DELETE("/session", userHandler.DeleteSession)
-
-	authorized.POST("/board", boardHandler.CreateBoard)
+	authorized.DELETE("/session", handler.RejectAccessToken(), userHandler.DeleteSession)
+	authorized.GET("/sessions", handler.RejectAccessToken(), userHandler.IndexSessions)
+	authorized.DELETE("/sessions/:sessionID", handler.RejectAccessToken(), userHandler.RevokeSession)
+
+	authorized.POST("/workspace", handler.RejectAccessToken(), userHandler.RequireVerified(), workspaceHandler.CreateWorkspace)
+	authorized.GET("/workspaces", workspaceHandler.IndexWorkspaces)
+	authorized.PATCH("/workspace/:workspaceID", handler.RejectAccessToken(), workspaceHandler.UpdateWorkspace)
+	authorized.DELETE("/workspace/:workspaceID", handler.RejectAccessToken(), workspaceHandler.DeleteWorkspace)
+	authorized.GET("/workspace/:workspaceID/members", workspaceHandler.IndexWorkspaceMembers)
+	authorized.POST("/workspace/:workspaceID/member", handler.RejectAccessToken(), workspaceHandler.CreateWorkspaceMember)
+	authorized.DELETE("/workspace/:workspaceID/member/:userID", handler.RejectAccessToken(), workspaceHandler.DeleteWorkspaceMember)
+
+	authorized.POST("/board", userHandler.RequireVerified(), boardHandler.CreateBoard)
 	authorized.GET("/boards", boardHandler.IndexBoard)
 	authorized.GET("/board/:boardID", boardHandler.ShowBoard)
 	authorized.PATCH("/board/:boardID", boardHandler.UpdateBoard)
+	authorized.PATCH("/board/:boardID/workspace", handler.RejectAccessToken(), boardHandler.UpdateBoardWorkspace)
 	authorized.DELETE("/board/:boardID", boardHandler.DeleteBoard)
 	authorized.GET("/boards/search", boardHandler.SearchBoard)
 
+	authorized.POST("/board/:boardID/member", handler.RejectAccessToken(), boardMemberHandler.CreateBoardMember)
+	authorized.GET("/board/:boardID/members", boardMemberHandler.IndexBoardMembers)
+	authorized.GET("/board/:boardID/members/search", boardMemberHandler.SearchMentionMembers)
+	authorized.PATCH("/board/:boardID/member/:userID", handler.RejectAccessToken(), boardMemberHandler.UpdateBoardMember)
+	authorized.DELETE("/board/:boardID/member/:userID", handler.RejectAccessToken(), boardMemberHandler.DeleteBoardMember)
+	authorized.POST("/board/:boardID/invitations", handler.RejectAccessToken(), boardMemberHandler.CreateInvitation)
+	authorized.GET("/board/:boardID/invitations", handler.RejectAccessToken(), boardMemberHandler.IndexInvitations)
+	authorized.DELETE("/board/:boardID/invitations/:invitationID", handler.RejectAccessToken(), boardMemberHandler.RevokeInvitation)
+	authorized.POST("/invitations/accept", handler.RejectAccessToken(), userHandler.AcceptInvitation)
+
 	authorized.POST("/board/:boardID/label", labelHandler.CreateLabel)
 	authorized.GET("/board/:boardID/labels", labelHandler.IndexLabel)
 	authorized.PATCH("/label/:labelID", labelHandler.UpdateLabel)
@@ -86,16 +143,47 @@ func startServer() {
 	authorized.PATCH("/list/:listID", listHandler.UpdateList)
 	authorized.PATCH("/lists/index", listHandler.UpdateListIndex)
 	authorized.DELETE("/list/:listID", listHandler.DeleteList)
+	authorized.POST("/list/:listID/restore", listHandler.RestoreList)
 
 	authorized.POST("/list/:listID/card", cardHandler.CreateCard)
 	authorized.PATCH("/card/:cardID/:attribute", cardHandler.UpdateCard)
 	authorized.PATCH("/cards/index", cardHandler.UpdateCardIndex)
 	authorized.DELETE("/card/:cardID", cardHandler.DeleteCard)
+	authorized.POST("/card/:cardID/restore", cardHandler.RestoreCard)
 	authorized.GET("/cards/search", cardHandler.SearchCard)
+	authorized.GET("/me/due", cardHandler.IndexDueCards)
 
 	authorized.POST("/card/:cardID/card_label", cardLabelHandler.CreateCardLabel)
 	authorized.DELETE("/card/:cardID/card_label/:labelID", cardLabelHandler.DeleteCardLabel)
 
+	authorized.POST("/card/:cardID/assignee", cardAssigneeHandler.CreateCardAssignee)
+	authorized.DELETE("/card/:cardID/assignee/:userID", cardAssigneeHandler.DeleteCardAssignee)
+	authorized.GET("/me/cards", cardAssigneeHandler.IndexAssignedCards)
+
+	authorized.GET("/card/:cardID/comments", commentHandler.IndexComments)
+	authorized.POST("/card/:cardID/comment", commentHandler.CreateComment)
+	authorized.PATCH("/comment/:commentID", commentHandler.UpdateComment)
+	authorized.DELETE("/comment/:commentID", commentHandler.DeleteComment)
+
+	authorized.GET("/notifications", notificationHandler.IndexNotifications)
+	authorized.PATCH("/notification/:notificationID/read", notificationHandler.ReadNotification)
+	authorized.PATCH("/notifications/read", notificationHandler.ReadAllNotifications)
+
+	authorized.GET("/me/watches", watchHandler.IndexWatches)
+	authorized.POST("/board/:boardID/watch", watchHandler.CreateWatch)
+	authorized.DELETE("/board/:boardID/watch", watchHandler.DeleteWatch)
+	authorized.POST("/list/:listID/watch", watchHandler.CreateWatch)
+	authorized.DELETE("/list/:listID/watch", watchHandler.DeleteWatch)
+	authorized.POST("/card/:cardID/watch", watchHandler.CreateWatch)
+	authorized.DELETE("/card/:cardID/watch", watchHandler.DeleteWatch)
+
+	authorized.GET("/board/:boardID/activities", activityHandler.IndexBoardActivities)
+	authorized.POST("/stream_token", handler.RejectAccessToken(), userHandler.CreateStreamToken)
+	r.GET("/board/:boardID/events", userHandler.AuthenticateStream(), activityHandler.StreamBoardActivities)
+	authorized.GET("/board/:boardID/presence", presenceHandler.IndexPresence)
+	authorized.PATCH("/board/:boardID/presence", presenceHandler.UpdatePresence)
+	authorized.GET("/card/:cardID/activities", activityHandler.IndexCardActivities)
+
 	authorized.POST("/card/:cardID/check_list", checkListHandler.CreateCheckList)
 	authorized.PATCH("/check_list/:checkListID", checkListHandler.UpdateCheckList)
 	authorized.DELETE("/check_list/:checkListID", checkListHandler.DeleteCheckList)
@@ -117,5 +205,19 @@ func startServer() {
 
 	authorized.PATCH("/board/:boardID/background_image/:backgroundImageID", boardBackgroundImageHandler.UpdateBoardBackgroundImage)
 
+	admin := authorized.Group("/admin", handler.RejectAccessToken(), userHandler.RequireAdmin())
+
+	admin.GET("/users", userHandler.IndexUsers)
+	admin.PATCH("/user/:userID/disable", userHandler.DisableUser)
+	admin.PATCH("/user/:userID/enable", userHandler.EnableUser)
+	admin.PATCH("/user/:userID/role", userHandler.UpdateUserRole)
+	admin.DELETE("/user/:userID/sessions", userHandler.SignOutUser)
+	admin.DELETE("/user/:userID/lock", userHandler.UnlockUser)
+	admin.POST("/user/:userID/password_reset", userHandler.ResetUserPassword)
+
+	admin.POST("/background_image", backgroundImageHandler.CreateBackgroundImage)
+	admin.PATCH("/background_image/:backgroundImageID", backgroundImageHandler.UpdateBackgroundImage)
+	admin.DELETE("/background_image/:backgroundImageID", backgroundImageHandler.DeleteBackgroundImage)
+
 	r.Run(fmt.Sprintf(":%v", config.Config.Web.Port))
 }
@@ -9,13 +9,37 @@ import (
 func Migrate() {
 	db := db.Get()
 
+	// users who signed up before email verification was introduced are regarded as verified.
+	grandfatherVerification := db.HasTable(&entity.User{}) && !db.Dialect().HasColumn("users", "verified_at")
+
+	// boards that were created before board sharing was introduced are owned by their creators.
+	grandfatherMembers := db.HasTable(&entity.Board{}) && !db.HasTable(&entity.BoardMember{})
+
 	db.AutoMigrate(
 		&entity.User{},
+		&entity.Session{},
+		&entity.RotatedRefreshToken{},
+		&entity.OneTimeToken{},
+		&entity.AccessToken{},
+		&entity.RecoveryCode{},
+		&entity.SignInThrottle{},
+		&entity.Identity{},
+		&entity.OIDCAuthRequest{},
+		&entity.Workspace{},
+		&entity.WorkspaceMember{},
 		&entity.Board{},
+		&entity.BoardMember{},
+		&entity.Invitation{},
 		&entity.List{},
 		&entity.Card{},
 		&entity.Label{},
 		&entity.CardLabel{},
+		&entity.CardAssignee{},
+		&entity.Comment{},
+		&entity.Mention{},
+		&entity.Notification{},
+		&entity.Watch{},
+		&entity.Activity{},
 		&entity.CheckList{},
 		&entity.CheckListItem{},
 		&entity.File{},
@@ -24,13 +48,58 @@ func Migrate() {
 		&entity.BoardBackgroundImage{},
 	)
 
+	if grandfatherVerification {
+		db.Exec("UPDATE users SET verified_at = created_at WHERE verified_at IS NULL")
+	}
+
+	if grandfatherMembers {
+		db.Exec("INSERT INTO board_members (board_id, user_id, role, created_at) SELECT id, user_id, 'owner', created_at FROM boards")
+	}
+
+	// session tokens were moved from users table to sessions table.
+	for _, c := range []string{"remember_token", "refresh_token", "expires_at"} {
+		if db.Dialect().HasColumn("users", c) {
+			db.Model(&entity.User{}).DropColumn(c)
+		}
+	}
+
+	// session tokens were stored in plain text before only their digests are stored.
+	db.Exec("UPDATE sessions SET remember_token = SHA2(remember_token, 256), refresh_token = SHA2(refresh_token, 256) WHERE CHAR_LENGTH(refresh_token) <> 64")
+
+	// notifications were only about cards before lists and boards could be watched.
+	db.Model(&entity.Notification{}).ModifyColumn("card_id", "int unsigned NULL")
+
+	db.Model(&entity.Session{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.RotatedRefreshToken{}).AddForeignKey("session_id", "sessions(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.OneTimeToken{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
 	db.Model(&entity.Board{}).AddForeignKey("user_id", "users(id)", "RESTRICT", "RESTRICT")
+	db.Model(&entity.Board{}).AddForeignKey("workspace_id", "workspaces(id)", "SET NULL", "RESTRICT")
+	db.Model(&entity.WorkspaceMember{}).AddForeignKey("workspace_id", "workspaces(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.WorkspaceMember{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.BoardMember{}).AddForeignKey("board_id", "boards(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.BoardMember{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Invitation{}).AddForeignKey("board_id", "boards(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.RecoveryCode{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Identity{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.AccessToken{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.AccessToken{}).AddForeignKey("board_id", "boards(id)", "CASCADE", "RESTRICT")
 	db.Model(&entity.Label{}).AddForeignKey("board_id", "boards(id)", "RESTRICT", "RESTRICT")
 	db.Model(&entity.List{}).AddForeignKey("board_id", "boards(id)", "RESTRICT", "RESTRICT")
 	db.Model(&entity.CardLabel{}).AddForeignKey("card_id", "cards(id)", "RESTRICT", "RESTRICT")
 	db.Model(&entity.CardLabel{}).AddForeignKey("label_id", "labels(id)", "RESTRICT", "RESTRICT")
 	db.Model(&entity.CardLabel{}).AddForeignKey("label_id", "labels(id)", "RESTRICT", "RESTRICT")
 	db.Model(&entity.CardLabel{}).AddForeignKey("label_id", "labels(id)", "RESTRICT", "RESTRICT")
+	db.Model(&entity.CardAssignee{}).AddForeignKey("card_id", "cards(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.CardAssignee{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Comment{}).AddForeignKey("card_id", "cards(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Comment{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Mention{}).AddForeignKey("card_id", "cards(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Mention{}).AddForeignKey("comment_id", "comments(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Mention{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Notification{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Notification{}).AddForeignKey("board_id", "boards(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Watch{}).AddForeignKey("user_id", "users(id)", "CASCADE", "RESTRICT")
+	db.Model(&entity.Activity{}).AddForeignKey("board_id", "boards(id)", "CASCADE", "RESTRICT")
 	db.Model(&entity.CheckList{}).AddForeignKey("card_id", "cards(id)", "RESTRICT", "RESTRICT")
 	db.Model(&entity.CheckListItem{}).AddForeignKey("check_list_id", "check_lists(id)", "CASCADE", "RESTRICT")
 	db.Model(&entity.File{}).AddForeignKey("card_id", "cards(id)", "CASCADE", "RESTRICT")
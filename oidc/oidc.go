@@ -0,0 +1,249 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"local.packages/config"
+)
+
+// ErrNotConfigured is returned when an issuer of OpenID Connect is not specified in config file.
+var ErrNotConfigured = errors.New("oidc: provider is not configured")
+
+// Config contains settings of a relying party.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// metadata is a part of OpenID Provider Metadata that is used by the relying party.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is an OpenID Provider that was discovered by an issuer.
+type Provider struct {
+	config        Config
+	client        *http.Client
+	mu            sync.Mutex
+	metadata      *metadata
+	keys          *keySet
+	keysFetchedAt time.Time
+}
+
+// NewProvider is constructor for Provider.
+// metadata of the provider is discovered on first use.
+func NewProvider(c Config) *Provider {
+	return &Provider{
+		config: c,
+		client: &http.Client{Timeout: time.Second * 10},
+	}
+}
+
+var (
+	mu       sync.Mutex
+	provider *Provider
+)
+
+// Get returns an instance of Provider that was configured by config file.
+// the instance is created again when the config was changed.
+func Get() (*Provider, error) {
+	c := config.Config.OIDC
+
+	if c.Issuer == "" {
+		return nil, ErrNotConfigured
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	pc := Config{
+		Issuer:       c.Issuer,
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		RedirectURL:  c.RedirectURL,
+	}
+
+	if provider == nil || provider.config != pc {
+		provider = NewProvider(pc)
+	}
+
+	return provider, nil
+}
+
+// Issuer returns an issuer identifier of the provider.
+func (p *Provider) Issuer() string {
+	return p.config.Issuer
+}
+
+// AuthCodeURL returns a URL of the authorization endpoint for the authorization code flow with PKCE.
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) (string, error) {
+	m, err := p.discover()
+
+	if err != nil {
+		return "", err
+	}
+
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.config.ClientID)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("scope", "openid email profile")
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", CodeChallenge(verifier))
+	v.Set("code_challenge_method", "S256")
+
+	sep := "?"
+
+	if strings.Contains(m.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return m.AuthorizationEndpoint + sep + v.Encode(), nil
+}
+
+// Exchange exchanges an authorization code for an ID token at the token endpoint.
+func (p *Provider) Exchange(code, verifier string) (string, error) {
+	m, err := p.discover()
+
+	if err != nil {
+		return "", err
+	}
+
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("code_verifier", verifier)
+
+	req, err := http.NewRequest(http.MethodPost, m.TokenEndpoint, strings.NewReader(v.Encode()))
+
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	var t struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+
+	if err := p.fetch(req, &t); err != nil {
+		return "", err
+	}
+
+	if t.IDToken == "" {
+		return "", fmt.Errorf("oidc: token response does not contain id_token: %s", t.Error)
+	}
+
+	return t.IDToken, nil
+}
+
+// Verify validates a signature and claims of an ID token.
+// returns the claims if the token was issued by the provider for this client and the nonce.
+func (p *Provider) Verify(raw, nonce string) (*Claims, error) {
+	m, err := p.discover()
+
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := p.verifySignature(raw, m.JWKSURI)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.validate(m.Issuer, p.config.ClientID, nonce, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (p *Provider) discover() (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var m metadata
+
+	if err := p.fetch(req, &m); err != nil {
+		return nil, err
+	}
+
+	if m.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("oidc: issuer did not match: %s", m.Issuer)
+	}
+
+	p.metadata = &m
+
+	return p.metadata, nil
+}
+
+func (p *Provider) fetch(req *http.Request, v interface{}) error {
+	res, err := p.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: %s responded %d: %s", req.URL, res.StatusCode, b)
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// NewRandomString returns a random string that is used for state, nonce and code verifier.
+func NewRandomString() (string, error) {
+	b := make([]byte, 32)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge returns a code challenge of PKCE that derived from a code verifier by S256 method.
+func CodeChallenge(verifier string) string {
+	d := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(d[:])
+}
@@ -0,0 +1,209 @@
+package oidc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/utils"
+)
+
+func setUpProvider(t *testing.T) (*Provider, *utils.StubIdP) {
+	idp, err := utils.NewStubIdP("kanban", "secret")
+
+	if err != nil {
+		t.Fatalf("fail to start stub idp: %v", err)
+	}
+
+	p := NewProvider(Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     "kanban",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:3000/oidc/callback",
+	})
+
+	return p, idp
+}
+
+func TestShouldSuccessfullyCompleteAuthorizationCodeFlow(t *testing.T) {
+	p, idp := setUpProvider(t)
+	defer idp.Close()
+
+	authURL, err := p.AuthCodeURL("state", "nonce", "verifier")
+
+	if err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	u, _ := url.Parse(authURL)
+
+	assert.Equal(t, idp.Issuer()+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "state", u.Query().Get("state"))
+	assert.Equal(t, CodeChallenge("verifier"), u.Query().Get("code_challenge"))
+
+	code, err := idp.Authorize(authURL, "subject", "gopher@sample.com", true, "gopher")
+
+	if err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	raw, err := p.Exchange(code, "verifier")
+
+	if err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	c, err := p.Verify(raw, "nonce")
+
+	if err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	assert.Equal(t, "subject", c.Subject)
+	assert.Equal(t, "gopher@sample.com", c.Email)
+	assert.True(t, bool(c.EmailVerified))
+	assert.Equal(t, "gopher", c.Name)
+}
+
+func TestShouldFailureExchangeWhenCodeVerifierIsWrong(t *testing.T) {
+	p, idp := setUpProvider(t)
+	defer idp.Close()
+
+	authURL, _ := p.AuthCodeURL("state", "nonce", "verifier")
+	code, _ := idp.Authorize(authURL, "subject", "gopher@sample.com", true, "gopher")
+
+	_, err := p.Exchange(code, "another verifier")
+
+	assert.Error(t, err)
+}
+
+func TestShouldFailureVerifyWhenNonceDoesNotMatch(t *testing.T) {
+	p, idp := setUpProvider(t)
+	defer idp.Close()
+
+	authURL, _ := p.AuthCodeURL("state", "nonce", "verifier")
+	code, _ := idp.Authorize(authURL, "subject", "gopher@sample.com", true, "gopher")
+	raw, _ := p.Exchange(code, "verifier")
+
+	_, err := p.Verify(raw, "another nonce")
+
+	assert.Error(t, err)
+}
+
+func TestShouldFailureVerifyWhenClaimsAreInvalid(t *testing.T) {
+	p, idp := setUpProvider(t)
+	defer idp.Close()
+
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   idp.Issuer(),
+			"sub":   "subject",
+			"aud":   []string{"kanban"},
+			"exp":   time.Now().Add(time.Minute * 5).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	raw, _ := idp.SignIDToken(valid())
+
+	if _, err := p.Verify(raw, "nonce"); err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	cases := map[string]func(c map[string]interface{}){
+		"issuer":   func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" },
+		"audience": func(c map[string]interface{}) { c["aud"] = "another" },
+		"expired":  func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Minute * 5).Unix() },
+		"future":   func(c map[string]interface{}) { c["iat"] = time.Now().Add(time.Minute * 5).Unix() },
+		"subject":  func(c map[string]interface{}) { c["sub"] = "" },
+	}
+
+	for name, modify := range cases {
+		c := valid()
+		modify(c)
+
+		raw, _ := idp.SignIDToken(c)
+
+		if _, err := p.Verify(raw, "nonce"); err == nil {
+			t.Errorf("was expected an error by invalid %s", name)
+		}
+	}
+}
+
+func TestShouldFailureVerifyWhenSignatureIsInvalid(t *testing.T) {
+	p, idp := setUpProvider(t)
+	defer idp.Close()
+
+	other, _ := utils.NewStubIdP("kanban", "secret")
+	defer other.Close()
+
+	raw, _ := other.SignIDToken(map[string]interface{}{
+		"iss":   idp.Issuer(),
+		"sub":   "subject",
+		"aud":   "kanban",
+		"exp":   time.Now().Add(time.Minute * 5).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": "nonce",
+	})
+
+	_, err := p.Verify(raw, "nonce")
+
+	assert.Error(t, err)
+}
+
+func TestShouldAcceptEmailVerifiedAsString(t *testing.T) {
+	p, idp := setUpProvider(t)
+	defer idp.Close()
+
+	raw, _ := idp.SignIDToken(map[string]interface{}{
+		"iss":            idp.Issuer(),
+		"sub":            "subject",
+		"aud":            "kanban",
+		"exp":            time.Now().Add(time.Minute * 5).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          "nonce",
+		"email_verified": "true",
+	})
+
+	c, err := p.Verify(raw, "nonce")
+
+	if err != nil {
+		t.Fatalf("was not expected an error. %v", err)
+	}
+
+	assert.True(t, bool(c.EmailVerified))
+}
+
+func TestShouldNotFetchKeysAgainByUnknownKeyIDWithinInterval(t *testing.T) {
+	var fetched int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetched, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer server.Close()
+
+	p := NewProvider(Config{})
+
+	for i := 0; i < 3; i++ {
+		_, err := p.key("forged", server.URL)
+
+		assert.Error(t, err)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetched))
+
+	p.keysFetchedAt = time.Now().Add(-keysRefetchInterval)
+
+	_, err := p.key("forged", server.URL)
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetched))
+}
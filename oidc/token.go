@@ -0,0 +1,281 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockSkew is an allowance of the difference between clocks of the provider and this server.
+const clockSkew = time.Minute * 1
+
+// keysRefetchInterval is a minimum interval of fetching keys again by unknown key ids.
+// it keeps tokens with forged key ids from making the provider be requested at every verification.
+const keysRefetchInterval = time.Minute * 1
+
+// Claims are claims of an ID token that are used by the relying party.
+type Claims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	Expiry        int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified boolean  `json:"email_verified"`
+	Name          string   `json:"name"`
+}
+
+// audience is a claim that is either a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+
+	var ss []string
+
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+
+	*a = ss
+
+	return nil
+}
+
+// boolean is a claim that is either a boolean or a string of a boolean.
+// some providers send email_verified as a string "true".
+type boolean bool
+
+func (v *boolean) UnmarshalJSON(b []byte) error {
+	var f bool
+
+	if err := json.Unmarshal(b, &f); err == nil {
+		*v = boolean(f)
+		return nil
+	}
+
+	var s string
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	f, err := strconv.ParseBool(s)
+
+	if err != nil {
+		return fmt.Errorf("oidc: invalid boolean claim: %s", s)
+	}
+
+	*v = boolean(f)
+
+	return nil
+}
+
+func (a audience) contains(s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Claims) validate(issuer, clientID, nonce string, now time.Time) error {
+	if c.Issuer != issuer {
+		return fmt.Errorf("oidc: issuer did not match: %s", c.Issuer)
+	}
+
+	if !c.Audience.contains(clientID) {
+		return errors.New("oidc: audience does not contain client id")
+	}
+
+	if now.Add(-clockSkew).Unix() >= c.Expiry {
+		return errors.New("oidc: token has expired")
+	}
+
+	if now.Add(clockSkew).Unix() < c.IssuedAt {
+		return errors.New("oidc: token was issued in the future")
+	}
+
+	if c.Nonce != nonce {
+		return errors.New("oidc: nonce did not match")
+	}
+
+	if c.Subject == "" {
+		return errors.New("oidc: token does not contain subject")
+	}
+
+	return nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// keySet is RSA public keys of the provider that are indexed by key id.
+type keySet map[string]*rsa.PublicKey
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// verifySignature verifies a signature of JWS compact serialization and decodes claims.
+// only RS256 is accepted.
+// keys are fetched again if a key id is unknown, so that rotation of keys by the provider is followed.
+// they are fetched at most once in keysRefetchInterval.
+func (p *Provider) verifySignature(raw, jwksURI string) (*Claims, error) {
+	parts := strings.Split(raw, ".")
+
+	if len(parts) != 3 {
+		return nil, errors.New("oidc: malformed token")
+	}
+
+	var h header
+
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, err
+	}
+
+	if h.Alg != "RS256" {
+		return nil, fmt.Errorf("oidc: unsupported algorithm: %s", h.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := p.key(h.Kid, jwksURI)
+
+	if err != nil {
+		return nil, err
+	}
+
+	d := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, d[:], sig); err != nil {
+		return nil, fmt.Errorf("oidc: invalid signature: %v", err)
+	}
+
+	var c Claims
+
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (p *Provider) key(kid, jwksURI string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+
+	if p.keys != nil {
+		if k, ok := (*p.keys)[kid]; ok {
+			p.mu.Unlock()
+			return k, nil
+		}
+
+		if time.Since(p.keysFetchedAt) < keysRefetchInterval {
+			p.mu.Unlock()
+			return nil, fmt.Errorf("oidc: unknown key id: %s", kid)
+		}
+	}
+
+	// other verifications wait for the interval instead of fetching keys at the same time.
+	p.keysFetchedAt = time.Now()
+	p.mu.Unlock()
+
+	keys, err := p.fetchKeys(jwksURI)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.keys = &keys
+	p.mu.Unlock()
+
+	k, ok := keys[kid]
+
+	if !ok {
+		return nil, fmt.Errorf("oidc: unknown key id: %s", kid)
+	}
+
+	return k, nil
+}
+
+// fetchKeys fetches RSA public keys of the provider.
+// it is called without holding the lock, so that verifications are not blocked by the request.
+func (p *Provider) fetchKeys(jwksURI string) (keySet, error) {
+	req, err := http.NewRequest(http.MethodGet, jwksURI, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := p.fetch(req, &set); err != nil {
+		return nil, err
+	}
+
+	keys := keySet{}
+
+	for _, j := range set.Keys {
+		if j.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+
+		if err != nil {
+			return nil, err
+		}
+
+		keys[j.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+func decodeSegment(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// lastUsedInterval is the minimum interval between updates of AccessToken.LastUsedAt.
+const lastUsedInterval = time.Minute * 1
+
+// boardIDQueries maps a key of URL params to a query that selects an id of the board that a record belongs to.
+// params of records that do not belong to a board, such as notificationID, workspaceID, sessionID and accessTokenID, are not mapped.
+// so that a token scoped to a board is rejected on routes of notifications, workspaces and the account, which are out of the scope on purpose.
+// userID and backgroundImageID are not mapped either, because they are used only together with boardID or on routes of the account and admin.
+var boardIDQueries = map[string]func(db *gorm.DB, id uint) *gorm.DB{
+	"listID": func(db *gorm.DB, id uint) *gorm.DB {
+		return db.Table("lists").Select("lists.board_id").Where("lists.id = ?", id)
+	},
+	"labelID": func(db *gorm.DB, id uint) *gorm.DB {
+		return db.Table("labels").Select("labels.board_id").Where("labels.id = ?", id)
+	},
+	"cardID": func(db *gorm.DB, id uint) *gorm.DB {
+		return db.Table("cards").
+			Joins("Join lists ON cards.list_id = lists.id").
+			Select("lists.board_id").
+			Where("cards.id = ?", id)
+	},
+	"checkListID": func(db *gorm.DB, id uint) *gorm.DB {
+		return db.Table("check_lists").
+			Joins("Join cards ON check_lists.card_id = cards.id").
+			Joins("Join lists ON cards.list_id = lists.id").
+			Select("lists.board_id").
+			Where("check_lists.id = ?", id)
+	},
+	"checkListItemID": func(db *gorm.DB, id uint) *gorm.DB {
+		return db.Table("check_list_items").
+			Joins("Join check_lists ON check_list_items.check_list_id = check_lists.id").
+			Joins("Join cards ON check_lists.card_id = cards.id").
+			Joins("Join lists ON cards.list_id = lists.id").
+			Select("lists.board_id").
+			Where("check_list_items.id = ?", id)
+	},
+	"commentID": func(db *gorm.DB, id uint) *gorm.DB {
+		return db.Table("comments").
+			Joins("Join cards ON comments.card_id = cards.id").
+			Joins("Join lists ON cards.list_id = lists.id").
+			Select("lists.board_id").
+			Where("comments.id = ?", id)
+	},
+	"fileID": func(db *gorm.DB, id uint) *gorm.DB {
+		return db.Table("files").
+			Joins("Join cards ON files.card_id = cards.id").
+			Joins("Join lists ON cards.list_id = lists.id").
+			Select("lists.board_id").
+			Where("files.id = ?", id)
+	},
+}
+
+// CreateAccessToken insert a new record to access_tokens table and returns the raw token.
+// the raw token can not be retrieved again.
+func (r *UserRepository) CreateAccessToken(uid uint, name, scope string, bid *uint, expiresAt *time.Time) (*entity.AccessToken, string, []validator.ValidationError) {
+	at := &entity.AccessToken{
+		UserID:    uid,
+		Name:      name,
+		Scope:     scope,
+		BoardID:   bid,
+		ExpiresAt: expiresAt,
+	}
+
+	if bid != nil {
+		var b entity.Board
+
+		if r.db.Select("id").Scopes(memberOf(uid, viewableRoles)).First(&b, *bid).RecordNotFound() {
+			return at, "", validator.NewValidationErrors(ErrorRecordNotFound)
+		}
+	}
+
+	t, err := newSessionToken()
+
+	if err != nil {
+		log.Printf("fail to create access token: %v", err)
+		return at, "", validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	t = entity.AccessTokenPrefix + t
+	at.Digest = digestToken(t)
+
+	if err := r.db.Create(at).Error; err != nil {
+		log.Printf("fail to create access token: %v", err)
+		return at, "", validator.FormattedMySQLError(err)
+	}
+
+	return at, t, nil
+}
+
+// GetAccessTokens returns slice of AccessToken's record that belongs to the login user.
+func (r *UserRepository) GetAccessTokens(uid uint) *[]entity.AccessToken {
+	var ats []entity.AccessToken
+
+	r.db.Select("id, created_at, name, scope, board_id, last_used_at, expires_at").
+		Where("user_id = ?", uid).
+		Order("id desc").
+		Find(&ats)
+
+	return &ats
+}
+
+// DeleteAccessToken revokes an access token of the login user that found by id.
+func (r *UserRepository) DeleteAccessToken(id, uid uint) []validator.ValidationError {
+	if err := deletionErrors(r.db.Where("id = ? AND user_id = ?", id, uid).Delete(&entity.AccessToken{}), "access token"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindAccessToken returns an instance of AccessToken that found by a raw token.
+// returns `false` if the record not found or has expired.
+func (r *UserRepository) FindAccessToken(token string) (*entity.AccessToken, bool) {
+	at := &entity.AccessToken{}
+
+	if r.db.Where("digest = ?", digestToken(token)).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		First(at).
+		RecordNotFound() {
+		return at, false
+	}
+
+	return at, true
+}
+
+// TouchAccessToken updates the last used time of an access token.
+// skip update if the token was used within lastUsedInterval.
+func (r *UserRepository) TouchAccessToken(at *entity.AccessToken) {
+	if at.LastUsedAt != nil && time.Since(*at.LastUsedAt) < lastUsedInterval {
+		return
+	}
+
+	if err := r.db.Model(at).UpdateColumn("last_used_at", time.Now()).Error; err != nil {
+		log.Printf("fail to update last used: %v", err)
+	}
+}
+
+// BoardIDOfParam returns an id of the board that a record specified by URL param belongs to.
+// returns `false` if the param does not specify a record that belongs to a board.
+func (r *UserRepository) BoardIDOfParam(key string, id uint) (uint, bool) {
+	if key == "boardID" {
+		return id, true
+	}
+
+	q, ok := boardIDQueries[key]
+
+	if !ok {
+		return 0, false
+	}
+
+	var bid uint
+
+	if err := q(r.db, id).Row().Scan(&bid); err != nil {
+		log.Printf("fail to find board id of %s: %v", key, err)
+		return 0, true
+	}
+
+	return bid, true
+}
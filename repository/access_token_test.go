@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+)
+
+func TestShouldSuccessfullyCreateAccessToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+	name := "ci"
+
+	query := utils.ReplaceQuotationForQuery(`
+		INSERT INTO 'access_tokens' ('created_at','updated_at','user_id','name','scope','board_id','digest','last_used_at','expires_at')
+		VALUES (?,?,?,?,?,?,?,?,?)`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, userID, name, entity.ScopeRead, nil, sqlmock.AnyArg(), nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	at, token, err := r.CreateAccessToken(userID, name, entity.ScopeRead, nil, nil)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, at.ID, uint(1))
+	assert.True(t, strings.HasPrefix(token, entity.AccessTokenPrefix))
+	assert.Equal(t, at.Digest, digestToken(token))
+}
+
+func TestShouldNotCreateAccessTokenWhenUserIsNotMemberOfBoard(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	boardID := uint(2)
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT id FROM 'boards'
+		WHERE 'boards'.'deleted_at' IS NULL
+		AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?))) AND ('boards'.'id' = 2))
+		ORDER BY 'boards'.'id' ASC
+		LIMIT 1`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(uint(1), "owner", "editor", "viewer").
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, token, err := r.CreateAccessToken(uint(1), "ci", entity.ScopeWrite, &boardID, nil)
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Empty(t, token)
+	assert.Equal(t, err[0].Text, ErrorRecordNotFound)
+}
+
+func TestShouldSuccessfullyFindAccessToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	token := entity.AccessTokenPrefix + "sampletoken"
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT * FROM 'access_tokens'
+		WHERE (digest = ?) AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY 'access_tokens'.'id' ASC
+		LIMIT 1`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(digestToken(token), utils.AnyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "scope"}).AddRow(uint(3), uint(1), entity.ScopeRead))
+
+	at, ok := r.FindAccessToken(token)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.True(t, ok)
+	assert.Equal(t, at.UserID, uint(1))
+	assert.Equal(t, at.Scope, entity.ScopeRead)
+}
+
+func TestShouldNotFindAccessTokenWhenTokenIsUnknown(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `access_tokens`")).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, ok := r.FindAccessToken(entity.AccessTokenPrefix + "sampletoken")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.False(t, ok)
+}
+
+func TestShouldSuccessfullyGetAccessTokens(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT id, created_at, name, scope, board_id, last_used_at, expires_at
+		FROM 'access_tokens'
+		WHERE (user_id = ?)
+		ORDER BY id desc`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(uint(1)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "scope"}).
+				AddRow(uint(2), "deploy", entity.ScopeWrite).
+				AddRow(uint(1), "ci", entity.ScopeRead))
+
+	ats := r.GetAccessTokens(uint(1))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, len(*ats), 2)
+	assert.Equal(t, (*ats)[0].Name, "deploy")
+}
+
+func TestShouldFailureDeleteAccessTokenWhenTokenDoesNotBelongToUser(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	query := "DELETE FROM `access_tokens` WHERE (id = ? AND user_id = ?)"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(uint(3), uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	err := r.DeleteAccessToken(uint(3), uint(1))
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorRecordNotFound)
+}
+
+func TestShouldReturnsBoardIDOfParam(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	cardID := uint(4)
+	boardID := uint(2)
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT lists.board_id FROM 'cards'
+		Join lists ON cards.list_id = lists.id
+		WHERE (cards.id = ?)`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(cardID).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id"}).AddRow(boardID))
+
+	bid, ok := r.BoardIDOfParam("cardID", cardID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.True(t, ok)
+	assert.Equal(t, bid, boardID)
+}
+
+func TestShouldReturnsBoardIDOfCommentParam(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	commentID := uint(5)
+	boardID := uint(2)
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT lists.board_id FROM 'comments'
+		Join cards ON comments.card_id = cards.id
+		Join lists ON cards.list_id = lists.id
+		WHERE (comments.id = ?)`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(commentID).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id"}).AddRow(boardID))
+
+	bid, ok := r.BoardIDOfParam("commentID", commentID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.True(t, ok)
+	assert.Equal(t, bid, boardID)
+}
+
+func TestShouldNotReturnsBoardIDOfParamThatDoesNotBelongToBoard(t *testing.T) {
+	db, _ := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	_, ok := r.BoardIDOfParam("backgroundImageID", uint(1))
+
+	assert.False(t, ok)
+
+	_, ok = r.BoardIDOfParam("notificationID", uint(1))
+
+	assert.False(t, ok)
+}
@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jinzhu/gorm"
+	"golang.org/x/crypto/bcrypt"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// UpdateProfile updates a name and an email address of the user.
+// an email address has to be verified again when it was changed.
+func (r *UserRepository) UpdateProfile(u *entity.User, name, email string) []validator.ValidationError {
+	attrs := map[string]interface{}{}
+
+	if name != "" {
+		attrs["name"] = name
+	}
+
+	if email != "" && email != u.Email {
+		attrs["email"] = email
+		attrs["verified_at"] = nil
+	}
+
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	if err := r.db.Model(u).Updates(attrs).Error; err != nil {
+		return validator.FormattedMySQLError(err)
+	}
+
+	return nil
+}
+
+// ChangePassword updates a password of the user after confirming the current password.
+// sessions of the user except for the current one are deleted.
+func (r *UserRepository) ChangePassword(uid, sid uint, current, passwordDigest string) []validator.ValidationError {
+	u, err := r.Find(uid)
+
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordDigest), []byte(current)); err != nil {
+		return validator.NewValidationErrors(ErrorInvalidPassword)
+	}
+
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(u).UpdateColumn("password_digest", passwordDigest).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND id <> ?", uid, sid).Delete(&entity.Session{}).Error
+	}); err != nil {
+		log.Printf("fail to change password: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	forgetLiveSessionsOf(uid)
+
+	return nil
+}
+
+// DeleteAccount deletes the user and boards that belong only to the user in one transaction.
+// boards shared with other members are handed over to one of them, and so are workspaces that the user owns.
+// uploaded files are deleted from S3 bucket after the transaction was committed.
+func (r *UserRepository) DeleteAccount(uid uint, password string) []validator.ValidationError {
+	u, verr := r.Find(uid)
+
+	if verr != nil {
+		return verr
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordDigest), []byte(password)); err != nil {
+		return validator.NewValidationErrors(ErrorInvalidPassword)
+	}
+
+	var fs []entity.File
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+
+		if err = handOverWorkspaces(tx, uid); err != nil {
+			return err
+		}
+
+		if fs, err = deleteUserBoards(tx, uid); err != nil {
+			return err
+		}
+
+		// sessions and one time tokens are deleted by foreign key constraints.
+		return tx.Delete(u).Error
+	})
+
+	if err != nil {
+		log.Printf("fail to delete account: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	forgetLiveSessionsOf(uid)
+	deleteFileObjects(fs)
+
+	return nil
+}
+
+// deleteUserBoards deletes boards that the user created or owns and their lists, cards, labels, checklists and members.
+// boards shared with other members are handed over to one of them instead of being deleted.
+// returns files of deleted cards, so that the objects are deleted after the transaction was committed.
+func deleteUserBoards(tx *gorm.DB, uid uint) ([]entity.File, error) {
+	var fs []entity.File
+	var bids, lids, cids, clids []uint
+
+	if err := tx.Unscoped().Model(&entity.Board{}).
+		Where("user_id = ? OR id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role = ?)", uid, uid, entity.BoardRoleOwner).
+		Pluck("id", &bids).Error; err != nil {
+		return nil, err
+	}
+
+	bids, err := handOverSharedBoards(tx, uid, bids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Unscoped().Model(&entity.List{}).Where("board_id IN (?)", bids).Pluck("id", &lids).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Unscoped().Model(&entity.Card{}).Where("list_id IN (?)", lids).Pluck("id", &cids).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(&entity.CheckList{}).Where("card_id IN (?)", cids).Pluck("id", &clids).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Select("card_id, `key`").Where("card_id IN (?)", cids).Find(&fs).Error; err != nil {
+		return nil, err
+	}
+
+	deletions := []struct {
+		query string
+		ids   []uint
+		value interface{}
+	}{
+		{"card_id IN (?)", cids, &entity.Cover{}},
+		{"card_id IN (?)", cids, &entity.File{}},
+		{"card_id IN (?)", cids, &entity.CardLabel{}},
+		{"card_id IN (?)", cids, &entity.CardAssignee{}},
+		{"card_id IN (?)", cids, &entity.Mention{}},
+		{"card_id IN (?)", cids, &entity.Comment{}},
+		{"target_type = 'card' AND target_id IN (?)", cids, &entity.Watch{}},
+		{"check_list_id IN (?)", clids, &entity.CheckListItem{}},
+		{"card_id IN (?)", cids, &entity.CheckList{}},
+		{"list_id IN (?)", lids, &entity.Card{}},
+		{"target_type = 'list' AND target_id IN (?)", lids, &entity.Watch{}},
+		{"board_id IN (?)", bids, &entity.List{}},
+		{"board_id IN (?)", bids, &entity.Label{}},
+		{"board_id IN (?)", bids, &entity.BoardBackgroundImage{}},
+		{"board_id IN (?)", bids, &entity.BoardMember{}},
+		{"board_id IN (?)", bids, &entity.Invitation{}},
+		{"board_id IN (?)", bids, &entity.Notification{}},
+		{"board_id IN (?)", bids, &entity.Activity{}},
+		{"target_type = 'board' AND target_id IN (?)", bids, &entity.Watch{}},
+		{"id IN (?)", bids, &entity.Board{}},
+	}
+
+	for _, d := range deletions {
+		if len(d.ids) == 0 {
+			continue
+		}
+
+		if err := tx.Unscoped().Where(d.query, d.ids).Delete(d.value).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return fs, nil
+}
+
+// handOverSharedBoards hands boards over to another member, so that boards shared with others are kept when the user goes.
+// another owner is preferred, and the oldest member is promoted to owner if the user is the only owner.
+// the user leaves the boards that were handed over. returns ids of boards that have no other member.
+func handOverSharedBoards(tx *gorm.DB, uid uint, bids []uint) ([]uint, error) {
+	if len(bids) == 0 {
+		return bids, nil
+	}
+
+	var ms []entity.BoardMember
+
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("board_id IN (?) AND user_id <> ?", bids, uid).
+		Order("board_id, role = 'owner' DESC, created_at").
+		Find(&ms).Error; err != nil {
+		return nil, err
+	}
+
+	heirs := map[uint]entity.BoardMember{}
+
+	for _, m := range ms {
+		if _, ok := heirs[m.BoardID]; !ok {
+			heirs[m.BoardID] = m
+		}
+	}
+
+	var unshared []uint
+
+	for _, bid := range bids {
+		m, ok := heirs[bid]
+
+		if !ok {
+			unshared = append(unshared, bid)
+			continue
+		}
+
+		if m.Role != entity.BoardRoleOwner {
+			if err := tx.Model(&m).UpdateColumn("role", entity.BoardRoleOwner).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		if err := tx.Unscoped().Model(&entity.Board{}).Where("id = ? AND user_id = ?", bid, uid).UpdateColumn("user_id", m.UserID).Error; err != nil {
+			return nil, err
+		}
+
+		if err := tx.Where("board_id = ? AND user_id = ?", bid, uid).Delete(&entity.BoardMember{}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if len(unshared) < len(bids) {
+		if err := unassignNonMemberCards(tx, uid); err != nil {
+			return nil, err
+		}
+	}
+
+	return unshared, nil
+}
+
+// handOverWorkspaces hands workspaces that the user owns over to another member, so that a workspace is not left without an owner.
+// another owner is preferred, and the oldest member is promoted to owner if the user is the only owner.
+// a workspace that has no other member is deleted.
+func handOverWorkspaces(tx *gorm.DB, uid uint) error {
+	var wids []uint
+
+	if err := tx.Model(&entity.WorkspaceMember{}).Where("user_id = ? AND role = ?", uid, entity.WorkspaceRoleOwner).Pluck("workspace_id", &wids).Error; err != nil {
+		return err
+	}
+
+	if len(wids) == 0 {
+		return nil
+	}
+
+	var ms []entity.WorkspaceMember
+
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("workspace_id IN (?) AND user_id <> ?", wids, uid).
+		Order("workspace_id, role = 'owner' DESC, created_at").
+		Find(&ms).Error; err != nil {
+		return err
+	}
+
+	heirs := map[uint]entity.WorkspaceMember{}
+
+	for _, m := range ms {
+		if _, ok := heirs[m.WorkspaceID]; !ok {
+			heirs[m.WorkspaceID] = m
+		}
+	}
+
+	for _, wid := range wids {
+		m, ok := heirs[wid]
+
+		if !ok {
+			if err := deleteWorkspace(tx, wid); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if m.Role != entity.WorkspaceRoleOwner {
+			if err := tx.Model(&m).UpdateColumn("role", entity.WorkspaceRoleOwner).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteFileObjects deletes objects of files from S3 bucket.
+func deleteFileObjects(fs []entity.File) {
+	for _, f := range fs {
+		if err := deleteObject(fmt.Sprintf("%d/%s", f.CardID, f.Key)); err != nil {
+			log.Printf("fail to delete an object of deleted file: %v", err)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"local.packages/entity"
+	"local.packages/utils"
+)
+
+func TestShouldSuccessfullyUpdateProfile(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	now := time.Now()
+	u := &entity.User{ID: uint(1), Name: "gopher", Email: "gopher@sample.com", VerifiedAt: &now}
+
+	name := "new gopher"
+	email := "new-gopher@sample.com"
+
+	query := utils.ReplaceQuotationForQuery(`
+		UPDATE 'users'
+		SET 'email' = ?, 'name' = ?, 'updated_at' = ?, 'verified_at' = ?
+		WHERE 'users'.'id' = ?`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(email, name, utils.AnyTime{}, nil, u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.UpdateProfile(u, name, email); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldNotUpdateVerifiedAtWhenEmailIsNotChanged(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	u := &entity.User{ID: uint(1), Name: "gopher", Email: "gopher@sample.com"}
+
+	name := "new gopher"
+
+	query := "UPDATE `users` SET `name` = ?, `updated_at` = ? WHERE `users`.`id` = ?"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(name, utils.AnyTime{}, u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.UpdateProfile(u, name, u.Email); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldSuccessfullyChangePassword(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+	sessionID := uint(2)
+	current := "12345678"
+	passwordDigest := "digest"
+
+	currentDigest, _ := bcrypt.GenerateFromPassword([]byte(current), bcrypt.DefaultCost)
+
+	updateQuery := "UPDATE `users` SET `password_digest` = ? WHERE `users`.`id` = ?"
+	deleteQuery := "DELETE FROM `sessions` WHERE (user_id = ? AND id <> ?)"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_digest"}).AddRow(userID, currentDigest))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+		WithArgs(passwordDigest, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+		WithArgs(userID, sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectCommit()
+
+	if err := r.ChangePassword(userID, sessionID, current, passwordDigest); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldFailureChangePasswordWhenCurrentPasswordIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	currentDigest, _ := bcrypt.GenerateFromPassword([]byte("12345678"), bcrypt.DefaultCost)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_digest"}).AddRow(uint(1), currentDigest))
+
+	err := r.ChangePassword(uint(1), uint(2), "87654321", "digest")
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorInvalidPassword)
+}
+
+func TestShouldSuccessfullyDeleteAccount(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+	boardID := uint(2)
+	listID := uint(3)
+	cardID := uint(4)
+	checkListID := uint(5)
+	password := "12345678"
+
+	passwordDigest, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_digest"}).AddRow(userID, passwordDigest))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT workspace_id FROM `workspace_members` WHERE (user_id = ? AND role = ?)")).
+		WithArgs(userID, entity.WorkspaceRoleOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards` WHERE (user_id = ? OR id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role = ?))")).
+		WithArgs(userID, userID, entity.BoardRoleOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(boardID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `board_members` WHERE (board_id IN (?) AND user_id <> ?)")).
+		WithArgs(boardID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id", "user_id", "role"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `lists` WHERE (board_id IN (?))")).
+		WithArgs(boardID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(listID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `cards` WHERE (list_id IN (?))")).
+		WithArgs(listID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(cardID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `check_lists` WHERE (card_id IN (?))")).
+		WithArgs(cardID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(checkListID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT card_id, `key` FROM `files` WHERE (card_id IN (?))")).
+		WithArgs(cardID).
+		WillReturnRows(sqlmock.NewRows([]string{"card_id", "key"}))
+
+	deleteQueries := []struct {
+		query string
+		id    uint
+	}{
+		{"DELETE FROM `covers` WHERE (card_id IN (?))", cardID},
+		{"DELETE FROM `files` WHERE (card_id IN (?))", cardID},
+		{"DELETE FROM `card_labels` WHERE (card_id IN (?))", cardID},
+		{"DELETE FROM `card_assignees` WHERE (card_id IN (?))", cardID},
+		{"DELETE FROM `mentions` WHERE (card_id IN (?))", cardID},
+		{"DELETE FROM `comments` WHERE (card_id IN (?))", cardID},
+		{"DELETE FROM `watches` WHERE (target_type = 'card' AND target_id IN (?))", cardID},
+		{"DELETE FROM `check_list_items` WHERE (check_list_id IN (?))", checkListID},
+		{"DELETE FROM `check_lists` WHERE (card_id IN (?))", cardID},
+		{"DELETE FROM `cards` WHERE (list_id IN (?))", listID},
+		{"DELETE FROM `watches` WHERE (target_type = 'list' AND target_id IN (?))", listID},
+		{"DELETE FROM `lists` WHERE (board_id IN (?))", boardID},
+		{"DELETE FROM `labels` WHERE (board_id IN (?))", boardID},
+		{"DELETE FROM `board_background_images` WHERE (board_id IN (?))", boardID},
+		{"DELETE FROM `board_members` WHERE (board_id IN (?))", boardID},
+		{"DELETE FROM `invitations` WHERE (board_id IN (?))", boardID},
+		{"DELETE FROM `notifications` WHERE (board_id IN (?))", boardID},
+		{"DELETE FROM `activities` WHERE (board_id IN (?))", boardID},
+		{"DELETE FROM `watches` WHERE (target_type = 'board' AND target_id IN (?))", boardID},
+		{"DELETE FROM `boards` WHERE (id IN (?))", boardID},
+	}
+
+	for _, d := range deleteQueries {
+		mock.ExpectExec(regexp.QuoteMeta(d.query)).
+			WithArgs(d.id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `users` WHERE `users`.`id` = ?")).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.DeleteAccount(userID, password); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldHandOverSharedBoardWhenDeletingAccount(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+	boardID := uint(2)
+	memberID := uint(3)
+	password := "12345678"
+
+	passwordDigest, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_digest"}).AddRow(userID, passwordDigest))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT workspace_id FROM `workspace_members` WHERE (user_id = ? AND role = ?)")).
+		WithArgs(userID, entity.WorkspaceRoleOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WithArgs(userID, userID, entity.BoardRoleOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(boardID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `board_members` WHERE (board_id IN (?) AND user_id <> ?) ORDER BY board_id, role = 'owner' DESC, created_at FOR UPDATE")).
+		WithArgs(boardID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id", "user_id", "role"}).AddRow(boardID, memberID, entity.BoardRoleEditor))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `board_members` SET `role` = ?")).
+		WithArgs(entity.BoardRoleOwner, boardID, memberID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `boards` SET `user_id` = ? WHERE (id = ? AND user_id = ?)")).
+		WithArgs(memberID, boardID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `board_members` WHERE (board_id = ? AND user_id = ?)")).
+		WithArgs(boardID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `card_assignees`")).
+		WithArgs(userID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `cards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `check_lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT card_id, `key` FROM `files`")).
+		WillReturnRows(sqlmock.NewRows([]string{"card_id", "key"}))
+
+	// the shared board is not deleted.
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `users`")).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.DeleteAccount(userID, password); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldHandOverWorkspacesWhenDeletingAccount(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+	sharedWorkspaceID := uint(5)
+	ownWorkspaceID := uint(6)
+	memberID := uint(3)
+	password := "12345678"
+
+	passwordDigest, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_digest"}).AddRow(userID, passwordDigest))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT workspace_id FROM `workspace_members` WHERE (user_id = ? AND role = ?)")).
+		WithArgs(userID, entity.WorkspaceRoleOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id"}).AddRow(sharedWorkspaceID).AddRow(ownWorkspaceID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `workspace_members` WHERE (workspace_id IN (?,?) AND user_id <> ?) ORDER BY workspace_id, role = 'owner' DESC, created_at FOR UPDATE")).
+		WithArgs(sharedWorkspaceID, ownWorkspaceID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id", "user_id", "role"}).AddRow(sharedWorkspaceID, memberID, entity.WorkspaceRoleMember))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `workspace_members` SET `role` = ?")).
+		WithArgs(entity.WorkspaceRoleOwner, sharedWorkspaceID, memberID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `boards` SET `workspace_id` = ? WHERE (workspace_id = ?)")).
+		WithArgs(nil, ownWorkspaceID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `workspace_members` WHERE (workspace_id = ?)")).
+		WithArgs(ownWorkspaceID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `workspaces` WHERE (id = ?)")).
+		WithArgs(ownWorkspaceID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `cards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `check_lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT card_id, `key` FROM `files`")).
+		WillReturnRows(sqlmock.NewRows([]string{"card_id", "key"}))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `users`")).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.DeleteAccount(userID, password); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldDeleteAccountWithoutBoards(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+	password := "12345678"
+
+	passwordDigest, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_digest"}).AddRow(userID, passwordDigest))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT workspace_id FROM `workspace_members` WHERE (user_id = ? AND role = ?)")).
+		WithArgs(userID, entity.WorkspaceRoleOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `cards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `check_lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT card_id, `key` FROM `files`")).
+		WillReturnRows(sqlmock.NewRows([]string{"card_id", "key"}))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `users`")).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.DeleteAccount(userID, password); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldFailureDeleteAccountWhenPasswordIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	passwordDigest, _ := bcrypt.GenerateFromPassword([]byte("12345678"), bcrypt.DefaultCost)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_digest"}).AddRow(uint(1), passwordDigest))
+
+	err := r.DeleteAccount(uint(1), "87654321")
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorInvalidPassword)
+}
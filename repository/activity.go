@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// activitiesPerPage is the number of activities in a page.
+const activitiesPerPage = 20
+
+// encodeActivityValues returns JSON of values, or an empty string when there are no values.
+func encodeActivityValues(v entity.ActivityValues) string {
+	if v == nil {
+		return ""
+	}
+
+	b, err := json.Marshal(v)
+
+	if err != nil {
+		log.Printf("fail to marshal activity values: %v", err)
+		return ""
+	}
+
+	return string(b)
+}
+
+// recordActivity creates an activity of the actor with values of the target before and after it.
+// the board is found by the card when only the card of the activity is given.
+// activities are secondary to the change that they record, so that a failure is only logged.
+// activities of a board are recorded one at a time, so that they are published in order of their ids.
+func recordActivity(db *gorm.DB, a entity.Activity, before, after entity.ActivityValues) {
+	if a.BoardID == 0 && a.CardID != nil {
+		var bids []uint
+
+		if err := db.Table("cards").Joins("Join lists ON lists.id = cards.list_id").Where("cards.id = ?", *a.CardID).Pluck("lists.board_id", &bids).Error; err != nil || len(bids) == 0 {
+			log.Printf("fail to find board of activity: %v", err)
+			return
+		}
+
+		a.BoardID = bids[0]
+	}
+
+	a.Before = encodeActivityValues(before)
+	a.After = encodeActivityValues(after)
+
+	unlock := lockActivityOrder(a.BoardID)
+	defer unlock()
+
+	if err := db.Create(&a).Error; err != nil {
+		log.Printf("fail to create activity: %v", err)
+		return
+	}
+
+	publishActivityWithActor(db, a)
+}
+
+// ActivityRepository ...
+type ActivityRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityRepository is constructor for ActivityRepository.
+func NewActivityRepository(db *gorm.DB) *ActivityRepository {
+	return &ActivityRepository{
+		db: db,
+	}
+}
+
+// ValidateUID validates whether the login user is a member of a board of a boardID received as args.
+func (r *ActivityRepository) ValidateUID(bid, uid uint) []validator.ValidationError {
+	var b entity.Board
+
+	if r.db.Select("id").Scopes(memberOf(uid, viewableRoles)).First(&b, bid).RecordNotFound() {
+		return validator.NewValidationErrors(ErrorInvalidSession)
+	}
+
+	return nil
+}
+
+// ValidateCardUID validates whether the login user is a member of a board that has a cardID received as args.
+// activities of a deleted card can be viewed as well.
+func (r *ActivityRepository) ValidateCardUID(cid, uid uint) []validator.ValidationError {
+	var b entity.Board
+
+	if r.db.Joins("Join lists ON boards.id = lists.board_id").
+		Joins("Join cards ON lists.id = cards.list_id").
+		Select("boards.id").
+		Where("cards.id = ?", cid).
+		Scopes(memberOf(uid, viewableRoles)).
+		First(&b).
+		RecordNotFound() {
+		return validator.NewValidationErrors(ErrorInvalidSession)
+	}
+
+	return nil
+}
+
+// GetAll returns activities of a board in a page with their actors, newest first.
+// returns the number of all activities of the board as well.
+func (r *ActivityRepository) GetAll(bid uint, page int) (*[]entity.Activity, int) {
+	return r.paginate(r.db.Where("board_id = ?", bid), page)
+}
+
+// GetAllOfCard returns activities of a card and things that belong to the card in a page, newest first.
+// returns the number of all activities of the card as well.
+func (r *ActivityRepository) GetAllOfCard(cid uint, page int) (*[]entity.Activity, int) {
+	return r.paginate(r.db.Where("card_id = ?", cid), page)
+}
+
+func (r *ActivityRepository) paginate(db *gorm.DB, page int) (*[]entity.Activity, int) {
+	var as []entity.Activity
+	var total int
+
+	db = db.Model(&entity.Activity{})
+
+	db.Count(&total)
+
+	db.Preload("Actor", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, name")
+	}).
+		Order("id desc").
+		Limit(activitiesPerPage).
+		Offset((page - 1) * activitiesPerPage).
+		Find(&as)
+
+	return &as, total
+}
+
+// RecordActivity records an activity of the login user on a board.
+func (r *BoardRepository) RecordActivity(bid, uid uint, action string, before, after entity.ActivityValues) {
+	recordActivity(r.db, entity.Activity{BoardID: bid, ActorID: uid, Action: action, TargetType: entity.ActivityTargetBoard, TargetID: bid}, before, after)
+}
+
+// RecordActivity records an activity of the login user on a list.
+func (r *ListRepository) RecordActivity(l *entity.List, uid uint, action string, before, after entity.ActivityValues) {
+	recordActivity(r.db, entity.Activity{BoardID: l.BoardID, ActorID: uid, Action: action, TargetType: entity.ActivityTargetList, TargetID: l.ID}, before, after)
+}
+
+// RecordActivity records an activity of the login user on a card.
+func (r *CardRepository) RecordActivity(c *entity.Card, uid uint, action string, before, after entity.ActivityValues) {
+	recordActivity(r.db, entity.Activity{CardID: &c.ID, ActorID: uid, Action: action, TargetType: entity.ActivityTargetCard, TargetID: c.ID}, before, after)
+}
+
+// RecordActivity records an activity of the login user on a label.
+func (r *LabelRepository) RecordActivity(l *entity.Label, uid uint, action string, before, after entity.ActivityValues) {
+	recordActivity(r.db, entity.Activity{BoardID: l.BoardID, ActorID: uid, Action: action, TargetType: entity.ActivityTargetLabel, TargetID: l.ID}, before, after)
+}
+
+// RecordActivity records an activity of the login user on a label attached to a card.
+// the activity is identified by the label.
+func (r *CardLabelRepository) RecordActivity(cl *entity.CardLabel, uid uint, action string, before, after entity.ActivityValues) {
+	recordActivity(r.db, entity.Activity{CardID: &cl.CardID, ActorID: uid, Action: action, TargetType: entity.ActivityTargetCardLabel, TargetID: cl.LabelID}, before, after)
+}
+
+// RecordActivity records an activity of the login user on a check list.
+func (r *CheckListRepository) RecordActivity(cl *entity.CheckList, uid uint, action string, before, after entity.ActivityValues) {
+	recordActivity(r.db, entity.Activity{CardID: &cl.CardID, ActorID: uid, Action: action, TargetType: entity.ActivityTargetCheckList, TargetID: cl.ID}, before, after)
+}
+
+// RecordActivity records an activity of the login user on an item of a check list.
+// the card is found by the check list, so that it has to be recorded before the check list is deleted.
+func (r *CheckListItemRepository) RecordActivity(item *entity.CheckListItem, uid uint, action string, before, after entity.ActivityValues) {
+	var cids []uint
+
+	if err := r.db.Model(&entity.CheckList{}).Where("id = ?", item.CheckListID).Pluck("card_id", &cids).Error; err != nil || len(cids) == 0 {
+		log.Printf("fail to find card of activity: %v", err)
+		return
+	}
+
+	recordActivity(r.db, entity.Activity{CardID: &cids[0], ActorID: uid, Action: action, TargetType: entity.ActivityTargetCheckListItem, TargetID: item.ID}, before, after)
+}
+
+// RecordActivity records an activity of the login user on a file.
+func (r *FileRepository) RecordActivity(f *entity.File, uid uint, action string, before, after entity.ActivityValues) {
+	recordActivity(r.db, entity.Activity{CardID: &f.CardID, ActorID: uid, Action: action, TargetType: entity.ActivityTargetFile, TargetID: f.ID}, before, after)
+}
+
+// RecordActivity records an activity of the login user on a cover of a card.
+// a cover is identified by the card.
+func (r *CoverRepository) RecordActivity(co *entity.Cover, uid uint, action string, before, after entity.ActivityValues) {
+	recordActivity(r.db, entity.Activity{CardID: &co.CardID, ActorID: uid, Action: action, TargetType: entity.ActivityTargetCover, TargetID: co.CardID}, before, after)
+}
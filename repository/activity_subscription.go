@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"log"
+	"sync"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+)
+
+// activitySubscriptionBuffer is the number of activities that wait to be sent to a subscriber.
+const activitySubscriptionBuffer = 32
+
+// activitiesPerReplay is the number of activities that are found at once to be sent again to a subscriber.
+const activitiesPerReplay = 100
+
+// activitySubscriptions are channels of subscribers to activities of each board.
+// subscriptions live in this process, so that a subscriber receives activities recorded by this process only.
+var activitySubscriptions = struct {
+	sync.Mutex
+	channels map[uint]map[chan entity.Activity]bool
+}{channels: map[uint]map[chan entity.Activity]bool{}}
+
+// activityOrders are locks of boards that are held while an activity of a board is recorded and published,
+// so that subscribers receive activities of a board in order of their ids and resume from the last id without a gap.
+var activityOrders = struct {
+	sync.Mutex
+	boards map[uint]*sync.Mutex
+}{boards: map[uint]*sync.Mutex{}}
+
+// lockActivityOrder locks recording activities of a board, and returns a function that unlocks it.
+func lockActivityOrder(bid uint) func() {
+	activityOrders.Lock()
+
+	m, ok := activityOrders.boards[bid]
+
+	if !ok {
+		m = &sync.Mutex{}
+		activityOrders.boards[bid] = m
+	}
+
+	activityOrders.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
+
+// publishActivity sends an activity to subscribers of its board without waiting for them.
+// a subscriber that falls behind is closed, and resumes from the last activity that it received when it subscribes again.
+func publishActivity(a entity.Activity) {
+	activitySubscriptions.Lock()
+	defer activitySubscriptions.Unlock()
+
+	for ch := range activitySubscriptions.channels[a.BoardID] {
+		select {
+		case ch <- a:
+		default:
+			delete(activitySubscriptions.channels[a.BoardID], ch)
+			close(ch)
+		}
+	}
+}
+
+// publishActivityWithActor loads the actor of an activity and publishes it, so that a live event has the name of the actor
+// like an activity that is sent again. the actor is not loaded when no one subscribes to the board.
+func publishActivityWithActor(db *gorm.DB, a entity.Activity) {
+	activitySubscriptions.Lock()
+	n := len(activitySubscriptions.channels[a.BoardID])
+	activitySubscriptions.Unlock()
+
+	if n == 0 {
+		return
+	}
+
+	u := &entity.User{}
+
+	if err := db.Select("id, name").First(u, a.ActorID).Error; err != nil {
+		log.Printf("fail to find actor of activity: %v", err)
+	} else {
+		a.Actor = u
+	}
+
+	publishActivity(a)
+}
+
+// Subscribe returns a channel that receives activities of a board as they are recorded.
+// the returned function stops the subscription, and has to be called when the subscriber goes away.
+func (r *ActivityRepository) Subscribe(bid uint) (<-chan entity.Activity, func()) {
+	ch := make(chan entity.Activity, activitySubscriptionBuffer)
+
+	activitySubscriptions.Lock()
+	defer activitySubscriptions.Unlock()
+
+	if activitySubscriptions.channels[bid] == nil {
+		activitySubscriptions.channels[bid] = map[chan entity.Activity]bool{}
+	}
+
+	activitySubscriptions.channels[bid][ch] = true
+
+	return ch, func() {
+		activitySubscriptions.Lock()
+		defer activitySubscriptions.Unlock()
+
+		if activitySubscriptions.channels[bid][ch] {
+			delete(activitySubscriptions.channels[bid], ch)
+			close(ch)
+		}
+
+		if len(activitySubscriptions.channels[bid]) == 0 {
+			delete(activitySubscriptions.channels, bid)
+		}
+	}
+}
+
+// GetSince returns activities of a board that were recorded after an activity of id with their actors, oldest first.
+// up to activitiesPerReplay activities are returned, so that it is called again with the last id until no activity is returned.
+func (r *ActivityRepository) GetSince(bid, id uint) *[]entity.Activity {
+	var as []entity.Activity
+
+	r.db.Preload("Actor", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, name")
+	}).
+		Where("board_id = ? AND id > ?", bid, id).
+		Order("id asc").
+		Limit(activitiesPerReplay).
+		Find(&as)
+
+	return &as
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestShouldRecordActivityOfCardWithBoardOfCard(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCardRepository(db)
+
+	c := &entity.Card{ID: uint(1), Title: "after"}
+	uid := uint(2)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT lists.board_id FROM `cards` Join lists ON lists.id = cards.list_id WHERE (cards.id = ?)")).
+		WithArgs(c.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id"}).AddRow(uint(3)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activities` (`created_at`,`board_id`,`card_id`,`actor_id`,`action`,`target_type`,`target_id`,`before`,`after`)")).
+		WithArgs(utils.AnyTime{}, uint(3), c.ID, uid, "update", "card", c.ID, `{"title":"before"}`, `{"title":"after"}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	r.RecordActivity(c, uid, entity.ActivityUpdate, entity.ActivityValues{"title": "before"}, entity.ActivityValues{"title": c.Title})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldRecordActivityOfCreatedListWithoutBeforeValues(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewListRepository(db)
+
+	l := &entity.List{ID: uint(1), Name: "list", BoardID: uint(3)}
+	uid := uint(2)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activities`")).
+		WithArgs(utils.AnyTime{}, l.BoardID, nil, uid, "create", "list", l.ID, "", `{"name":"list"}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	r.RecordActivity(l, uid, entity.ActivityCreate, nil, entity.ActivityValues{"name": l.Name})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldGetActivitiesOfBoardInPageWithTotal(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewActivityRepository(db)
+
+	boardID := uint(1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activities` WHERE (board_id = ?)")).
+		WithArgs(boardID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activities` WHERE (board_id = ?) ORDER BY id desc LIMIT 20 OFFSET 20")).
+		WithArgs(boardID).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "board_id", "actor_id", "action", "target_type", "target_id", "after"}).
+				AddRow(uint(1), boardID, uint(2), "create", "board", boardID, `{"name":"board"}`))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM `users` WHERE (`id` IN (?))")).
+		WithArgs(uint(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uint(2), "gopher"))
+
+	as, total := r.GetAll(boardID, 2)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, 21, total)
+	assert.Equal(t, "create", (*as)[0].Action)
+	assert.Equal(t, "gopher", (*as)[0].Actor.Name)
+}
+
+func TestShouldFailureValidateUIDOnActivityRepositoryOfCard(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewActivityRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT boards.id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := r.ValidateCardUID(uint(1), uint(2))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidSession), err)
+}
+
+func TestShouldRestoreCardToEndOfList(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCardRepository(db)
+
+	c := &entity.Card{ID: uint(1), ListID: uint(2)}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `index` FROM `cards` WHERE `cards`.`deleted_at` IS NULL AND ((list_id = ?)) ORDER BY `index` desc LIMIT 1")).
+		WithArgs(c.ListID).
+		WillReturnRows(sqlmock.NewRows([]string{"index"}).AddRow(4))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `cards` SET `deleted_at` = ?, `index` = ? WHERE `cards`.`id` = ?")).
+		WithArgs(nil, 5, c.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.Restore(c); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, 5, c.Index)
+	assert.Nil(t, c.DeletedAt)
+}
+
+func TestShouldNotFindCardThatIsNotDeleted(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCardRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `cards`.* FROM `cards` Join lists ON lists.id = cards.list_id Join boards ON boards.id = lists.board_id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := r.FindDeleted(uint(1), uint(2))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorRecordNotFound), err)
+}
+
+func TestShouldPublishRecordedActivityToSubscribersOfBoard(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewActivityRepository(db)
+
+	ch, unsubscribe := r.Subscribe(uint(3))
+	defer unsubscribe()
+
+	other, unsubscribeOther := r.Subscribe(uint(4))
+	defer unsubscribeOther()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activities`")).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM `users` WHERE (`users`.`id` = 2)")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uint(2), "gopher"))
+
+	NewListRepository(db).RecordActivity(&entity.List{ID: uint(1), BoardID: uint(3)}, uint(2), entity.ActivityDelete, nil, nil)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	a := <-ch
+
+	assert.Equal(t, uint(5), a.ID)
+	assert.Equal(t, "delete", a.Action)
+	assert.Equal(t, "gopher", a.Actor.Name)
+	assert.Len(t, other, 0)
+}
+
+func TestShouldCloseSubscriberThatFallsBehindActivities(t *testing.T) {
+	db, _ := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewActivityRepository(db)
+
+	ch, unsubscribe := r.Subscribe(uint(3))
+	defer unsubscribe()
+
+	for i := 0; i <= activitySubscriptionBuffer; i++ {
+		publishActivity(entity.Activity{ID: uint(i + 1), BoardID: uint(3)})
+	}
+
+	n := 0
+
+	for range ch {
+		n++
+	}
+
+	assert.Equal(t, activitySubscriptionBuffer, n)
+}
+
+func TestShouldRecordActivitiesOfBoardOneAtATime(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	unlock := lockActivityOrder(uint(3))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activities`")).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	mock.ExpectCommit()
+
+	done := make(chan bool)
+
+	go func() {
+		NewListRepository(db).RecordActivity(&entity.List{ID: uint(1), BoardID: uint(3)}, uint(2), entity.ActivityCreate, nil, nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("activity was recorded while another activity of the board was recorded")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	<-done
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// adminUsersPerPage is the number of users per page of the administration API.
+const adminUsersPerPage = 50
+
+// IsAdmin check if the user has the admin role and is not disabled.
+func (r *UserRepository) IsAdmin(uid uint) bool {
+	u := &entity.User{}
+
+	if r.db.Select("id").Where("role = ? AND disabled_at IS NULL", entity.RoleAdmin).First(u, uid).RecordNotFound() {
+		return false
+	}
+
+	return true
+}
+
+// SearchUsers returns users whose name or email contains a query, ordered by id.
+// returns the users of a page and the number of all users that matched.
+func (r *UserRepository) SearchUsers(q string, page int) (*[]entity.User, int) {
+	var us []entity.User
+	var total int
+
+	db := r.db.Model(&entity.User{})
+
+	if q != "" {
+		db = db.Where("name LIKE ? OR email LIKE ?", "%"+q+"%", "%"+q+"%")
+	}
+
+	db.Count(&total)
+
+	db.Order("id").
+		Limit(adminUsersPerPage).
+		Offset((page - 1) * adminUsersPerPage).
+		Find(&us)
+
+	return &us, total
+}
+
+// DisableUser marks the user as disabled, and deletes sessions and personal access tokens of the user.
+// the user can not sign in until enabled again. the last active administrator can not be disabled.
+func (r *UserRepository) DisableUser(uid uint) []validator.ValidationError {
+	u, verr := r.Find(uid)
+
+	if verr != nil {
+		return verr
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if verr = rejectLastAdmin(tx, uid); verr != nil {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Model(u).UpdateColumn("disabled_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", uid).Delete(&entity.Session{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", uid).Delete(&entity.AccessToken{}).Error
+	})
+
+	if verr != nil {
+		return verr
+	}
+
+	if err != nil {
+		log.Printf("fail to disable user: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	forgetLiveSessionsOf(uid)
+
+	return nil
+}
+
+// EnableUser clears disabled mark of the user.
+func (r *UserRepository) EnableUser(uid uint) []validator.ValidationError {
+	u, verr := r.Find(uid)
+
+	if verr != nil {
+		return verr
+	}
+
+	if err := r.db.Model(u).UpdateColumn("disabled_at", nil).Error; err != nil {
+		log.Printf("fail to enable user: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return nil
+}
+
+// UpdateRole updates a role of the user.
+// the last active administrator can not be demoted.
+func (r *UserRepository) UpdateRole(uid uint, role string) []validator.ValidationError {
+	u, verr := r.Find(uid)
+
+	if verr != nil {
+		return verr
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if role != entity.RoleAdmin {
+			if verr = rejectLastAdmin(tx, uid); verr != nil {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		return tx.Model(u).UpdateColumn("role", role).Error
+	})
+
+	if verr != nil {
+		return verr
+	}
+
+	if err != nil {
+		log.Printf("fail to update role: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return nil
+}
+
+// rejectLastAdmin returns errors with message if the user is the only active administrator.
+// active administrators are locked until the transaction ends, so that two administrators can not disable or demote each other at the same time.
+func rejectLastAdmin(tx *gorm.DB, uid uint) []validator.ValidationError {
+	var us []entity.User
+
+	if err := tx.Set("gorm:query_option", "FOR UPDATE").
+		Select("id").
+		Where("role = ? AND disabled_at IS NULL", entity.RoleAdmin).
+		Find(&us).Error; err != nil {
+		log.Printf("fail to find administrators: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	if len(us) == 1 && us[0].ID == uid {
+		return validator.NewValidationErrors(ErrorLastAdmin)
+	}
+
+	return nil
+}
+
+// SignOutAll deletes all sessions of the user, so that the user is signed out from every device.
+func (r *UserRepository) SignOutAll(uid uint) []validator.ValidationError {
+	if _, verr := r.Find(uid); verr != nil {
+		return verr
+	}
+
+	if err := r.db.Where("user_id = ?", uid).Delete(&entity.Session{}).Error; err != nil {
+		log.Printf("fail to delete sessions: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	forgetLiveSessionsOf(uid)
+
+	return nil
+}
+
+// UnlockUser forgets failed sign in attempts by the email address of the user, so that the user can sign in again before the lockout ends.
+func (r *UserRepository) UnlockUser(uid uint) []validator.ValidationError {
+	u, verr := r.Find(uid)
+
+	if verr != nil {
+		return verr
+	}
+
+	if err := clearSignInFailures(r.db, u.Email); err != nil {
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return nil
+}
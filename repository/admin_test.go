@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"local.packages/entity"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestIsAdminShouldReturnFalseWhenUserIsNotAdmin(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `users` WHERE (role = ? AND disabled_at IS NULL) AND (`users`.`id` = 1)")).
+		WithArgs(entity.RoleAdmin).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ok := r.IsAdmin(uint(1))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.False(t, ok)
+}
+
+func TestShouldSearchUsersByPage(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` ORDER BY `id` LIMIT 50 OFFSET 0")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)).AddRow(uint(2)))
+
+	us, total := r.SearchUsers("", 1)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, 2, total)
+	assert.Len(t, *us, 2)
+}
+
+func TestShouldDisableUserAndDeleteSessions(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(2)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `users` WHERE (role = ? AND disabled_at IS NULL) FOR UPDATE")).
+		WithArgs(entity.RoleAdmin).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)).AddRow(userID))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `disabled_at` = ? WHERE `users`.`id` = ?")).
+		WithArgs(utils.AnyTime{}, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions` WHERE (user_id = ?)")).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `access_tokens` WHERE (user_id = ?)")).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.DisableUser(userID); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldNotDisableLastAdmin(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(2)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(userID, entity.RoleAdmin))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `users` WHERE (role = ? AND disabled_at IS NULL) FOR UPDATE")).
+		WithArgs(entity.RoleAdmin).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+
+	mock.ExpectRollback()
+
+	err := r.DisableUser(userID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorLastAdmin), err)
+}
+
+func TestShouldNotDemoteLastAdmin(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(2)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(userID, entity.RoleAdmin))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `users` WHERE (role = ? AND disabled_at IS NULL) FOR UPDATE")).
+		WithArgs(entity.RoleAdmin).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+
+	mock.ExpectRollback()
+
+	err := r.UpdateRole(userID, entity.RoleUser)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorLastAdmin), err)
+}
+
+func TestShouldNotSignInWhenUserIsDisabled(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	email := "gopher@sample.com"
+	password := "password"
+	passwordDigest, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE (email = ?)")).
+		WithArgs(email).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "email", "password_digest", "disabled_at"}).
+				AddRow(uint(1), email, passwordDigest, time.Now()))
+
+	_, err := r.SignIn(email, password)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, ErrorUserDisabled, err[0].Text)
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"github.com/jinzhu/gorm"
+
 	"local.packages/entity"
+	"local.packages/validator"
 )
 
 // BackgroundImageRepository ...
@@ -25,3 +27,47 @@ func (r *BackgroundImageRepository) GetAll() *[]entity.BackgroundImage {
 
 	return &bs
 }
+
+// Find returns a record of BackgroundImage that found by id.
+func (r *BackgroundImageRepository) Find(id uint) (*entity.BackgroundImage, []validator.ValidationError) {
+	var b entity.BackgroundImage
+
+	if r.db.First(&b, id).RecordNotFound() {
+		return &b, validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return &b, nil
+}
+
+// Create insert a new record to a background_images table.
+func (r *BackgroundImageRepository) Create(url, theme string) (*entity.BackgroundImage, []validator.ValidationError) {
+	b := &entity.BackgroundImage{
+		URL:   url,
+		Theme: theme,
+	}
+
+	if err := r.db.Create(b).Error; err != nil {
+		return b, validator.FormattedValidationError(err)
+	}
+
+	return b, nil
+}
+
+// Update update a record in a background_images table.
+func (r *BackgroundImageRepository) Update(b *entity.BackgroundImage, url, theme string) []validator.ValidationError {
+	if err := r.db.Model(b).Updates(map[string]interface{}{"url": url, "theme": theme}).Error; err != nil {
+		return validator.FormattedValidationError(err)
+	}
+
+	return nil
+}
+
+// Delete delete a record from a background_images table.
+// boards that use the image lose their background image by foreign key constraint.
+func (r *BackgroundImageRepository) Delete(b *entity.BackgroundImage) []validator.ValidationError {
+	if err := deletionErrors(r.db.Delete(b), "background image"); err != nil {
+		return err
+	}
+
+	return nil
+}
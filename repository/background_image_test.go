@@ -7,6 +7,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"local.packages/utils"
+	"local.packages/validator"
 )
 
 func TestShouldReturnBackgroundImageInstances(t *testing.T) {
@@ -28,3 +29,51 @@ func TestShouldReturnBackgroundImageInstances(t *testing.T) {
 
 	assert.Equal(t, (*bs)[0].ID, backgroundImageID)
 }
+
+func TestShouldSuccessfullyCreateBackgroundImage(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBackgroundImageRepository(db)
+
+	url := "https://example.com/image.jpg"
+	theme := "dark"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `background_images` (`url`,`theme`) VALUES (?,?)")).
+		WithArgs(url, theme).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	b, err := r.Create(url, theme)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, uint(1), b.ID)
+}
+
+func TestShouldNotCreateBackgroundImageWithInvalidParams(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBackgroundImageRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	_, err := r.Create("image.jpg", "blue")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.ErrorURL("画像URL"), err[0].Text)
+	assert.Equal(t, validator.ErrorOneOf("テーマ", "light, dark"), err[1].Text)
+}
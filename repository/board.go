@@ -2,6 +2,7 @@ package repository
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/jinzhu/gorm"
 
@@ -22,10 +23,11 @@ func NewBoardRepository(db *gorm.DB) *BoardRepository {
 }
 
 func selectBoardColumn(db *gorm.DB) *gorm.DB {
-	return db.Select("id, updated_at, name, user_id")
+	return db.Select("id, updated_at, name, user_id, workspace_id, version")
 }
 
 // Find returns a record of Board that contains related model's records.
+// the number of comments of each card is counted as well.
 func (r *BoardRepository) Find(id, uid uint) (*entity.Board, []validator.ValidationError) {
 	var b entity.Board
 
@@ -40,22 +42,46 @@ func (r *BoardRepository) Find(id, uid uint) (*entity.Board, []validator.Validat
 		Preload("Lists.Cards.Labels", func(db *gorm.DB) *gorm.DB {
 			return db.Scopes(selectWithLabelAssociationKey)
 		}).
+		Preload("Lists.Cards.Assignees").
 		Preload("Lists.Cards.Cover").
-		Where("user_id = ?", uid).
+		Scopes(memberOf(uid, viewableRoles)).
 		First(&b, id)
 
 	if rslt.RecordNotFound() {
 		return &b, validator.NewValidationErrors(ErrorRecordNotFound)
 	}
 
+	countComments(r.db, &b)
+
+	now := time.Now()
+
+	for i := range b.Lists {
+		for j := range b.Lists[i].Cards {
+			b.Lists[i].Cards[j].SetDueStatus(now)
+		}
+	}
+
 	return &b, nil
 }
 
 // FindWithoutPreload returns a record of Board without related model's records.
+// the login user must be able to edit the board.
 func (r *BoardRepository) FindWithoutPreload(id, uid uint) (*entity.Board, []validator.ValidationError) {
 	var b entity.Board
 
-	if r.db.Scopes(selectBoardColumn).Where("user_id = ?", uid).First(&b, id).RecordNotFound() {
+	if r.db.Scopes(selectBoardColumn, memberOf(uid, editableRoles)).First(&b, id).RecordNotFound() {
+		return &b, validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return &b, nil
+}
+
+// FindOwned returns a record of Board without related model's records.
+// the login user must be an owner of the board.
+func (r *BoardRepository) FindOwned(id, uid uint) (*entity.Board, []validator.ValidationError) {
+	var b entity.Board
+
+	if r.db.Scopes(selectBoardColumn, memberOf(uid, ownerRoles)).First(&b, id).RecordNotFound() {
 		return &b, validator.NewValidationErrors(ErrorRecordNotFound)
 	}
 
@@ -63,17 +89,30 @@ func (r *BoardRepository) FindWithoutPreload(id, uid uint) (*entity.Board, []val
 }
 
 // Create insert a new record to a boards table.
-func (r *BoardRepository) Create(name string, iid, uid uint) (*entity.Board, []validator.ValidationError) {
+// the login user becomes an owner of the board.
+// if a workspace id is given, the board belongs to the workspace and other members of the workspace join it with the default role.
+func (r *BoardRepository) Create(name string, iid, uid, wid uint) (*entity.Board, []validator.ValidationError) {
 	b := &entity.Board{
 		Name:   name,
 		UserID: uid,
 	}
 
+	if wid != 0 {
+		b.WorkspaceID = &wid
+	}
+
 	i := &entity.BoardBackgroundImage{
 		BackgroundImageID: iid,
 	}
 
+	var verr []validator.ValidationError
+
 	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if wid != 0 && tx.Where("workspace_id = ? AND user_id = ?", wid, uid).First(&entity.WorkspaceMember{}).RecordNotFound() {
+			verr = validator.NewValidationErrors(ErrorInvalidRequest)
+			return gorm.ErrRecordNotFound
+		}
+
 		if err := tx.Create(b).Error; err != nil {
 			return err
 		}
@@ -83,9 +122,24 @@ func (r *BoardRepository) Create(name string, iid, uid uint) (*entity.Board, []v
 		if err := tx.Create(i).Error; err != nil {
 			return err
 		}
-		return nil
+
+		if err := tx.Create(&entity.BoardMember{BoardID: b.ID, UserID: uid, Role: entity.BoardRoleOwner}).Error; err != nil {
+			return err
+		}
+
+		if wid == 0 {
+			return nil
+		}
+
+		return tx.Exec(
+			"INSERT INTO board_members (board_id, user_id, role, created_at) SELECT ?, workspace_members.user_id, workspaces.default_role, ? FROM workspace_members JOIN workspaces ON workspaces.id = workspace_members.workspace_id WHERE workspace_members.workspace_id = ? AND workspace_members.user_id <> ?",
+			b.ID, time.Now(), wid, uid).Error
 	})
 
+	if verr != nil {
+		return b, verr
+	}
+
 	if err != nil {
 		t := reflect.TypeOf(err)
 
@@ -102,40 +156,94 @@ func (r *BoardRepository) Create(name string, iid, uid uint) (*entity.Board, []v
 
 // Update update a record in a boards table.
 func (r *BoardRepository) Update(b *entity.Board, name string) []validator.ValidationError {
-	if err := r.db.Set("gorm:association_autoupdate", false).Model(b).Update("name", name).Error; err != nil {
+	return updateWithVersion(r.db.Set("gorm:association_autoupdate", false), b, &b.Version, map[string]interface{}{"name": name})
+}
+
+// UpdateWorkspace moves a board into a workspace, or out of its workspace if a workspace id is zero.
+// the login user must be a member of the workspace that the board moves into, and members of the workspace join the board with the default role.
+// members of the board stay on it when the board moves out of a workspace, like when the workspace is deleted.
+func (r *BoardRepository) UpdateWorkspace(b *entity.Board, uid, wid uint) []validator.ValidationError {
+	var verr []validator.ValidationError
+	var workspaceID *uint
+
+	if wid != 0 {
+		workspaceID = &wid
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if wid != 0 && tx.Where("workspace_id = ? AND user_id = ?", wid, uid).First(&entity.WorkspaceMember{}).RecordNotFound() {
+			verr = validator.NewValidationErrors(ErrorInvalidRequest)
+			return gorm.ErrRecordNotFound
+		}
+
+		if verr = updateWithVersion(tx.Set("gorm:association_autoupdate", false), b, &b.Version, map[string]interface{}{"workspace_id": workspaceID}); verr != nil {
+			return gorm.ErrRecordNotFound
+		}
+
+		if wid == 0 {
+			return nil
+		}
+
+		return tx.Exec(
+			"INSERT IGNORE INTO board_members (board_id, user_id, role, created_at) SELECT ?, workspace_members.user_id, workspaces.default_role, ? FROM workspace_members JOIN workspaces ON workspaces.id = workspace_members.workspace_id WHERE workspace_members.workspace_id = ?",
+			b.ID, time.Now(), wid).Error
+	})
+
+	if verr != nil {
+		return verr
+	}
+
+	if err != nil {
 		return validator.FormattedValidationError(err)
 	}
 
+	b.WorkspaceID = workspaceID
+
 	return nil
 }
 
 // Delete delete a record from a boards table.
-// use soft delete.
-func (r *BoardRepository) Delete(id, uid uint) []validator.ValidationError {
-	if rslt := r.db.Where("id = ? AND user_id = ?", id, uid).Delete(&entity.Board{}).RowsAffected; rslt == 0 {
-		return validator.NewValidationErrors(ErrorInvalidRequest)
+// use soft delete. only an owner of the board can delete it.
+// if a version is given, the board is deleted only if it still has the version.
+func (r *BoardRepository) Delete(id, uid uint, version *uint) []validator.ValidationError {
+	if err := versionedDeletionErrors(r.db.Where("id = ?", id).Scopes(memberOf(uid, ownerRoles), whereVersion(version)).Delete(&entity.Board{}), "board", version); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// GetAll returns slice of Board's record.
-func (r *BoardRepository) GetAll(uid uint) *[]entity.Board {
+// GetAll returns slice of Board's record that the login user is a member of.
+// if a workspace id is given, returns only boards of the workspace.
+func (r *BoardRepository) GetAll(uid, wid uint) *[]entity.Board {
 	var bs []entity.Board
 
-	r.db.Scopes(selectBoardColumn).Preload("BackgroundImage").Where("user_id = ?", uid).Find(&bs)
+	r.db.Scopes(selectBoardColumn).Preload("BackgroundImage").Scopes(memberOf(uid, viewableRoles), inWorkspace(wid)).Find(&bs)
 
 	return &bs
 }
 
 // Search returns ids of Board that found by Board's name.
-func (r *BoardRepository) Search(name string, uid uint) []uint {
+// if a workspace id is given, searches only boards of the workspace.
+func (r *BoardRepository) Search(name string, uid, wid uint) []uint {
 	var ids []uint
 
 	r.db.Model(&entity.Board{}).
-		Where("user_id = ?", uid).
+		Scopes(memberOf(uid, viewableRoles), inWorkspace(wid)).
 		Where("name LIKE ?", "%"+name+"%").
 		Pluck("id", &ids)
 
 	return ids
 }
+
+// inWorkspace returns a scope that narrows boards down to those of a workspace.
+// the scope does nothing if a workspace id is zero.
+func inWorkspace(wid uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if wid == 0 {
+			return db
+		}
+
+		return db.Where("boards.workspace_id = ?", wid)
+	}
+}
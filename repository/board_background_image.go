@@ -23,7 +23,7 @@ func (r *BoardBackgroundImageRepository) Find(id, uid uint) (*entity.BoardBackgr
 	var b entity.BoardBackgroundImage
 
 	if r.db.Joins("Join boards ON board_background_images.board_id = boards.id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		Where("boards.id = ?", id).
 		First(&b).
 		RecordNotFound() {
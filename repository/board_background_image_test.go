@@ -27,12 +27,12 @@ func TestShouldSuccessfullyFindBoardBackgroundImageRepository(t *testing.T) {
 		SELECT 'board_background_images'.*
 		FROM 'board_background_images'
 		Join boards ON board_background_images.board_id = boards.id
-		WHERE (boards.user_id = ?) AND (boards.id = ?)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND (boards.id = ?)
 		ORDER BY 'board_background_images'.'board_id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(userID, boardID).
+		WithArgs(userID, "owner", "editor", boardID).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"board_id", "background_image_id"}).
 				AddRow(boardID, backgroundImageID))
@@ -64,12 +64,12 @@ func TestShouldNotFindBoardBackgroundImageRepository(t *testing.T) {
 		SELECT 'board_background_images'.*
 		FROM 'board_background_images'
 		Join boards ON board_background_images.board_id = boards.id
-		WHERE (boards.user_id = ?) AND (boards.id = ?)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND (boards.id = ?)
 		ORDER BY 'board_background_images'.'board_id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(userID, boardID).
+		WithArgs(userID, "owner", "editor", boardID).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.Find(boardID, userID)
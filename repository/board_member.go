@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"log"
+	"reflect"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+var (
+	// viewableRoles are roles of members who can view a board.
+	viewableRoles = []string{entity.BoardRoleOwner, entity.BoardRoleEditor, entity.BoardRoleViewer}
+	// editableRoles are roles of members who can edit a board and its lists, cards, labels, checklists, files and covers.
+	editableRoles = []string{entity.BoardRoleOwner, entity.BoardRoleEditor}
+	// ownerRoles are roles of members who can manage members and delete a board.
+	ownerRoles = []string{entity.BoardRoleOwner}
+)
+
+// editableBoardIDs is a subquery of ids of boards that a user can edit.
+// it requires a user id and editableRoles as args.
+const editableBoardIDs = "SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?)"
+
+// memberOf returns a scope that narrows boards down to those the user is a member of with one of roles.
+// a query must refer to boards table.
+func memberOf(uid uint, roles []string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?))", uid, roles)
+	}
+}
+
+// BoardMemberRepository ...
+type BoardMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewBoardMemberRepository is constructor for BoardMemberRepository.
+func NewBoardMemberRepository(db *gorm.DB) *BoardMemberRepository {
+	return &BoardMemberRepository{
+		db: db,
+	}
+}
+
+// ValidateUID validates whether the login user is an owner of a board.
+func (r *BoardMemberRepository) ValidateUID(bid, uid uint) []validator.ValidationError {
+	var b entity.Board
+
+	if r.db.Select("id").Scopes(memberOf(uid, ownerRoles)).First(&b, bid).RecordNotFound() {
+		return validator.NewValidationErrors(ErrorInvalidSession)
+	}
+
+	return nil
+}
+
+// Find returns a record of BoardMember that found by board id and user id.
+func (r *BoardMemberRepository) Find(bid, uid uint) (*entity.BoardMember, []validator.ValidationError) {
+	var m entity.BoardMember
+
+	if r.db.Where("board_id = ? AND user_id = ?", bid, uid).First(&m).RecordNotFound() {
+		return &m, validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return &m, nil
+}
+
+// GetAll returns slice of BoardMember's record with their users.
+// returns nothing unless the login user is a member of the board.
+func (r *BoardMemberRepository) GetAll(bid, uid uint) *[]entity.BoardMember {
+	var ms []entity.BoardMember
+
+	r.db.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, name, email")
+	}).
+		Joins("Join boards ON boards.id = board_members.board_id").
+		Scopes(memberOf(uid, viewableRoles)).
+		Where("board_members.board_id = ?", bid).
+		Order("board_members.created_at").
+		Find(&ms)
+
+	return &ms
+}
+
+// Create insert a new record to a board_members table for the user who has an email address.
+func (r *BoardMemberRepository) Create(bid uint, email, role string) (*entity.BoardMember, []validator.ValidationError) {
+	u := &entity.User{}
+
+	if r.db.Select("id, name, email").Where("email = ?", email).First(u).RecordNotFound() {
+		return nil, validator.NewValidationErrors(ErrorUserDoesNotExist)
+	}
+
+	m := &entity.BoardMember{
+		BoardID: bid,
+		UserID:  u.ID,
+		Role:    role,
+	}
+
+	if err := r.db.Create(m).Error; err != nil {
+		if reflect.TypeOf(err).String() == "*mysql.MySQLError" {
+			return m, validator.FormattedMySQLError(err)
+		}
+		return m, validator.FormattedValidationError(err)
+	}
+
+	m.User = u
+
+	return m, nil
+}
+
+// UpdateRole update a role of a member.
+// a board must have at least one owner, so that the last owner can not be demoted.
+func (r *BoardMemberRepository) UpdateRole(m *entity.BoardMember, role string) []validator.ValidationError {
+	var verr []validator.ValidationError
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if role != entity.BoardRoleOwner && isLastOwner(tx, m) {
+			verr = validator.NewValidationErrors(ErrorLastOwner)
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(m).Update("role", role).Error
+	})
+
+	if verr != nil {
+		return verr
+	}
+
+	if err != nil {
+		return validator.FormattedValidationError(err)
+	}
+
+	return nil
+}
+
+// Delete delete a record from a board_members table.
+// the member is also unassigned from cards of the board.
+// a board must have at least one owner, so that the last owner can not be removed.
+func (r *BoardMemberRepository) Delete(m *entity.BoardMember) []validator.ValidationError {
+	var verr []validator.ValidationError
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if isLastOwner(tx, m) {
+			verr = validator.NewValidationErrors(ErrorLastOwner)
+			return gorm.ErrRecordNotFound
+		}
+
+		if rslt := tx.Where("board_id = ? AND user_id = ?", m.BoardID, m.UserID).Delete(&entity.BoardMember{}); rslt.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return unassignNonMemberCards(tx, m.UserID)
+	})
+
+	if verr != nil {
+		return verr
+	}
+
+	if err != nil {
+		log.Printf("fail to delete board member: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return nil
+}
+
+// isLastOwner check if a member is the only owner of the board.
+// owners of the board are locked until the transaction ends, so that concurrent requests can not remove all owners.
+func isLastOwner(tx *gorm.DB, m *entity.BoardMember) bool {
+	var uids []uint
+
+	tx.Set("gorm:query_option", "FOR UPDATE").
+		Model(&entity.BoardMember{}).
+		Where("board_id = ? AND role = ?", m.BoardID, entity.BoardRoleOwner).
+		Pluck("user_id", &uids)
+
+	return len(uids) == 1 && uids[0] == m.UserID
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestShouldValidateUIDByOwnerRole(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardMemberRepository(db)
+
+	userID := uint(1)
+	boardID := uint(2)
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT id FROM 'boards'
+		WHERE 'boards'.'deleted_at' IS NULL
+		AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?))) AND ('boards'.'id' = 2))`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(userID, "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := r.ValidateUID(boardID, userID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidSession), err)
+}
+
+func TestShouldCreateBoardMember(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardMemberRepository(db)
+
+	boardID := uint(2)
+	userID := uint(3)
+	email := "gopher@sample.com"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email FROM `users` WHERE (email = ?)")).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(userID, "gopher", email))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `board_members` (`board_id`,`user_id`,`role`,`created_at`) VALUES (?,?,?,?)")).
+		WithArgs(boardID, userID, "editor", utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	m, err := r.Create(boardID, email, entity.BoardRoleEditor)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, userID, m.UserID)
+	assert.Equal(t, "gopher", m.User.Name)
+}
+
+func TestShouldNotCreateBoardMemberWithInvalidRole(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardMemberRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(3)))
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	_, err := r.Create(uint(2), "gopher@sample.com", "admin")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.ErrorOneOf("権限", "owner, editor, viewer"), err[0].Text)
+}
+
+func TestShouldNotDemoteLastOwner(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardMemberRepository(db)
+
+	m := &entity.BoardMember{BoardID: uint(2), UserID: uint(1), Role: entity.BoardRoleOwner}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM `board_members` WHERE (board_id = ? AND role = ?) FOR UPDATE")).
+		WithArgs(m.BoardID, "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(m.UserID))
+
+	mock.ExpectRollback()
+
+	err := r.UpdateRole(m, entity.BoardRoleViewer)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorLastOwner), err)
+}
+
+func TestShouldDeleteOwnerWhenAnotherOwnerRemains(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardMemberRepository(db)
+
+	m := &entity.BoardMember{BoardID: uint(2), UserID: uint(1), Role: entity.BoardRoleOwner}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM `board_members` WHERE (board_id = ? AND role = ?) FOR UPDATE")).
+		WithArgs(m.BoardID, "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(m.UserID).AddRow(uint(3)))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `board_members` WHERE (board_id = ? AND user_id = ?)")).
+		WithArgs(m.BoardID, m.UserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `card_assignees` WHERE (user_id = ? AND card_id IN (SELECT cards.id FROM cards JOIN lists ON lists.id = cards.list_id WHERE lists.board_id NOT IN (SELECT board_id FROM board_members WHERE user_id = ?)))")).
+		WithArgs(m.UserID, m.UserID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectCommit()
+
+	if err := r.Delete(m); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
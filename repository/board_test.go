@@ -43,12 +43,15 @@ func TestShouldSuccessfullyFindBoard(t *testing.T) {
 		Index:   1,
 	}
 
+	dueAt := time.Now().Add(time.Hour)
+
 	mockCard := entity.Card{
 		ID:          uint(3),
 		Title:       "mockCard",
 		Description: "mockDescription",
 		ListID:      mockList.ID,
 		Index:       1,
+		DueAt:       &dueAt,
 	}
 
 	mockLabel := entity.Label{
@@ -63,14 +66,14 @@ func TestShouldSuccessfullyFindBoard(t *testing.T) {
 	}
 
 	boardQuery := utils.ReplaceQuotationForQuery(`
-		SELECT id, updated_at, name, user_id
+		SELECT id, updated_at, name, user_id, workspace_id, version
 		FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?) AND ('boards'.'id' = %d))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?))) AND ('boards'.'id' = %d))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(boardQuery, mockBoard.ID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor", "viewer").
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "updated_at", "name", "user_id"}).
 				AddRow(mockBoard.ID, mockBoard.UpdatedAt, mockBoard.Name, userID))
@@ -88,7 +91,7 @@ func TestShouldSuccessfullyFindBoard(t *testing.T) {
 				AddRow(mockBackgroundImage.BoardID, mockBackgroundImage.BackgroundImageID))
 
 	listQuery := utils.ReplaceQuotationForQuery(`
-		SELECT lists.id, lists.name, lists.board_id, lists.index
+		SELECT lists.id, lists.name, lists.board_id, lists.index, lists.version
 		FROM 'lists'
 		WHERE 'lists'.'deleted_at' IS NULL AND (('board_id' IN (?)))
 		ORDER BY lists.index asc,'lists'.'id' ASC`)
@@ -100,7 +103,7 @@ func TestShouldSuccessfullyFindBoard(t *testing.T) {
 				AddRow(mockList.ID, mockList.Name, mockList.BoardID, mockList.Index))
 
 	cardQuery := utils.ReplaceQuotationForQuery(`
-		SELECT cards.id, cards.title, cards.description, cards.list_id, cards.index
+		SELECT cards.id, cards.title, cards.description, cards.list_id, cards.index, cards.start_at, cards.due_at, cards.completed, cards.version
 		FROM 'cards'
 		WHERE 'cards'.'deleted_at' IS NULL AND (('list_id' IN (?)))
 		ORDER BY cards.index asc,'cards'.'id' ASC`)
@@ -108,11 +111,11 @@ func TestShouldSuccessfullyFindBoard(t *testing.T) {
 	mock.ExpectQuery(regexp.QuoteMeta(cardQuery)).
 		WithArgs(mockList.ID).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "title", "description", "list_id", "index"}).
-				AddRow(mockCard.ID, mockCard.Title, mockCard.Description, mockCard.ListID, mockCard.Index))
+			sqlmock.NewRows([]string{"id", "title", "description", "list_id", "index", "due_at"}).
+				AddRow(mockCard.ID, mockCard.Title, mockCard.Description, mockCard.ListID, mockCard.Index, mockCard.DueAt))
 
 	labelQuery := utils.ReplaceQuotationForQuery(`
-		SELECT labels.id, labels.name, labels.color, labels.board_id, card_labels.card_id
+		SELECT labels.id, labels.name, labels.color, labels.board_id, labels.version, card_labels.card_id
 		FROM 'labels'
 		INNER JOIN 'card_labels' ON 'card_labels'.'label_id' = 'labels'.'id'
 		WHERE 'labels'.'deleted_at' IS NULL AND (('card_labels'.'card_id' IN (?)))`)
@@ -122,6 +125,19 @@ func TestShouldSuccessfullyFindBoard(t *testing.T) {
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "name", "color", "card_id"}).
 				AddRow(mockLabel.ID, mockLabel.Name, mockLabel.Color, mockCard.ID))
+
+	assigneeQuery := utils.ReplaceQuotationForQuery(`
+		SELECT *
+		FROM 'card_assignees'
+		WHERE ('card_id' IN (?))
+		ORDER BY 'card_assignees'.'card_id' ASC`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(assigneeQuery)).
+		WithArgs(mockCard.ID).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"card_id", "user_id"}).
+				AddRow(mockCard.ID, userID))
+
 	coverQuery := utils.ReplaceQuotationForQuery(`
 		SELECT *
 		FROM 'covers'
@@ -134,6 +150,18 @@ func TestShouldSuccessfullyFindBoard(t *testing.T) {
 			sqlmock.NewRows([]string{"card_id", "file_id"}).
 				AddRow(mockCover.CardID, mockCover.FileID))
 
+	commentQuery := utils.ReplaceQuotationForQuery(`
+		SELECT card_id, count(*)
+		FROM 'comments'
+		WHERE (card_id IN (?))
+		GROUP BY card_id`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(commentQuery)).
+		WithArgs(mockCard.ID).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"card_id", "count(*)"}).
+				AddRow(mockCard.ID, 2))
+
 	b, err := r.Find(mockBoard.ID, userID)
 
 	if err != nil {
@@ -161,13 +189,19 @@ func TestShouldSuccessfullyFindBoard(t *testing.T) {
 	assert.Equal(t, b.Lists[0].Cards[0].Title, mockCard.Title)
 	assert.Equal(t, b.Lists[0].Cards[0].Description, mockCard.Description)
 	assert.Equal(t, b.Lists[0].Cards[0].ListID, mockCard.ListID)
+	assert.False(t, b.Lists[0].Cards[0].Overdue)
+	assert.True(t, b.Lists[0].Cards[0].DueSoon)
 
 	assert.Equal(t, b.Lists[0].Cards[0].Labels[0].ID, mockLabel.ID)
 	assert.Equal(t, b.Lists[0].Cards[0].Labels[0].Name, mockLabel.Name)
 	assert.Equal(t, b.Lists[0].Cards[0].Labels[0].Color, mockLabel.Color)
 
+	assert.Equal(t, b.Lists[0].Cards[0].Assignees[0].UserID, userID)
+
 	assert.Equal(t, b.Lists[0].Cards[0].Cover.CardID, mockCover.CardID)
 	assert.Equal(t, b.Lists[0].Cards[0].Cover.FileID, mockCover.FileID)
+
+	assert.Equal(t, b.Lists[0].Cards[0].CommentCount, 2)
 }
 
 func TestShouldNotFindBoardWhenUserIdIsInvalid(t *testing.T) {
@@ -180,14 +214,14 @@ func TestShouldNotFindBoardWhenUserIdIsInvalid(t *testing.T) {
 	boardID := uint(2)
 
 	query := utils.ReplaceQuotationForQuery(`
-		SELECT id, updated_at, name, user_id
+		SELECT id, updated_at, name, user_id, workspace_id, version
 		FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?) AND ('boards'.'id' = %d))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?))) AND ('boards'.'id' = %d))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, boardID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor", "viewer").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.Find(boardID, userID)
@@ -215,14 +249,14 @@ func TestShouldSuccessfullyFindBoardWithoutRelatedModel(t *testing.T) {
 	name := "sampleBoard"
 
 	query := utils.ReplaceQuotationForQuery(`
-		SELECT id, updated_at, name, user_id
+		SELECT id, updated_at, name, user_id, workspace_id, version
 		FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?) AND ('boards'.'id' = %d))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('boards'.'id' = %d))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, boardID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "updated_at", "name", "user_id"}).
 				AddRow(boardID, updatedAt, name, userID))
@@ -254,14 +288,14 @@ func TestShouldNotFindBoardWithoutRelatedModel(t *testing.T) {
 	boardID := uint(2)
 
 	query := utils.ReplaceQuotationForQuery(`
-		SELECT id, updated_at, name, user_id
+		SELECT id, updated_at, name, user_id, workspace_id, version
 		FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?) AND ('boards'.'id' = %d))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('boards'.'id' = %d))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, boardID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.FindWithoutPreload(boardID, userID)
@@ -290,25 +324,33 @@ func TestShouldCreateBoard(t *testing.T) {
 	backgroundImageID := uint(2)
 
 	insertBoardQuery := utils.ReplaceQuotationForQuery(`
-		INSERT INTO 'boards' ('created_at','updated_at','deleted_at','name','user_id')
-		VALUES (?,?,?,?,?)`)
+		INSERT INTO 'boards' ('created_at','updated_at','deleted_at','name','user_id','workspace_id','version')
+		VALUES (?,?,?,?,?,?,?)`)
 
 	insertBackgroundImageQuery := utils.ReplaceQuotationForQuery(`
 		INSERT INTO 'board_background_images' ('board_id','background_image_id')
 		VALUES (?,?)`)
 
+	insertMemberQuery := utils.ReplaceQuotationForQuery(`
+		INSERT INTO 'board_members' ('board_id','user_id','role','created_at')
+		VALUES (?,?,?,?)`)
+
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(insertBoardQuery)).
-		WithArgs(createdAt, updatedAt, nil, name, userID).
+		WithArgs(createdAt, updatedAt, nil, name, userID, nil, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectExec(regexp.QuoteMeta(insertBackgroundImageQuery)).
 		WithArgs(1, backgroundImageID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
+	mock.ExpectExec(regexp.QuoteMeta(insertMemberQuery)).
+		WithArgs(1, userID, "owner", utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
 	mock.ExpectCommit()
 
-	b, err := r.Create(name, backgroundImageID, userID)
+	b, err := r.Create(name, backgroundImageID, userID, 0)
 
 	if err != nil {
 		t.Errorf("was not expected an error. %v", err)
@@ -359,7 +401,7 @@ func TestShouldNotCreateBoard(t *testing.T) {
 
 			backgroundImageID := uint(2)
 
-			_, err := r.Create(tc.boardName, backgroundImageID, tc.userID)
+			_, err := r.Create(tc.boardName, backgroundImageID, tc.userID, 0)
 
 			if err == nil {
 				t.Error("was expected an error, but did not recieve it.")
@@ -390,12 +432,12 @@ func TestShouldUpdateBoard(t *testing.T) {
 
 	query := utils.ReplaceQuotationForQuery(`
 		UPDATE 'boards'
-		SET 'name' = ?, 'updated_at' = ?
-		WHERE 'boards'.'deleted_at' IS NULL AND 'boards'.'id' = ?`)
+		SET 'name' = ?, 'updated_at' = ?, 'version' = version + 1
+		WHERE 'boards'.'deleted_at' IS NULL AND 'boards'.'id' = ? AND ((version = ?))`)
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(name, updatedAt, b.ID).
+		WithArgs(name, updatedAt, b.ID, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -409,6 +451,40 @@ func TestShouldUpdateBoard(t *testing.T) {
 	}
 
 	assert.Equal(t, b.Name, name)
+	assert.Equal(t, uint(1), b.Version)
+}
+
+func TestShouldNotUpdateBoardWhenVersionIsStale(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardRepository(db)
+
+	b := &entity.Board{
+		ID:      uint(1),
+		Name:    "sample_board",
+		Version: uint(2),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `boards`")).
+		WithArgs("board", utils.AnyTime{}, b.ID, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	err := r.Update(b, "board")
+
+	if err == nil {
+		t.Error("was expected an error, but did not recieve it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, ErrorConflict, err[0].Text)
+	assert.Equal(t, uint(2), b.Version)
 }
 
 func TestShouldNotUpdateBoard(t *testing.T) {
@@ -471,16 +547,16 @@ func TestShouldSuccessfullyDeleteBoard(t *testing.T) {
 	query := utils.ReplaceQuotationForQuery(`
 		UPDATE 'boards'
 		SET 'deleted_at'=?
-		WHERE 'boards'.'deleted_at' IS NULL AND ((id = ? AND user_id = ?))`)
+		WHERE 'boards'.'deleted_at' IS NULL AND ((id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?))))`)
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(deletedAt, boardID, userID).
+		WithArgs(deletedAt, boardID, userID, "owner").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
 
-	if err := r.Delete(boardID, userID); err != nil {
+	if err := r.Delete(boardID, userID, nil); err != nil {
 		t.Errorf("was not expected an error. %v", err)
 	}
 
@@ -502,16 +578,16 @@ func TestShouldNotDeleteBoard(t *testing.T) {
 	query := utils.ReplaceQuotationForQuery(`
 		UPDATE 'boards'
 		SET 'deleted_at'=?
-		WHERE 'boards'.'deleted_at' IS NULL AND ((id = ? AND user_id = ?))`)
+		WHERE 'boards'.'deleted_at' IS NULL AND ((id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?))))`)
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(deletedAt, boardID, userID).
+		WithArgs(deletedAt, boardID, userID, "owner").
 		WillReturnResult(sqlmock.NewResult(1, 0))
 
 	mock.ExpectCommit()
 
-	err := r.Delete(boardID, userID)
+	err := r.Delete(boardID, userID, nil)
 
 	if err == nil {
 		t.Error("was expected an error, but did not recieved it.")
@@ -521,7 +597,38 @@ func TestShouldNotDeleteBoard(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %v", err)
 	}
 
-	assert.Equal(t, err[0].Text, ErrorInvalidRequest)
+	assert.Equal(t, err[0].Text, ErrorRecordNotFound)
+}
+
+func TestShouldReturnConflictWhenBoardIsUpdatedBeforeDeletion(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardRepository(db)
+
+	boardID := uint(1)
+	userID := uint(2)
+	version := uint(3)
+
+	query := utils.ReplaceQuotationForQuery(`
+		UPDATE 'boards'
+		SET 'deleted_at'=?
+		WHERE 'boards'.'deleted_at' IS NULL AND ((id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?))) AND (version = ?))`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(utils.AnyTime{}, boardID, userID, "owner", version).
+		WillReturnResult(sqlmock.NewResult(1, 0))
+
+	mock.ExpectCommit()
+
+	err := r.Delete(boardID, userID, &version)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorConflict), err)
 }
 
 func TestShouldSuccessfullySearchBoard(t *testing.T) {
@@ -536,14 +643,14 @@ func TestShouldSuccessfullySearchBoard(t *testing.T) {
 
 	query := utils.ReplaceQuotationForQuery(`
 		SELECT id FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?) AND (name LIKE ?))`)
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?))) AND (name LIKE ?))`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(userID, "%"+name+"%").
+		WithArgs(userID, "owner", "editor", "viewer", "%"+name+"%").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).
 			AddRow(boardID))
 
-	ids := r.Search(name, userID)
+	ids := r.Search(name, userID, 0)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Fatalf("there were unfulfilled expectations: %v", err)
@@ -575,9 +682,9 @@ func TestShouldReturnsAllBoardInstances(t *testing.T) {
 	}
 
 	boardQuery := utils.ReplaceQuotationForQuery(`
-		SELECT id, updated_at, name, user_id
+		SELECT id, updated_at, name, user_id, workspace_id, version
 		FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?))`)
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?))))`)
 
 	backgroundImageQuery := utils.ReplaceQuotationForQuery(`
 		SELECT *
@@ -585,7 +692,7 @@ func TestShouldReturnsAllBoardInstances(t *testing.T) {
 		WHERE ('board_id' IN (?))`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(boardQuery)).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor", "viewer").
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "updated_at", "name", "user_id"}).
 				AddRow(mockBoard.ID, mockBoard.UpdatedAt, mockBoard.Name, mockBoard.UserID))
@@ -596,7 +703,7 @@ func TestShouldReturnsAllBoardInstances(t *testing.T) {
 			sqlmock.NewRows([]string{"board_id", "background_image_id"}).
 				AddRow(mockBackgroundImage.BoardID, mockBackgroundImage.BackgroundImageID))
 
-	bs := r.GetAll(userID)
+	bs := r.GetAll(userID, 0)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Fatalf("there were unfulfilled expectations: %v", err)
@@ -610,3 +717,189 @@ func TestShouldReturnsAllBoardInstances(t *testing.T) {
 	assert.Equal(t, (*bs)[0].BackgroundImage.BoardID, mockBackgroundImage.BoardID)
 	assert.Equal(t, (*bs)[0].BackgroundImage.BackgroundImageID, mockBackgroundImage.BackgroundImageID)
 }
+
+func TestShouldCreateBoardInWorkspaceWithMembersOfWorkspace(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardRepository(db)
+
+	userID := uint(1)
+	workspaceID := uint(3)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `workspace_members` WHERE (workspace_id = ? AND user_id = ?)")).
+		WithArgs(workspaceID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id", "user_id", "role"}).AddRow(workspaceID, userID, "member"))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `boards`")).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, nil, "board", userID, workspaceID, 0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `board_background_images`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `board_members`")).
+		WithArgs(1, userID, "owner", utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO board_members (board_id, user_id, role, created_at) SELECT ?, workspace_members.user_id, workspaces.default_role, ?")).
+		WithArgs(1, utils.AnyTime{}, workspaceID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectCommit()
+
+	b, err := r.Create("board", uint(2), userID, workspaceID)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, workspaceID, *b.WorkspaceID)
+}
+
+func TestShouldNotCreateBoardInWorkspaceThatUserIsNotMemberOf(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `workspace_members`")).
+		WithArgs(uint(3), uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id", "user_id", "role"}))
+
+	mock.ExpectRollback()
+
+	_, err := r.Create("board", uint(2), uint(1), uint(3))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidRequest), err)
+}
+
+func TestShouldMoveBoardIntoWorkspaceWithMembersOfWorkspace(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardRepository(db)
+
+	userID := uint(1)
+	workspaceID := uint(3)
+	b := &entity.Board{ID: uint(2), Name: "board", UserID: userID, Version: uint(4)}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `workspace_members` WHERE (workspace_id = ? AND user_id = ?)")).
+		WithArgs(workspaceID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id", "user_id", "role"}).AddRow(workspaceID, userID, "member"))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `boards` SET `updated_at` = ?, `version` = version + 1, `workspace_id` = ? WHERE `boards`.`deleted_at` IS NULL AND `boards`.`id` = ? AND ((version = ?))")).
+		WithArgs(utils.AnyTime{}, workspaceID, b.ID, uint(4)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO board_members (board_id, user_id, role, created_at) SELECT ?, workspace_members.user_id, workspaces.default_role, ?")).
+		WithArgs(b.ID, utils.AnyTime{}, workspaceID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectCommit()
+
+	if err := r.UpdateWorkspace(b, userID, workspaceID); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, workspaceID, *b.WorkspaceID)
+	assert.Equal(t, uint(5), b.Version)
+}
+
+func TestShouldMoveBoardOutOfWorkspace(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardRepository(db)
+
+	workspaceID := uint(3)
+	b := &entity.Board{ID: uint(2), Name: "board", UserID: uint(1), WorkspaceID: &workspaceID}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `boards` SET `updated_at` = ?, `version` = version + 1, `workspace_id` = ? WHERE `boards`.`deleted_at` IS NULL AND `boards`.`id` = ? AND ((version = ?))")).
+		WithArgs(utils.AnyTime{}, nil, b.ID, uint(0)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.UpdateWorkspace(b, uint(1), uint(0)); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Nil(t, b.WorkspaceID)
+}
+
+func TestShouldNotMoveBoardIntoWorkspaceThatUserIsNotMemberOf(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardRepository(db)
+
+	b := &entity.Board{ID: uint(2), Name: "board", UserID: uint(1)}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `workspace_members`")).
+		WithArgs(uint(3), uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id", "user_id", "role"}))
+
+	mock.ExpectRollback()
+
+	err := r.UpdateWorkspace(b, uint(1), uint(3))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidRequest), err)
+	assert.Nil(t, b.WorkspaceID)
+}
+
+func TestShouldGetAllBoardsOfWorkspace(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardRepository(db)
+
+	userID := uint(1)
+	workspaceID := uint(3)
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT id, updated_at, name, user_id, workspace_id, version
+		FROM 'boards'
+		WHERE 'boards'.'deleted_at' IS NULL
+		AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?))) AND (boards.workspace_id = ?))`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(userID, "owner", "editor", "viewer", workspaceID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id"}).AddRow(uint(5), workspaceID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `board_background_images`")).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id", "background_image_id"}))
+
+	bs := r.GetAll(userID, workspaceID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, uint(5), (*bs)[0].ID)
+}
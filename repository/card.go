@@ -13,6 +13,23 @@ import (
 	"local.packages/validator"
 )
 
+// dueCardsWindow is the time after now within which upcoming cards are listed with overdue ones.
+const dueCardsWindow = 7 * 24 * time.Hour
+
+// DueCard is a card with a due date with the list and the board it belongs to.
+type DueCard struct {
+	ID        uint       `json:"id"`
+	Title     string     `json:"title"`
+	ListID    uint       `json:"list_id"`
+	ListName  string     `json:"list_name"`
+	BoardID   uint       `json:"board_id"`
+	BoardName string     `json:"board_name"`
+	StartAt   *time.Time `json:"start_at"`
+	DueAt     time.Time  `json:"due_at"`
+	Overdue   bool       `json:"overdue" gorm:"-"`
+	DueSoon   bool       `json:"due_soon" gorm:"-"`
+}
+
 // CardRepository ...
 type CardRepository struct {
 	db *gorm.DB
@@ -26,17 +43,17 @@ func NewCardRepository(db *gorm.DB) *CardRepository {
 }
 
 func selectCardColumn(db *gorm.DB) *gorm.DB {
-	return db.Select("cards.id, cards.title, cards.description, cards.list_id, cards.index")
+	return db.Select("cards.id, cards.title, cards.description, cards.list_id, cards.index, cards.start_at, cards.due_at, cards.completed, cards.version")
 }
 
-// ValidateUID validates whether a listID received as args was created by the login user.
+// ValidateUID validates whether the login user can edit a board that has a listID received as args.
 func (r *CardRepository) ValidateUID(lid, uid uint) []validator.ValidationError {
 	var b entity.Board
 
 	if r.db.Joins("Join lists ON boards.id = lists.board_id").
 		Select("user_id").
 		Where("lists.id = ?", lid).
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&b).
 		RecordNotFound() {
 		return validator.NewValidationErrors(ErrorInvalidSession)
@@ -51,7 +68,7 @@ func (r *CardRepository) Find(id, uid uint) (*entity.Card, []validator.Validatio
 
 	rslt := r.db.Joins("Join lists ON lists.id = cards.list_id").
 		Joins("Join boards ON boards.id = lists.board_id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&c, id)
 
 	if rslt.RecordNotFound() {
@@ -83,60 +100,168 @@ func (r *CardRepository) Create(title string, lid uint) (*entity.Card, []validat
 
 // UpdateTitle update a record's title in a cards table.
 func (r *CardRepository) UpdateTitle(c *entity.Card, title string) []validator.ValidationError {
-	if err := r.db.Model(c).Updates(map[string]interface{}{"title": title}).Error; err != nil {
-		return validator.FormattedValidationError(err)
-	}
-
-	return nil
+	return updateWithVersion(r.db, c, &c.Version, map[string]interface{}{"title": title})
 }
 
 // UpdateDescription update a record's description in a cards table.
 func (r *CardRepository) UpdateDescription(c *entity.Card, description string) []validator.ValidationError {
-	if err := r.db.Model(c).Updates(map[string]interface{}{"description": description}).Error; err != nil {
-		return validator.FormattedValidationError(err)
+	return updateWithVersion(r.db, c, &c.Version, map[string]interface{}{"description": description})
+}
+
+// UpdateStartAt update a record's start date in a cards table, or clears it when t is nil.
+// the start date must not be later than the due date.
+func (r *CardRepository) UpdateStartAt(c *entity.Card, t *time.Time) []validator.ValidationError {
+	if t != nil && c.DueAt != nil && c.DueAt.Before(*t) {
+		return validator.NewValidationErrors(ErrorDueBeforeStart)
 	}
 
-	return nil
+	return updateWithVersion(r.db, c, &c.Version, map[string]interface{}{"start_at": t})
+}
+
+// UpdateDueAt update a record's due date in a cards table, or clears it when t is nil.
+// the due date must not be earlier than the start date.
+func (r *CardRepository) UpdateDueAt(c *entity.Card, t *time.Time) []validator.ValidationError {
+	if t != nil && c.StartAt != nil && t.Before(*c.StartAt) {
+		return validator.NewValidationErrors(ErrorDueBeforeStart)
+	}
+
+	return updateWithVersion(r.db, c, &c.Version, map[string]interface{}{"due_at": t})
+}
+
+// UpdateCompleted update whether a card is completed in a cards table.
+func (r *CardRepository) UpdateCompleted(c *entity.Card, completed bool) []validator.ValidationError {
+	return updateWithVersion(r.db, c, &c.Version, map[string]interface{}{"completed": completed})
 }
 
 // UpdateIndex update Card's order that recieved as args.
+// cards can be moved only between lists of boards that the login user can edit.
+// a card that was moved to another list is recorded as an activity, and its watchers are notified.
 func (r *CardRepository) UpdateIndex(params []struct {
 	ID     uint `json:"id"`
 	Index  int  `json:"index"`
 	ListID uint `json:"list_id"`
-}) []validator.ValidationError {
+}, uid uint) []validator.ValidationError {
 	ids := make([]string, 0, len(params))
 	listIds := make([]string, 0, len(params))
 	values := make([]string, 0, len(params))
+	lids := map[uint]bool{}
 
 	for _, p := range params {
 		ids = append(ids, strconv.Itoa(int(p.ID)))
 		listIds = append(listIds, strconv.Itoa(int(p.ListID)))
 		values = append(values, strconv.Itoa(p.Index))
+		lids[p.ListID] = true
+	}
+
+	if !r.canEditLists(lids, uid) {
+		return validator.NewValidationErrors(ErrorInvalidRequest)
 	}
 
+	// only cards on boards that the login user can edit are moved, so that other cards are neither recorded nor notified.
+	var before []entity.Card
+
+	r.db.Select("id, list_id").
+		Where("id IN (?) AND list_id IN (SELECT id FROM lists WHERE board_id IN ("+editableBoardIDs+"))", ids, uid, editableRoles).
+		Find(&before)
+
 	joinedIDs := strings.Join(ids, ",")
 	joinedListIDs := strings.Join(listIds, ",")
 	joinedValues := strings.Join(values, ",")
 	q := fmt.Sprintf(
-		"UPDATE `cards` SET `index` = ELT(FIELD(id,%s),%s), `list_id` = ELT(FIELD(id,%s),%s) WHERE id IN (%s)",
+		"UPDATE `cards` SET `index` = ELT(FIELD(id,%s),%s), `list_id` = ELT(FIELD(id,%s),%s) WHERE id IN (%s) AND list_id IN (SELECT id FROM lists WHERE board_id IN (%s))",
 		joinedIDs,
 		joinedValues,
 		joinedIDs,
 		joinedListIDs,
-		joinedIDs)
+		joinedIDs,
+		editableBoardIDs)
 
-	if err := r.db.Exec(q).Error; err != nil {
+	if err := r.db.Exec(q, uid, editableRoles).Error; err != nil {
 		return validator.FormattedValidationError(err)
 	}
+
+	moved := map[uint]uint{}
+
+	for _, c := range before {
+		moved[c.ID] = c.ListID
+	}
+
+	var movedIDs []uint
+
+	for _, p := range params {
+		if from, ok := moved[p.ID]; ok && from != p.ListID {
+			r.RecordActivity(&entity.Card{ID: p.ID}, uid, entity.ActivityMove, entity.ActivityValues{"list_id": from}, entity.ActivityValues{"list_id": p.ListID})
+			movedIDs = append(movedIDs, p.ID)
+		}
+	}
+
+	// watchers of all moved cards are found at once, instead of a query for each card on every drag.
+	if len(movedIDs) > 0 {
+		notifyCards(r.db, entity.NotificationCardMoved, uid, cardsWatcherIDs(r.db, movedIDs))
+	}
+
 	return nil
 }
 
+// canEditLists check if the login user can edit all boards that have lists of ids.
+func (r *CardRepository) canEditLists(ids map[uint]bool, uid uint) bool {
+	lids := make([]uint, 0, len(ids))
+
+	for id := range ids {
+		lids = append(lids, id)
+	}
+
+	var n int
+
+	r.db.Model(&entity.List{}).
+		Joins("Join boards ON boards.id = lists.board_id").
+		Scopes(memberOf(uid, editableRoles)).
+		Where("lists.id IN (?)", lids).
+		Count(&n)
+
+	return n == len(lids)
+}
+
 // Delete delete a record from a cards table.
-// use soft delete.
-func (r *CardRepository) Delete(c *entity.Card) []validator.ValidationError {
-	if rslt := r.db.Model(c).UpdateColumns(map[string]interface{}{"deleted_at": time.Now(), "index": 0}); rslt.RowsAffected == 0 {
-		log.Printf("fail to delete card: %v", rslt.Error)
+// use soft delete. if a version is given, the card is deleted only if it still has the version.
+func (r *CardRepository) Delete(c *entity.Card, version *uint) []validator.ValidationError {
+	if err := versionedDeletionErrors(r.db.Model(c).Scopes(whereVersion(version)).UpdateColumns(map[string]interface{}{"deleted_at": time.Now(), "index": 0}), "card", version); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindDeleted returns a record of Card that was deleted, found by id.
+// the list of the card must not be deleted, so that the card is restored into it.
+func (r *CardRepository) FindDeleted(id, uid uint) (*entity.Card, []validator.ValidationError) {
+	var c entity.Card
+
+	rslt := r.db.Unscoped().
+		Joins("Join lists ON lists.id = cards.list_id").
+		Joins("Join boards ON boards.id = lists.board_id").
+		Scopes(memberOf(uid, editableRoles)).
+		Where("cards.deleted_at IS NOT NULL AND lists.deleted_at IS NULL AND boards.deleted_at IS NULL").
+		First(&c, id)
+
+	if rslt.RecordNotFound() {
+		return &c, validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return &c, nil
+}
+
+// Restore restores a deleted card to the end of its list.
+func (r *CardRepository) Restore(c *entity.Card) []validator.ValidationError {
+	index := 0
+	pc := &entity.Card{}
+
+	if r.db.Select("`index`").Where("list_id = ?", c.ListID).Order("`index` desc").Take(pc).RowsAffected > 0 {
+		index = pc.Index + 1
+	}
+
+	if rslt := r.db.Unscoped().Model(c).UpdateColumns(map[string]interface{}{"deleted_at": nil, "index": index}); rslt.RowsAffected == 0 {
+		log.Printf("fail to restore card: %v", rslt.Error)
 		return validator.NewValidationErrors(ErrorInvalidRequest)
 	}
 
@@ -150,7 +275,7 @@ func (r *CardRepository) Search(bid, uid uint, title string) []uint {
 	r.db.Model(&entity.Card{}).
 		Joins("Join lists ON lists.id = cards.list_id").
 		Joins("Join boards ON boards.id = lists.board_id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, viewableRoles)).
 		Where("boards.id = ?", bid).
 		Where("cards.title LIKE ?", "%"+title+"%").
 		Where("lists.deleted_at IS NULL").
@@ -159,3 +284,25 @@ func (r *CardRepository) Search(bid, uid uint, title string) []uint {
 
 	return ids
 }
+
+// GetDueCards returns slice of cards that are not completed and are overdue or due within dueCardsWindow, across boards the login user is a member of.
+// cards are ordered by their due dates.
+func (r *CardRepository) GetDueCards(uid uint, now time.Time) *[]DueCard {
+	var cs []DueCard
+
+	r.db.Table("cards").
+		Select("cards.id, cards.title, cards.list_id, lists.name AS list_name, boards.id AS board_id, boards.name AS board_name, cards.start_at, cards.due_at").
+		Joins("Join lists ON lists.id = cards.list_id").
+		Joins("Join boards ON boards.id = lists.board_id").
+		Scopes(memberOf(uid, viewableRoles)).
+		Where("cards.due_at IS NOT NULL AND cards.due_at < ? AND cards.completed = ?", now.Add(dueCardsWindow), false).
+		Where("cards.deleted_at IS NULL AND lists.deleted_at IS NULL AND boards.deleted_at IS NULL").
+		Order("cards.due_at asc").
+		Scan(&cs)
+
+	for i := range cs {
+		cs[i].Overdue, cs[i].DueSoon = entity.DueStatus(&cs[i].DueAt, false, now)
+	}
+
+	return &cs
+}
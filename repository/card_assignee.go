@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// AssignedCard is a card assigned to a user with the list and the board it belongs to.
+type AssignedCard struct {
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	ListID    uint   `json:"list_id"`
+	ListName  string `json:"list_name"`
+	BoardID   uint   `json:"board_id"`
+	BoardName string `json:"board_name"`
+}
+
+// unassignNonMemberCards deletes assignments of a user to cards of boards that the user is not a member of anymore.
+func unassignNonMemberCards(tx *gorm.DB, uid uint) error {
+	return tx.Where(
+		"user_id = ? AND card_id IN (SELECT cards.id FROM cards JOIN lists ON lists.id = cards.list_id WHERE lists.board_id NOT IN (SELECT board_id FROM board_members WHERE user_id = ?))",
+		uid, uid).
+		Delete(&entity.CardAssignee{}).
+		Error
+}
+
+// CardAssigneeRepository ...
+type CardAssigneeRepository struct {
+	db *gorm.DB
+}
+
+// NewCardAssigneeRepository is constructor for CardAssigneeRepository.
+func NewCardAssigneeRepository(db *gorm.DB) *CardAssigneeRepository {
+	return &CardAssigneeRepository{
+		db: db,
+	}
+}
+
+// ValidateUID validates whether the login user can edit a board that has a cardID received as args,
+// and the assignee is a member of the board.
+func (r *CardAssigneeRepository) ValidateUID(cid, aid, uid uint) []validator.ValidationError {
+	var b entity.Board
+
+	if r.db.Joins("Join lists ON boards.id = lists.board_id").
+		Joins("Join cards ON lists.id = cards.list_id").
+		Select("boards.id").
+		Where("cards.id = ?", cid).
+		Where("boards.id IN (SELECT board_id FROM board_members WHERE user_id = ?)", aid).
+		Scopes(memberOf(uid, editableRoles)).
+		Find(&b).
+		RecordNotFound() {
+		return validator.NewValidationErrors(ErrorInvalidSession)
+	}
+
+	return nil
+}
+
+// Create insert a new record to a card_assignees table.
+// the assignee is notified unless the login user assigned themselves.
+func (r *CardAssigneeRepository) Create(cid, aid, uid uint) (*entity.CardAssignee, []validator.ValidationError) {
+	ca := &entity.CardAssignee{
+		CardID: cid,
+		UserID: aid,
+	}
+
+	if err := r.db.Create(ca).Error; err != nil {
+		return ca, validator.FormattedMySQLError(err)
+	}
+
+	notifyCard(r.db, entity.NotificationAssigned, cid, uid, []uint{aid})
+
+	return ca, nil
+}
+
+// Find returns a record of CardAssignee that found by card_id and user_id.
+// the login user must be able to edit the board of the card.
+func (r *CardAssigneeRepository) Find(cid, aid, uid uint) (*entity.CardAssignee, []validator.ValidationError) {
+	var ca entity.CardAssignee
+
+	if r.db.Joins("Join cards ON card_assignees.card_id = cards.id").
+		Joins("Join lists ON cards.list_id = lists.id").
+		Joins("Join boards ON lists.board_id = boards.id").
+		Scopes(memberOf(uid, editableRoles)).
+		Where("card_assignees.card_id = ?", cid).
+		Where("card_assignees.user_id = ?", aid).
+		First(&ca).
+		RecordNotFound() {
+		return &ca, validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return &ca, nil
+}
+
+// Delete delete a record from a card_assignees table.
+func (r *CardAssigneeRepository) Delete(ca *entity.CardAssignee) []validator.ValidationError {
+	if err := deletionErrors(r.db.Where("card_id = ? AND user_id = ?", ca.CardID, ca.UserID).Delete(&entity.CardAssignee{}), "card assignee"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAssignedCards returns slice of cards assigned to the login user across boards the user is a member of.
+func (r *CardAssigneeRepository) GetAssignedCards(uid uint) *[]AssignedCard {
+	var cs []AssignedCard
+
+	r.db.Table("cards").
+		Select("cards.id, cards.title, cards.list_id, lists.name AS list_name, boards.id AS board_id, boards.name AS board_name").
+		Joins("Join lists ON lists.id = cards.list_id").
+		Joins("Join boards ON boards.id = lists.board_id").
+		Scopes(memberOf(uid, viewableRoles)).
+		Where("cards.id IN (SELECT card_id FROM card_assignees WHERE user_id = ?)", uid).
+		Where("cards.deleted_at IS NULL AND lists.deleted_at IS NULL AND boards.deleted_at IS NULL").
+		Order("boards.id, lists.index, cards.index").
+		Scan(&cs)
+
+	return &cs
+}
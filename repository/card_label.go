@@ -21,7 +21,7 @@ func NewCardLabelRepository(db *gorm.DB) *CardLabelRepository {
 	}
 }
 
-// ValidateUID validates whether a listID and cardID received as args were created by the login user.
+// ValidateUID validates whether the login user can edit a board that has a labelID and cardID received as args.
 func (r *CardLabelRepository) ValidateUID(lid, cid, uid uint) []validator.ValidationError {
 	var b entity.Board
 
@@ -31,7 +31,7 @@ func (r *CardLabelRepository) ValidateUID(lid, cid, uid uint) []validator.Valida
 		Select("user_id").
 		Where("labels.id = ?", lid).
 		Where("cards.id = ?", cid).
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		Find(&b).
 		RecordNotFound() {
 		return validator.NewValidationErrors(ErrorInvalidSession)
@@ -63,7 +63,7 @@ func (r *CardLabelRepository) Find(lid, cid, uid uint) (*entity.CardLabel, []val
 
 	if r.db.Joins("Join labels ON card_labels.label_id = labels.id").
 		Joins("Join boards ON labels.board_id = boards.id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		Where("card_labels.label_id = ?", lid).
 		Where("card_labels.card_id = ?", cid).
 		First(&cl).
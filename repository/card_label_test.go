@@ -30,10 +30,10 @@ func TestShouldSuccessfullyValidateUIDOnCardLabelRepository(t *testing.T) {
 		Join lists ON boards.id = lists.board_id
 		Join labels ON boards.id = labels.board_id
 		Join cards ON lists.id = cards.list_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((labels.id = ?) AND (cards.id = ?) AND (boards.user_id = ?))`)
+		WHERE 'boards'.'deleted_at' IS NULL AND ((labels.id = ?) AND (cards.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(labelID, cardID, userID).
+		WithArgs(labelID, cardID, userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
 
 	if err := r.ValidateUID(labelID, cardID, userID); err != nil {
@@ -61,10 +61,10 @@ func TestShouldFailureValidateUIDOnCardLabelRepository(t *testing.T) {
 		Join lists ON boards.id = lists.board_id
 		Join labels ON boards.id = labels.board_id
 		Join cards ON lists.id = cards.list_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((labels.id = ?) AND (cards.id = ?) AND (boards.user_id = ?))`)
+		WHERE 'boards'.'deleted_at' IS NULL AND ((labels.id = ?) AND (cards.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(labelID, cardID, userID).
+		WithArgs(labelID, cardID, userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	err := r.ValidateUID(labelID, cardID, userID)
@@ -161,12 +161,12 @@ func TestShouldSuccessfullyFindCardLabel(t *testing.T) {
 		FROM 'card_labels'
 		Join labels ON card_labels.label_id = labels.id
 		Join boards ON labels.board_id = boards.id
-		WHERE (boards.user_id = ?) AND (card_labels.label_id = ?) AND (card_labels.card_id = ?)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND (card_labels.label_id = ?) AND (card_labels.card_id = ?)
 		ORDER BY 'card_labels'.'card_id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(userID, labelID, cardID).
+		WithArgs(userID, "owner", "editor", labelID, cardID).
 		WillReturnRows(sqlmock.NewRows([]string{"card_id", "label_id"}).AddRow(cardID, labelID))
 
 	cl, err := r.Find(labelID, cardID, userID)
@@ -198,12 +198,12 @@ func TestShouldNotFindCardLabel(t *testing.T) {
 		FROM 'card_labels'
 		Join labels ON card_labels.label_id = labels.id
 		Join boards ON labels.board_id = boards.id
-		WHERE (boards.user_id = ?) AND (card_labels.label_id = ?) AND (card_labels.card_id = ?)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND (card_labels.label_id = ?) AND (card_labels.card_id = ?)
 		ORDER BY 'card_labels'.'card_id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(userID, labelID, cardID).
+		WithArgs(userID, "owner", "editor", labelID, cardID).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.Find(labelID, cardID, userID)
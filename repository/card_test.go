@@ -29,12 +29,12 @@ func TestShouldSuccessfullyValidateUIDOnCardRepository(t *testing.T) {
 		SELECT user_id
 		FROM 'boards'
 		Join lists ON boards.id = lists.board_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((lists.id = ?) AND (boards.user_id = ?))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((lists.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(listID, userID).
+		WithArgs(listID, userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(1)))
 
 	if err := r.ValidateUID(listID, userID); err != nil {
@@ -59,12 +59,12 @@ func TestShouldFailureValidateUIDOnCardRepository(t *testing.T) {
 		SELECT user_id
 		FROM 'boards'
 		Join lists ON boards.id = lists.board_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((lists.id = ?) AND (boards.user_id = ?))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((lists.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(listID, userID).
+		WithArgs(listID, userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	err := r.ValidateUID(listID, userID)
@@ -97,12 +97,12 @@ func TestShouldSuccessfullyFindCard(t *testing.T) {
 		FROM 'cards'
 		Join lists ON lists.id = cards.list_id
 		Join boards ON boards.id = lists.board_id
-		WHERE 'cards'.'deleted_at' IS NULL AND ((boards.user_id = ?) AND ('cards'.'id' = %d))
+		WHERE 'cards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('cards'.'id' = %d))
 		ORDER BY 'cards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, cardID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "list_id"}).
 			AddRow(cardID, title, description, listID))
 
@@ -136,12 +136,12 @@ func TestShouldNotFindCard(t *testing.T) {
 		FROM 'cards'
 		Join lists ON lists.id = cards.list_id
 		Join boards ON boards.id = lists.board_id
-		WHERE 'cards'.'deleted_at' IS NULL AND ((boards.user_id = ?) AND ('cards'.'id' = %d))
+		WHERE 'cards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('cards'.'id' = %d))
 		ORDER BY 'cards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, cardID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.Find(cardID, userID)
@@ -178,8 +178,8 @@ func TestShouldSuccessfullyCreateCard(t *testing.T) {
 		LIMIT 1`)
 
 	insertQuery := utils.ReplaceQuotationForQuery(`
-		INSERT INTO 'cards' ('created_at','updated_at','deleted_at','title','description','list_id','index')
-		VALUES (?,?,?,?,?,?,?)`)
+		INSERT INTO 'cards' ('created_at','updated_at','deleted_at','title','description','list_id','index','start_at','due_at','completed','version')
+		VALUES (?,?,?,?,?,?,?,?,?,?,?)`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
 		WithArgs(listID).
@@ -188,7 +188,7 @@ func TestShouldSuccessfullyCreateCard(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
-		WithArgs(createdAt, updatedAt, nil, title, description, listID, preIndex+1).
+		WithArgs(createdAt, updatedAt, nil, title, description, listID, preIndex+1, nil, nil, false, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -286,12 +286,12 @@ func TestShouldSuccessfullyUpdateCardTitle(t *testing.T) {
 
 	query := utils.ReplaceQuotationForQuery(`
 		UPDATE 'cards'
-		SET 'title' = ?, 'updated_at' = ?
-		WHERE 'cards'.'deleted_at' IS NULL AND 'cards'.'id' = ?`)
+		SET 'title' = ?, 'updated_at' = ?, 'version' = version + 1
+		WHERE 'cards'.'deleted_at' IS NULL AND 'cards'.'id' = ? AND ((version = ?))`)
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(title, updatedAt, c.ID).
+		WithArgs(title, updatedAt, c.ID, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -306,6 +306,40 @@ func TestShouldSuccessfullyUpdateCardTitle(t *testing.T) {
 
 	assert.Equal(t, c.Title, title)
 	assert.Equal(t, c.Description, description)
+	assert.Equal(t, uint(1), c.Version)
+}
+
+func TestShouldNotUpdateCardTitleWhenVersionIsStale(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCardRepository(db)
+
+	c := &entity.Card{
+		ID:      uint(1),
+		Title:   "sample card",
+		Version: uint(3),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `cards`")).
+		WithArgs("title", utils.AnyTime{}, c.ID, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	err := r.UpdateTitle(c, "title")
+
+	if err == nil {
+		t.Error("was expected an error, but did not recieve it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, ErrorConflict, err[0].Text)
+	assert.Equal(t, uint(3), c.Version)
 }
 
 func TestShouldNotUpdateCardTitle(t *testing.T) {
@@ -389,12 +423,12 @@ func TestShouldSuccessfullyUpdateCardDescription(t *testing.T) {
 
 			query := utils.ReplaceQuotationForQuery(`
 				UPDATE 'cards'
-				SET 'description' = ?, 'updated_at' = ?
-				WHERE 'cards'.'deleted_at' IS NULL AND 'cards'.'id' = ?`)
+				SET 'description' = ?, 'updated_at' = ?, 'version' = version + 1
+				WHERE 'cards'.'deleted_at' IS NULL AND 'cards'.'id' = ? AND ((version = ?))`)
 
 			mock.ExpectBegin()
 			mock.ExpectExec(regexp.QuoteMeta(query)).
-				WithArgs(tc.description, updatedAt, c.ID).
+				WithArgs(tc.description, updatedAt, c.ID, 0).
 				WillReturnResult(sqlmock.NewResult(1, 1))
 
 			mock.ExpectCommit()
@@ -413,6 +447,116 @@ func TestShouldSuccessfullyUpdateCardDescription(t *testing.T) {
 	}
 }
 
+func TestShouldSuccessfullyClearCardDueAt(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCardRepository(db)
+
+	dueAt := time.Now()
+
+	c := &entity.Card{
+		ID:      uint(1),
+		Title:   "sample card",
+		DueAt:   &dueAt,
+		Version: uint(1),
+	}
+
+	query := utils.ReplaceQuotationForQuery(`
+		UPDATE 'cards'
+		SET 'due_at' = ?, 'updated_at' = ?, 'version' = version + 1
+		WHERE 'cards'.'deleted_at' IS NULL AND 'cards'.'id' = ? AND ((version = ?))`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(nil, utils.AnyTime{}, c.ID, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.UpdateDueAt(c, nil); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Nil(t, c.DueAt)
+	assert.Equal(t, uint(2), c.Version)
+}
+
+func TestShouldNotUpdateCardDueAtBeforeStartAt(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCardRepository(db)
+
+	startAt := time.Now()
+	dueAt := startAt.Add(-time.Hour)
+
+	c := &entity.Card{
+		ID:      uint(1),
+		Title:   "sample card",
+		StartAt: &startAt,
+	}
+
+	err := r.UpdateDueAt(c, &dueAt)
+
+	if err == nil {
+		t.Error("was expected an error, but did not recieve it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, ErrorDueBeforeStart, err[0].Text)
+	assert.Nil(t, c.DueAt)
+}
+
+func TestShouldGetOverdueAndUpcomingCards(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCardRepository(db)
+
+	userID := uint(1)
+	now := time.Now()
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT cards.id, cards.title, cards.list_id, lists.name AS list_name, boards.id AS board_id, boards.name AS board_name, cards.start_at, cards.due_at
+		FROM 'cards'
+		Join lists ON lists.id = cards.list_id
+		Join boards ON boards.id = lists.board_id
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?)))
+		AND (cards.due_at IS NOT NULL AND cards.due_at < ? AND cards.completed = ?)
+		AND (cards.deleted_at IS NULL AND lists.deleted_at IS NULL AND boards.deleted_at IS NULL)
+		ORDER BY cards.due_at asc`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(userID, "owner", "editor", "viewer", now.Add(dueCardsWindow), false).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "title", "list_id", "list_name", "board_id", "board_name", "start_at", "due_at"}).
+				AddRow(uint(2), "overdue", uint(3), "list", uint(4), "board", nil, now.Add(-time.Hour)).
+				AddRow(uint(5), "due soon", uint(3), "list", uint(4), "board", nil, now.Add(time.Hour)).
+				AddRow(uint(6), "upcoming", uint(3), "list", uint(4), "board", nil, now.Add(72*time.Hour)))
+
+	cs := *r.GetDueCards(userID, now)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, 3, len(cs))
+	assert.True(t, cs[0].Overdue)
+	assert.False(t, cs[0].DueSoon)
+	assert.False(t, cs[1].Overdue)
+	assert.True(t, cs[1].DueSoon)
+	assert.False(t, cs[2].Overdue)
+	assert.False(t, cs[2].DueSoon)
+}
+
 func TestShouldSuccessfullyUpdateCardIndex(t *testing.T) {
 	db, mock := utils.NewDBMock(t)
 	defer db.Close()
@@ -433,11 +577,148 @@ func TestShouldSuccessfullyUpdateCardIndex(t *testing.T) {
 		UPDATE 'cards'
 		SET 'index' = ELT(FIELD(id,1,2,3),1,3,2),
 		'list_id' = ELT(FIELD(id,1,2,3),1,1,1)
-		WHERE id IN (1,2,3)`)
+		WHERE id IN (1,2,3)
+		AND list_id IN (SELECT id FROM lists WHERE board_id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?)))`)
+
+	uid := uint(1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `lists`")).
+		WithArgs(uid, "owner", "editor", uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, list_id FROM `cards` WHERE `cards`.`deleted_at` IS NULL AND ((id IN (?,?,?) AND list_id IN (SELECT id FROM lists WHERE board_id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?)))))")).
+		WithArgs("1", "2", "3", uid, "owner", "editor").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}).AddRow(uint(1), uint(1)).AddRow(uint(2), uint(1)).AddRow(uint(3), uint(1)))
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(uid, "owner", "editor").
+		WillReturnResult(sqlmock.NewResult(1, 3))
+
+	if err := r.UpdateIndex(params, uid); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldRecordActivityAndNotifyWatchersOfCardMovedToAnotherList(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCardRepository(db)
+
+	params := []struct {
+		ID     uint `json:"id"`
+		Index  int  `json:"index"`
+		ListID uint `json:"list_id"`
+	}{
+		{ID: 1, Index: 0, ListID: 2},
+		{ID: 2, Index: 0, ListID: 1},
+		{ID: 3, Index: 1, ListID: 1},
+	}
+
+	uid := uint(1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, list_id FROM `cards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}).AddRow(uint(1), uint(1)).AddRow(uint(2), uint(2)).AddRow(uint(3), uint(1)))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `cards` SET")).
+		WillReturnResult(sqlmock.NewResult(1, 3))
+
+	for _, m := range []struct {
+		id, from, to uint
+	}{{1, 1, 2}, {2, 2, 1}} {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT lists.board_id FROM `cards` Join lists ON lists.id = cards.list_id WHERE (cards.id = ?)")).
+			WithArgs(m.id).
+			WillReturnRows(sqlmock.NewRows([]string{"board_id"}).AddRow(uint(5)))
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activities`")).
+			WithArgs(utils.AnyTime{}, uint(5), m.id, uid, "move", "card", m.id, fmt.Sprintf(`{"list_id":%d}`, m.from), fmt.Sprintf(`{"list_id":%d}`, m.to)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+	}
+
+	// watchers of the moved cards are found in one query.
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT card_id, user_id FROM card_assignees WHERE card_id IN (?,?)
+		UNION ALL
+		SELECT cards.id AS card_id, watches.user_id FROM watches
+		Join cards ON cards.id IN (?,?)
+		Join lists ON lists.id = cards.list_id
+		Join board_members ON board_members.board_id = lists.board_id AND board_members.user_id = watches.user_id
+		WHERE (watches.target_type = ? AND watches.target_id = cards.id) OR (watches.target_type = ? AND watches.target_id = lists.id) OR (watches.target_type = ? AND watches.target_id = lists.board_id)`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(uint(1), uint(2), uint(1), uint(2), "card", "list", "board").
+		WillReturnRows(sqlmock.NewRows([]string{"card_id", "user_id"}).
+			AddRow(uint(1), uid).
+			AddRow(uint(1), uint(3)).
+			AddRow(uint(1), uint(3)).
+			AddRow(uint(1), uint(6)).
+			AddRow(uint(2), uint(3)))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT cards.id, cards.title, cards.list_id, lists.board_id FROM `cards` Join lists ON lists.id = cards.list_id WHERE (cards.id IN (?,?))")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "list_id", "board_id"}).
+			AddRow(uint(1), "card", uint(2), uint(5)).
+			AddRow(uint(2), "another card", uint(1), uint(5)))
+
+	// the user who moved the cards is not notified, and an assignee who watches a card is notified once.
+	for _, n := range []struct {
+		user, list, card uint
+		title            string
+	}{{3, 2, 1, "card"}, {6, 2, 1, "card"}, {3, 1, 2, "another card"}} {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `notifications`")).
+			WithArgs(utils.AnyTime{}, n.user, uid, "card_moved", uint(5), n.list, n.card, n.title, nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 
-	mock.ExpectExec(regexp.QuoteMeta(query)).WillReturnResult(sqlmock.NewResult(1, 3))
+		mock.ExpectCommit()
+	}
 
-	if err := r.UpdateIndex(params); err != nil {
+	if err := r.UpdateIndex(params, uid); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldNotRecordActivityOfCardOnBoardThatUserCanNotEdit(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCardRepository(db)
+
+	// card 9 is on a board that the user can not edit, and is moved to an editable list.
+	params := []struct {
+		ID     uint `json:"id"`
+		Index  int  `json:"index"`
+		ListID uint `json:"list_id"`
+	}{
+		{ID: 9, Index: 0, ListID: 2},
+	}
+
+	uid := uint(1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, list_id FROM `cards` WHERE `cards`.`deleted_at` IS NULL AND ((id IN (?) AND list_id IN (SELECT id FROM lists WHERE board_id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?)))))")).
+		WithArgs("9", uid, "owner", "editor").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "list_id"}))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `cards` SET")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := r.UpdateIndex(params, uid); err != nil {
 		t.Errorf("was not expected an error. %v", err)
 	}
 
@@ -473,7 +754,7 @@ func TestShouldSuccessfullyDeleteCard(t *testing.T) {
 
 	mock.ExpectCommit()
 
-	if err := r.Delete(c); err != nil {
+	if err := r.Delete(c, nil); err != nil {
 		t.Errorf("was not expected an error. %v", err)
 	}
 
@@ -512,7 +793,7 @@ func TestShouldNotDeleteCard(t *testing.T) {
 
 	mock.ExpectCommit()
 
-	err := r.Delete(c)
+	err := r.Delete(c, nil)
 
 	if err == nil {
 		t.Error("was expected an error, but did not recieved it.")
@@ -522,7 +803,31 @@ func TestShouldNotDeleteCard(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %v", err)
 	}
 
-	assert.Equal(t, err[0].Text, ErrorInvalidRequest)
+	assert.Equal(t, err[0].Text, ErrorRecordNotFound)
+}
+
+func TestShouldNotReturnConflictWhenDatabaseFailsToDeleteCard(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCardRepository(db)
+
+	c := &entity.Card{ID: uint(1), Index: 1, Version: uint(2)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `cards` SET `deleted_at` = ?, `index` = ? WHERE `cards`.`deleted_at` IS NULL AND `cards`.`id` = ? AND ((version = ?))")).
+		WithArgs(utils.AnyTime{}, 0, c.ID, c.Version).
+		WillReturnError(fmt.Errorf("some error"))
+
+	mock.ExpectRollback()
+
+	err := r.Delete(c, &c.Version)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidRequest), err)
 }
 
 func TestShouldSuccessfullySearchCard(t *testing.T) {
@@ -542,14 +847,14 @@ func TestShouldSuccessfullySearchCard(t *testing.T) {
 		Join lists ON lists.id = cards.list_id
 		Join boards ON boards.id = lists.board_id
 		WHERE 'cards'.'deleted_at' IS NULL
-		AND ((boards.user_id = ?)
+		AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?)))
 		AND (boards.id = ?)
 		AND (cards.title LIKE ?)
 		AND (lists.deleted_at IS NULL))
 		ORDER BY cards.list_id asc`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(userID, boardID, "%"+title+"%").
+		WithArgs(userID, "owner", "editor", "viewer", boardID, "%"+title+"%").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).
 			AddRow(cardID))
 
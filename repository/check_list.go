@@ -1,8 +1,6 @@
 package repository
 
 import (
-	"log"
-
 	"github.com/jinzhu/gorm"
 	"local.packages/entity"
 	"local.packages/validator"
@@ -21,10 +19,10 @@ func NewCheckListRepository(db *gorm.DB) *CheckListRepository {
 }
 
 func selectCheckListColumn(db *gorm.DB) *gorm.DB {
-	return db.Select("check_lists.id, check_lists.title, check_lists.card_id")
+	return db.Select("check_lists.id, check_lists.title, check_lists.card_id, check_lists.version")
 }
 
-// ValidateUID validates whether a cardID received as args was created by the login user.
+// ValidateUID validates whether the login user can edit a board that has a cardID received as args.
 func (r *CheckListRepository) ValidateUID(cid, uid uint) []validator.ValidationError {
 	var b entity.Board
 
@@ -32,7 +30,7 @@ func (r *CheckListRepository) ValidateUID(cid, uid uint) []validator.ValidationE
 		Joins("Join cards ON lists.id = cards.list_id").
 		Select("user_id").
 		Where("cards.id = ?", cid).
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&b).
 		RecordNotFound() {
 		return validator.NewValidationErrors(ErrorInvalidSession)
@@ -48,7 +46,7 @@ func (r *CheckListRepository) Find(id, uid uint) (*entity.CheckList, []validator
 	if r.db.Joins("Join cards ON check_lists.card_id = cards.id").
 		Joins("Join lists ON cards.list_id = lists.id").
 		Joins("Join boards ON lists.board_id = boards.id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&c, id).
 		RecordNotFound() {
 		return &c, validator.NewValidationErrors(ErrorRecordNotFound)
@@ -73,18 +71,14 @@ func (r *CheckListRepository) Create(title string, cid uint) (*entity.CheckList,
 
 // Update update a record's title in a check_lists table
 func (r *CheckListRepository) Update(c *entity.CheckList, title string) []validator.ValidationError {
-	if err := r.db.Model(c).Update("title", title).Error; err != nil {
-		return validator.FormattedValidationError(err)
-	}
-
-	return nil
+	return updateWithVersion(r.db, c, &c.Version, map[string]interface{}{"title": title})
 }
 
 // Delete delete a record from a check_lists table.
-func (r *CheckListRepository) Delete(c *entity.CheckList) []validator.ValidationError {
-	if rslt := r.db.Delete(c); rslt.RowsAffected == 0 {
-		log.Printf("fail to delete check_list: %v", rslt.Error)
-		return validator.NewValidationErrors(ErrorInvalidRequest)
+// if a version is given, the check list is deleted only if it still has the version.
+func (r *CheckListRepository) Delete(c *entity.CheckList, version *uint) []validator.ValidationError {
+	if err := versionedDeletionErrors(r.db.Scopes(whereVersion(version)).Delete(c), "check list", version); err != nil {
+		return err
 	}
 
 	return nil
@@ -102,7 +96,7 @@ func (r *CheckListRepository) GetAll(bid, uid uint) *[]entity.CheckList {
 		Joins("Join lists ON cards.list_id = lists.id").
 		Joins("Join boards ON lists.board_id = boards.id").
 		Where("boards.id = ?", bid).
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, viewableRoles)).
 		Find(&cs)
 
 	return &cs
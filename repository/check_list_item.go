@@ -24,7 +24,7 @@ func selectCheckListItemColumn(db *gorm.DB) *gorm.DB {
 	return db.Select("check_list_items.id, check_list_items.name, check_list_items.check_list_id, check_list_items.check")
 }
 
-// ValidateUID validates whether a checkListID received as args was created by the login user.
+// ValidateUID validates whether the login user can edit a board that has a checkListID received as args.
 func (r *CheckListItemRepository) ValidateUID(cid, uid uint) []validator.ValidationError {
 	var b entity.Board
 
@@ -33,7 +33,7 @@ func (r *CheckListItemRepository) ValidateUID(cid, uid uint) []validator.Validat
 		Joins("Join check_lists ON cards.id = check_lists.card_id").
 		Select("user_id").
 		Where("check_lists.id = ?", cid).
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&b).
 		RecordNotFound() {
 		return validator.NewValidationErrors(ErrorInvalidSession)
@@ -50,7 +50,7 @@ func (r *CheckListItemRepository) Find(id, uid uint) (*entity.CheckListItem, []v
 		Joins("Join cards ON check_lists.card_id = cards.id").
 		Joins("Join lists ON cards.list_id = lists.id").
 		Joins("Join boards ON lists.board_id = boards.id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&item, id).
 		RecordNotFound() {
 		return &item, validator.NewValidationErrors(ErrorRecordNotFound)
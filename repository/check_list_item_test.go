@@ -29,12 +29,12 @@ func TestShouldSuccessfullyValidateUIDOnCheckListItemRepository(t *testing.T) {
 		Join lists ON boards.id = lists.board_id
 		Join cards ON lists.id = cards.list_id
 		Join check_lists ON cards.id = check_lists.card_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((check_lists.id = ?) AND (boards.user_id = ?))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((check_lists.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(checkListID, userID).
+		WithArgs(checkListID, userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
 
 	if err := r.ValidateUID(checkListID, userID); err != nil {
@@ -61,12 +61,12 @@ func TestShouldFailureValidateUIDOnCheckListItemRepository(t *testing.T) {
 		Join lists ON boards.id = lists.board_id
 		Join cards ON lists.id = cards.list_id
 		Join check_lists ON cards.id = check_lists.card_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((check_lists.id = ?) AND (boards.user_id = ?))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((check_lists.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(checkListID, userID).
+		WithArgs(checkListID, userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	err := r.ValidateUID(checkListID, userID)
@@ -98,12 +98,12 @@ func TestShouldSuccessfullyFindCheckListItem(t *testing.T) {
 		Join cards ON check_lists.card_id = cards.id
 		Join lists ON cards.list_id = lists.id
 		Join boards ON lists.board_id = boards.id
-		WHERE (boards.user_id = ?) AND ('check_list_items'.'id' = %d)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('check_list_items'.'id' = %d)
 		ORDER BY 'check_list_items'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, checkListItemID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(checkListItemID))
 
 	item, err := r.Find(checkListItemID, userID)
@@ -135,12 +135,12 @@ func TestShouldNotFindCheckListItem(t *testing.T) {
 		Join cards ON check_lists.card_id = cards.id
 		Join lists ON cards.list_id = lists.id
 		Join boards ON lists.board_id = boards.id
-		WHERE (boards.user_id = ?) AND ('check_list_items'.'id' = %d)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('check_list_items'.'id' = %d)
 		ORDER BY 'check_list_items'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, checkListItemID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.Find(checkListItemID, userID)
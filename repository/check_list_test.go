@@ -28,12 +28,12 @@ func TestShouldSuccessfullyValidateUIDOnCheckListRepository(t *testing.T) {
 		FROM 'boards'
 		Join lists ON boards.id = lists.board_id
 		Join cards ON lists.id = cards.list_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.user_id = ?))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(cardID, userID).
+		WithArgs(cardID, userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
 
 	if err := r.ValidateUID(cardID, userID); err != nil {
@@ -59,12 +59,12 @@ func TestShouldFailureValidateUIDOnCheckListRepository(t *testing.T) {
 		FROM 'boards'
 		Join lists ON boards.id = lists.board_id
 		Join cards ON lists.id = cards.list_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.user_id = ?))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(cardID, userID).
+		WithArgs(cardID, userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	err := r.ValidateUID(cardID, userID)
@@ -95,12 +95,12 @@ func TestShouldSuccessfullyFindCheckList(t *testing.T) {
 		Join cards ON check_lists.card_id = cards.id
 		Join lists ON cards.list_id = lists.id
 		Join boards ON lists.board_id = boards.id
-		WHERE (boards.user_id = ?) AND ('check_lists'.'id' = %d)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('check_lists'.'id' = %d)
 		ORDER BY 'check_lists'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, checkListID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(checkListID))
 
 	cl, err := r.Find(checkListID, userID)
@@ -131,12 +131,12 @@ func TestShouldNotFindCheckList(t *testing.T) {
 		Join cards ON check_lists.card_id = cards.id
 		Join lists ON cards.list_id = lists.id
 		Join boards ON lists.board_id = boards.id
-		WHERE (boards.user_id = ?) AND ('check_lists'.'id' = %d)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('check_lists'.'id' = %d)
 		ORDER BY 'check_lists'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, checkListID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.Find(checkListID, userID)
@@ -164,12 +164,12 @@ func TestShouldSuccessfullyCreateCheckList(t *testing.T) {
 	cardID := uint(1)
 
 	query := utils.ReplaceQuotationForQuery(`
-		INSERT INTO 'check_lists' ('created_at','updated_at','title','card_id')
-		VALUES (?,?,?,?)`)
+		INSERT INTO 'check_lists' ('created_at','updated_at','title','card_id','version')
+		VALUES (?,?,?,?,?)`)
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(createdAt, updatedAt, title, cardID).
+		WithArgs(createdAt, updatedAt, title, cardID, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -250,11 +250,11 @@ func TestShouldSuccessfullyUpdateCheckList(t *testing.T) {
 	updatedAt := utils.AnyTime{}
 	title := "update title"
 
-	query := "UPDATE `check_lists` SET `title` = ?, `updated_at` = ? WHERE `check_lists`.`id` = ?"
+	query := "UPDATE `check_lists` SET `title` = ?, `updated_at` = ?, `version` = version + 1 WHERE `check_lists`.`id` = ? AND ((version = ?))"
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(title, updatedAt, cl.ID).
+		WithArgs(title, updatedAt, cl.ID, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -337,7 +337,7 @@ func TestShouldSuccessfullyDeleteCheckList(t *testing.T) {
 
 	mock.ExpectCommit()
 
-	if err := r.Delete(cl); err != nil {
+	if err := r.Delete(cl, nil); err != nil {
 		t.Errorf("was not expected an error. %v", err)
 	}
 
@@ -365,7 +365,7 @@ func TestShouldFailureDeleteCheckList(t *testing.T) {
 
 	mock.ExpectCommit()
 
-	err := r.Delete(cl)
+	err := r.Delete(cl, nil)
 
 	if err == nil {
 		t.Errorf("was expected an error, but did not recieved it.")
@@ -375,7 +375,7 @@ func TestShouldFailureDeleteCheckList(t *testing.T) {
 		t.Fatalf("there were unfulfilled expectations: %v", err)
 	}
 
-	assert.Equal(t, err[0].Text, ErrorInvalidRequest)
+	assert.Equal(t, err[0].Text, ErrorRecordNotFound)
 }
 
 func TestShouldSuccessfullyGetAllCheckList(t *testing.T) {
@@ -401,14 +401,14 @@ func TestShouldSuccessfullyGetAllCheckList(t *testing.T) {
 	}
 
 	checkListQuery := utils.ReplaceQuotationForQuery(`
-		SELECT check_lists.id, check_lists.title, check_lists.card_id
+		SELECT check_lists.id, check_lists.title, check_lists.card_id, check_lists.version
 		FROM 'check_lists'
 		Join cards ON check_lists.card_id = cards.id
 		Join lists ON cards.list_id = lists.id Join boards ON lists.board_id = boards.id
-		WHERE (boards.id = ?) AND (boards.user_id = ?)`)
+		WHERE (boards.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?)))`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(checkListQuery)).
-		WithArgs(boardID, userID).
+		WithArgs(boardID, userID, "owner", "editor", "viewer").
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "title", "card_id"}).
 				AddRow(mockCheckList.ID, mockCheckList.Title, mockCheckList.CardID))
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// commentsPerPage is the number of comments in a page.
+const commentsPerPage = 20
+
+// CommentRepository ...
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository is constructor for CommentRepository.
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{
+		db: db,
+	}
+}
+
+// ValidateUID validates whether the login user can view a board that has a cardID received as args.
+// every member of the board can comment on its cards.
+func (r *CommentRepository) ValidateUID(cid, uid uint) []validator.ValidationError {
+	var b entity.Board
+
+	if r.db.Joins("Join lists ON boards.id = lists.board_id").
+		Joins("Join cards ON lists.id = cards.list_id").
+		Select("boards.id").
+		Where("cards.id = ?", cid).
+		Where("cards.deleted_at IS NULL").
+		Scopes(memberOf(uid, viewableRoles)).
+		First(&b).
+		RecordNotFound() {
+		return validator.NewValidationErrors(ErrorInvalidSession)
+	}
+
+	return nil
+}
+
+// GetAll returns comments of a card in a page with their authors, newest first.
+// returns the number of all comments of the card as well.
+func (r *CommentRepository) GetAll(cid uint, page int) (*[]entity.Comment, int) {
+	var cs []entity.Comment
+	var total int
+
+	db := r.db.Model(&entity.Comment{}).Where("card_id = ?", cid)
+
+	db.Count(&total)
+
+	db.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, name")
+	}).
+		Order("id desc").
+		Limit(commentsPerPage).
+		Offset((page - 1) * commentsPerPage).
+		Find(&cs)
+
+	return &cs, total
+}
+
+// Find returns a record of Comment that the login user wrote on a board the user is still a member of.
+func (r *CommentRepository) Find(id, uid uint) (*entity.Comment, []validator.ValidationError) {
+	var c entity.Comment
+
+	if r.db.Joins("Join cards ON cards.id = comments.card_id").
+		Joins("Join lists ON lists.id = cards.list_id").
+		Joins("Join boards ON boards.id = lists.board_id").
+		Scopes(memberOf(uid, viewableRoles)).
+		Where("comments.user_id = ?", uid).
+		First(&c, id).
+		RecordNotFound() {
+		return &c, validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return &c, nil
+}
+
+// Create insert a new record to a comments table.
+func (r *CommentRepository) Create(cid, uid uint, body string) (*entity.Comment, []validator.ValidationError) {
+	c := &entity.Comment{
+		CardID: cid,
+		UserID: uid,
+		Body:   body,
+	}
+
+	if err := r.db.Create(c).Error; err != nil {
+		return c, validator.FormattedValidationError(err)
+	}
+
+	return c, nil
+}
+
+// Update update a body of a comment and records when it was edited.
+func (r *CommentRepository) Update(c *entity.Comment, body string) []validator.ValidationError {
+	if err := r.db.Model(c).Updates(map[string]interface{}{"body": body, "edited_at": time.Now()}).Error; err != nil {
+		return validator.FormattedValidationError(err)
+	}
+
+	return nil
+}
+
+// Delete delete a record from a comments table.
+func (r *CommentRepository) Delete(c *entity.Comment) []validator.ValidationError {
+	if err := deletionErrors(r.db.Delete(c), "comment"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// countComments sets the number of comments to each card of a board.
+func countComments(db *gorm.DB, b *entity.Board) {
+	cards := map[uint]*entity.Card{}
+
+	for i := range b.Lists {
+		for j := range b.Lists[i].Cards {
+			c := &b.Lists[i].Cards[j]
+			cards[c.ID] = c
+		}
+	}
+
+	if len(cards) == 0 {
+		return
+	}
+
+	cids := make([]uint, 0, len(cards))
+
+	for id := range cards {
+		cids = append(cids, id)
+	}
+
+	rows, err := db.Model(&entity.Comment{}).
+		Select("card_id, count(*)").
+		Where("card_id IN (?)", cids).
+		Group("card_id").
+		Rows()
+
+	if err != nil {
+		log.Printf("fail to count comments: %v", err)
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid uint
+		var n int
+
+		if err := rows.Scan(&cid, &n); err != nil {
+			log.Printf("fail to count comments: %v", err)
+			return
+		}
+
+		if c, ok := cards[cid]; ok {
+			c.CommentCount = n
+		}
+	}
+}
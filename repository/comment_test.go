@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+)
+
+func TestShouldGetCommentsOfPageWithTotal(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCommentRepository(db)
+
+	cardID := uint(4)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `comments` WHERE (card_id = ?)")).
+		WithArgs(cardID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `comments` WHERE (card_id = ?) ORDER BY id desc LIMIT 20 OFFSET 20")).
+		WithArgs(cardID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "card_id", "user_id", "body"}).AddRow(uint(1), cardID, uint(3), "first"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM `users` WHERE (`id` IN (?))")).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uint(3), "gopher"))
+
+	cs, total := r.GetAll(cardID, 2)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, 21, total)
+	assert.Equal(t, "first", (*cs)[0].Body)
+	assert.Equal(t, "gopher", (*cs)[0].User.Name)
+}
+
+func TestShouldUpdateCommentWithEditedAt(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCommentRepository(db)
+
+	c := &entity.Comment{ID: uint(1), CardID: uint(4), UserID: uint(3), Body: "before"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `comments` SET `body` = ?, `edited_at` = ?, `updated_at` = ? WHERE `comments`.`id` = ?")).
+		WithArgs("after", utils.AnyTime{}, utils.AnyTime{}, c.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.Update(c, "after"); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, "after", c.Body)
+	assert.NotNil(t, c.EditedAt)
+}
+
+func TestShouldNotUpdateCommentWithEmptyBody(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCommentRepository(db)
+
+	c := &entity.Comment{ID: uint(1), CardID: uint(4), UserID: uint(3), Body: "before"}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err := r.Update(c, "")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.NotNil(t, err)
+}
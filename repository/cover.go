@@ -20,7 +20,7 @@ func NewCoverRepository(db *gorm.DB) *CoverRepository {
 	}
 }
 
-// ValidateUID validates whether a cardID received as args was created by the login user.
+// ValidateUID validates whether the login user can edit a board that has a cardID received as args.
 func (r *CoverRepository) ValidateUID(cid, uid uint) []validator.ValidationError {
 	var b entity.Board
 
@@ -28,7 +28,7 @@ func (r *CoverRepository) ValidateUID(cid, uid uint) []validator.ValidationError
 		Joins("Join cards ON lists.id = cards.list_id").
 		Select("user_id").
 		Where("cards.id = ?", cid).
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&b).
 		RecordNotFound() {
 		return validator.NewValidationErrors(ErrorInvalidSession)
@@ -44,7 +44,7 @@ func (r *CoverRepository) Find(cid, uid uint) (*entity.Cover, []validator.Valida
 	if r.db.Joins("Join cards ON covers.card_id = cards.id").
 		Joins("Join lists ON cards.list_id = lists.id").
 		Joins("Join boards ON lists.board_id = boards.id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		Where("covers.card_id = ?", cid).
 		First(&c).
 		RecordNotFound() {
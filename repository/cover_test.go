@@ -26,11 +26,11 @@ func TestShouldSuccessfullyValidateUIDOnCoverRepository(t *testing.T) {
 		SELECT user_id FROM 'boards'
 		Join lists ON boards.id = lists.board_id
 		Join cards ON lists.id = cards.list_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.user_id = ?))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))
 		ORDER BY 'boards'.'id' ASC LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(cardID, userID).
+		WithArgs(cardID, userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
 
 	if err := r.ValidateUID(cardID, userID); err != nil {
@@ -55,11 +55,11 @@ func TestShouldFailureValidateUIDOnCoverRepository(t *testing.T) {
 		SELECT user_id FROM 'boards'
 		Join lists ON boards.id = lists.board_id
 		Join cards ON lists.id = cards.list_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.user_id = ?))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))
 		ORDER BY 'boards'.'id' ASC LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(cardID, userID).
+		WithArgs(cardID, userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	err := r.ValidateUID(cardID, userID)
@@ -158,11 +158,11 @@ func TestShouldSuccessfullyFindCover(t *testing.T) {
 		Join cards ON covers.card_id = cards.id
 		Join lists ON cards.list_id = lists.id
 		Join boards ON lists.board_id = boards.id
-		WHERE (boards.user_id = ?) AND (covers.card_id = ?)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND (covers.card_id = ?)
 		ORDER BY 'covers'.'card_id' ASC LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(userID, cardID).
+		WithArgs(userID, "owner", "editor", cardID).
 		WillReturnRows(sqlmock.NewRows([]string{"card_id", "file_id"}).AddRow(cardID, fileID))
 
 	c, err := r.Find(cardID, userID)
@@ -194,11 +194,11 @@ func TestShouldNotFindCover(t *testing.T) {
 		Join cards ON covers.card_id = cards.id
 		Join lists ON cards.list_id = lists.id
 		Join boards ON lists.board_id = boards.id
-		WHERE (boards.user_id = ?) AND (covers.card_id = ?)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND (covers.card_id = ?)
 		ORDER BY 'covers'.'card_id' ASC LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(userID, cardID).
+		WithArgs(userID, "owner", "editor", cardID).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.Find(cardID, userID)
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"log"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+type seedBoard struct {
+	Name   string
+	Labels []seedLabel
+	Lists  []seedList
+}
+
+type seedLabel struct {
+	Name  string
+	Color string
+}
+
+type seedList struct {
+	Name  string
+	Cards []seedCard
+}
+
+type seedCard struct {
+	Title       string
+	Description string
+	Labels      []string
+	CheckLists  []seedCheckList
+}
+
+type seedCheckList struct {
+	Title string
+	Items []seedCheckListItem
+}
+
+type seedCheckListItem struct {
+	Name  string
+	Check bool
+}
+
+// demoFixture is boards that a test user has right after signing in.
+var demoFixture = []seedBoard{
+	{
+		Name: "Webサイトリニューアル",
+		Labels: []seedLabel{
+			{Name: "デザイン", Color: "#61bd4f"},
+			{Name: "開発", Color: "#0079bf"},
+			{Name: "緊急", Color: "#eb5a46"},
+			{Name: "調査", Color: "#f2d600"},
+		},
+		Lists: []seedList{
+			{
+				Name: "ToDo",
+				Cards: []seedCard{
+					{
+						Title:       "お問い合わせフォームの改善",
+						Description: "入力項目を減らして送信完了までの離脱を防ぐ。",
+						Labels:      []string{"開発"},
+					},
+					{
+						Title:  "競合サイトの調査",
+						Labels: []string{"調査"},
+						CheckLists: []seedCheckList{
+							{
+								Title: "調査対象",
+								Items: []seedCheckListItem{
+									{Name: "トップページの構成"},
+									{Name: "料金ページの見せ方"},
+									{Name: "採用ページの導線"},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "進行中",
+				Cards: []seedCard{
+					{
+						Title:       "トップページのデザイン",
+						Description: "ワイヤーフレームをもとにPC版とスマートフォン版を作成する。",
+						Labels:      []string{"デザイン"},
+						CheckLists: []seedCheckList{
+							{
+								Title: "デザイン",
+								Items: []seedCheckListItem{
+									{Name: "ワイヤーフレーム", Check: true},
+									{Name: "PC版"},
+									{Name: "スマートフォン版"},
+								},
+							},
+						},
+					},
+					{
+						Title:  "表示速度の改善",
+						Labels: []string{"開発", "緊急"},
+					},
+				},
+			},
+			{
+				Name: "完了",
+				Cards: []seedCard{
+					{
+						Title:  "サイトマップの作成",
+						Labels: []string{"調査"},
+					},
+				},
+			},
+		},
+	},
+	{
+		Name: "個人タスク",
+		Labels: []seedLabel{
+			{Name: "プライベート", Color: "#c377e0"},
+			{Name: "仕事", Color: "#ff9f1a"},
+		},
+		Lists: []seedList{
+			{
+				Name: "今週やること",
+				Cards: []seedCard{
+					{
+						Title:  "買い物",
+						Labels: []string{"プライベート"},
+						CheckLists: []seedCheckList{
+							{
+								Title: "買うもの",
+								Items: []seedCheckListItem{
+									{Name: "牛乳", Check: true},
+									{Name: "卵"},
+									{Name: "コーヒー豆"},
+								},
+							},
+						},
+					},
+					{
+						Title:       "週報を書く",
+						Description: "金曜日の17時までに提出する。",
+						Labels:      []string{"仕事"},
+					},
+				},
+			},
+			{
+				Name: "いつかやること",
+				Cards: []seedCard{
+					{
+						Title: "本棚の整理",
+					},
+				},
+			},
+		},
+	},
+}
+
+// ResetTestUser replaces boards of a test user with demoFixture in one transaction.
+// so that every visitor starts with the same sandbox.
+func (r *UserRepository) ResetTestUser(uid uint) []validator.ValidationError {
+	if !r.IsTestUserID(uid) {
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	var fs []entity.File
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+
+		if fs, err = deleteUserBoards(tx, uid); err != nil {
+			return err
+		}
+
+		for _, b := range demoFixture {
+			if err := createSeedBoard(tx, uid, b); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("fail to reset test user: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	deleteFileObjects(fs)
+
+	return nil
+}
+
+func createSeedBoard(tx *gorm.DB, uid uint, sb seedBoard) error {
+	b := &entity.Board{Name: sb.Name, UserID: uid}
+
+	if err := tx.Create(b).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Create(&entity.BoardMember{BoardID: b.ID, UserID: uid, Role: entity.BoardRoleOwner}).Error; err != nil {
+		return err
+	}
+
+	lids := map[string]uint{}
+
+	for _, sl := range sb.Labels {
+		l := &entity.Label{Name: sl.Name, Color: sl.Color, BoardID: b.ID}
+
+		if err := tx.Create(l).Error; err != nil {
+			return err
+		}
+
+		lids[sl.Name] = l.ID
+	}
+
+	for i, sl := range sb.Lists {
+		l := &entity.List{Name: sl.Name, BoardID: b.ID, Index: i}
+
+		if err := tx.Create(l).Error; err != nil {
+			return err
+		}
+
+		for j, sc := range sl.Cards {
+			if err := createSeedCard(tx, l.ID, j, sc, lids); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func createSeedCard(tx *gorm.DB, lid uint, index int, sc seedCard, lids map[string]uint) error {
+	c := &entity.Card{Title: sc.Title, Description: sc.Description, ListID: lid, Index: index}
+
+	if err := tx.Create(c).Error; err != nil {
+		return err
+	}
+
+	for _, name := range sc.Labels {
+		if err := tx.Create(&entity.CardLabel{CardID: c.ID, LabelID: lids[name]}).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, scl := range sc.CheckLists {
+		cl := &entity.CheckList{Title: scl.Title, CardID: c.ID}
+
+		if err := tx.Create(cl).Error; err != nil {
+			return err
+		}
+
+		for _, si := range scl.Items {
+			if err := tx.Create(&entity.CheckListItem{Name: si.Name, Check: si.Check, CheckListID: cl.ID}).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/config"
+	"local.packages/entity"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestShouldResetTestUserToSeedFixture(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	fixture := demoFixture
+	defer func() { demoFixture = fixture }()
+
+	demoFixture = []seedBoard{
+		{
+			Name:   "board",
+			Labels: []seedLabel{{Name: "label", Color: "#ffffff"}},
+			Lists: []seedList{
+				{
+					Name: "list",
+					Cards: []seedCard{
+						{
+							Title:  "card",
+							Labels: []string{"label"},
+							CheckLists: []seedCheckList{
+								{Title: "check list", Items: []seedCheckListItem{{Name: "item", Check: true}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	userID := testUserIDs()[0]
+	oldBoardID := uint(10)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards` WHERE (user_id = ? OR id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role = ?))")).
+		WithArgs(userID, userID, entity.BoardRoleOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(oldBoardID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `board_members` WHERE (board_id IN (?) AND user_id <> ?)")).
+		WithArgs(oldBoardID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id", "user_id", "role"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `cards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `check_lists`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT card_id, `key` FROM `files`")).
+		WillReturnRows(sqlmock.NewRows([]string{"card_id", "key"}))
+
+	for _, q := range []string{"lists", "labels", "board_background_images", "board_members", "invitations", "notifications", "activities"} {
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `" + q + "` WHERE (board_id IN (?))")).
+			WithArgs(oldBoardID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `watches` WHERE (target_type = 'board' AND target_id IN (?))")).
+		WithArgs(oldBoardID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `boards` WHERE (id IN (?))")).
+		WithArgs(oldBoardID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `boards`")).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, nil, "board", userID, nil, 0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `board_members`")).
+		WithArgs(uint(1), userID, "owner", utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `labels`")).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, nil, "label", "#ffffff", uint(1), 0).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `lists`")).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, nil, "list", uint(1), 0, 0).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `cards`")).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, nil, "card", "", uint(3), 0, nil, nil, false, 0).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `card_labels`")).
+		WithArgs(uint(4), uint(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `check_lists`")).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, "check list", uint(4), 0).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `check_list_items`")).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, "item", true, uint(5)).
+		WillReturnResult(sqlmock.NewResult(6, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.ResetTestUser(userID); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldNotResetUserWhoIsNotTestUser(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	err := r.ResetTestUser(uint(1))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidRequest), err)
+}
+
+func TestShouldUseTestUserIDsOfConfig(t *testing.T) {
+	db, _ := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	assert.True(t, r.IsTestUserID(uint(2)))
+	assert.False(t, r.IsTestUserID(uint(10)))
+
+	config.Config.Demo.UserIDs = []uint{10, 11}
+	defer func() { config.Config.Demo.UserIDs = nil }()
+
+	assert.False(t, r.IsTestUserID(uint(2)))
+	assert.True(t, r.IsTestUserID(uint(10)))
+}
+
+func TestIsExpireShouldRejectUserWhoIsNotTestUser(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	email := "gopher@sample.com"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(uint(1), email))
+
+	ok, err := r.IsExpire(email)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.False(t, ok)
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidRequest), err)
+}
@@ -1,5 +1,13 @@
 package repository
 
+import (
+	"log"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/validator"
+)
+
 const (
 	// ErrorRecordNotFound is record not found error text.
 	ErrorRecordNotFound string = "該当するレコードが見つかりませんでした"
@@ -15,4 +23,47 @@ const (
 	ErrorInvalidRequest string = "リククエストが不正です"
 	// ErrorUnavailableTestUser is when test user is unavailable error text.
 	ErrorUnavailableTestUser string = "このテストユーザーは使用中です"
+	// ErrorInvalidToken is an error text when a token is unknown, expired or already used.
+	ErrorInvalidToken string = "トークンが無効か有効期限が切れています"
+	// ErrorAlreadyVerified is an error text when an email address has already been verified.
+	ErrorAlreadyVerified string = "メールアドレスは確認済みです"
+	// ErrorInvalidTOTPCode is an error text when a code of two-factor authentication is invalid.
+	ErrorInvalidTOTPCode string = "認証コードが正しくありません"
+	// ErrorTOTPAlreadyEnabled is an error text when two-factor authentication has already been enabled.
+	ErrorTOTPAlreadyEnabled string = "二段階認証は既に有効です"
+	// ErrorTOTPNotEnabled is an error text when two-factor authentication has not been enabled.
+	ErrorTOTPNotEnabled string = "二段階認証が有効になっていません"
+	// ErrorIdentityEmailNotVerified is an error text when an email address of an external account has not been verified by the provider.
+	ErrorIdentityEmailNotVerified string = "外部アカウントのメールアドレスが確認されていません"
+	// ErrorIdentityAccountNotVerified is an error text when an external account is going to be linked to a user whose email address has not been verified.
+	ErrorIdentityAccountNotVerified string = "既存のアカウントのメールアドレスが確認されていません。パスワードでサインインしてメールアドレスを確認してください"
+	// ErrorUserDisabled is an error text when an account was disabled by an administrator.
+	ErrorUserDisabled string = "このアカウントは利用停止されています"
+	// ErrorLastAdmin is an error text when the last active administrator is going to be disabled or demoted.
+	ErrorLastAdmin string = "有効な管理者が1人もいなくなるため変更できません"
+	// ErrorLastOwner is an error text when the last owner of a board is going to be removed or demoted.
+	ErrorLastOwner string = "ボードには少なくとも1人のオーナーが必要です"
+	// ErrorLastWorkspaceOwner is an error text when the last owner of a workspace is going to be removed.
+	ErrorLastWorkspaceOwner string = "ワークスペースには少なくとも1人のオーナーが必要です"
+	// ErrorAlreadyMember is an error text when the user who accepts an invitation is already a member of the board.
+	ErrorAlreadyMember string = "既にボードのメンバーです"
+	// ErrorConflict is an error text when a record was updated by another request after the client got it.
+	ErrorConflict string = "他のユーザーによって更新されています。最新の内容を確認してください"
+	// ErrorDueBeforeStart is an error text when a due date of a card is earlier than its start date.
+	ErrorDueBeforeStart string = "期限は開始日時以降に設定してください"
 )
+
+// deletionErrors returns errors of a deletion of a record by its result.
+// an error of the database is logged and returned as ErrorInvalidRequest, and a deletion that affected no rows returns ErrorRecordNotFound.
+func deletionErrors(rslt *gorm.DB, name string) []validator.ValidationError {
+	if rslt.Error != nil {
+		log.Printf("fail to delete %s: %v", name, rslt.Error)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	if rslt.RowsAffected == 0 {
+		return validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return nil
+}
@@ -32,7 +32,7 @@ func selectFileColumn(db *gorm.DB) *gorm.DB {
 	return db.Select("files.id, files.display_name, files.url, files.content_type, files.card_id")
 }
 
-// ValidateUID validates whether a cardID received as args was created by the login user.
+// ValidateUID validates whether the login user can edit a board that has a cardID received as args.
 func (r *FileRepository) ValidateUID(cid, uid uint) []validator.ValidationError {
 	var b entity.Board
 
@@ -40,7 +40,7 @@ func (r *FileRepository) ValidateUID(cid, uid uint) []validator.ValidationError
 		Joins("Join cards ON lists.id = cards.list_id").
 		Select("user_id").
 		Where("cards.id = ?", cid).
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&b).
 		RecordNotFound() {
 		return validator.NewValidationErrors(ErrorInvalidSession)
@@ -56,7 +56,7 @@ func (r *FileRepository) Find(id, uid uint) (*entity.File, []validator.Validatio
 	if r.db.Joins("Join cards ON files.card_id = cards.id").
 		Joins("Join lists ON cards.list_id = lists.id").
 		Joins("Join boards ON lists.board_id = boards.id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&f, id).
 		RecordNotFound() {
 		return &f, validator.NewValidationErrors(ErrorRecordNotFound)
@@ -134,6 +134,10 @@ func (r *FileRepository) Delete(f *entity.File) []validator.ValidationError {
 
 // DeleteObject deletes an object from S3 bucket.
 func (r *FileRepository) DeleteObject(key string) error {
+	return deleteObject(key)
+}
+
+func deleteObject(key string) error {
 	svc := s3.New(config.AWSSession())
 
 	_, err := svc.DeleteObject(&s3.DeleteObjectInput{
@@ -158,7 +162,7 @@ func (r *FileRepository) GetAll(bid, uid uint) *[]entity.File {
 		Joins("Join lists ON cards.list_id = lists.id").
 		Joins("Join boards ON lists.board_id = boards.id").
 		Where("boards.id = ?", bid).
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, viewableRoles)).
 		Find(&fs)
 
 	return &fs
@@ -26,12 +26,12 @@ func TestShouldSuccessfullyValidateUIDOnFileRepository(t *testing.T) {
 		FROM 'boards'
 		Join lists ON boards.id = lists.board_id
 		Join cards ON lists.id = cards.list_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.user_id = ?))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(cardID, userID).
+		WithArgs(cardID, userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
 
 	if err := r.ValidateUID(cardID, userID); err != nil {
@@ -57,12 +57,12 @@ func TestShouldFailureValidateUIDOnFileRepository(t *testing.T) {
 		FROM 'boards'
 		Join lists ON boards.id = lists.board_id
 		Join cards ON lists.id = cards.list_id
-		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.user_id = ?))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((cards.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(cardID, userID).
+		WithArgs(cardID, userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	err := r.ValidateUID(cardID, userID)
@@ -131,10 +131,10 @@ func TestShouldSuccessfullyFindFile(t *testing.T) {
 		Join cards ON files.card_id = cards.id
 		Join lists ON cards.list_id = lists.id
 		Join boards ON lists.board_id = boards.id
-		WHERE (boards.user_id = ?) AND ('files'.'id' = %d) ORDER BY 'files'.'id' ASC LIMIT 1`)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('files'.'id' = %d) ORDER BY 'files'.'id' ASC LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, fileID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(fileID))
 
 	f, err := r.Find(fileID, userID)
@@ -165,10 +165,10 @@ func TestShouldNotFindFile(t *testing.T) {
 		Join cards ON files.card_id = cards.id
 		Join lists ON cards.list_id = lists.id
 		Join boards ON lists.board_id = boards.id
-		WHERE (boards.user_id = ?) AND ('files'.'id' = %d) ORDER BY 'files'.'id' ASC LIMIT 1`)
+		WHERE (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('files'.'id' = %d) ORDER BY 'files'.'id' ASC LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, fileID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.Find(fileID, userID)
@@ -267,10 +267,10 @@ func TestShouldSuccessfullyGetAllFiles(t *testing.T) {
 		Join cards ON files.card_id = cards.id
 		Join lists ON cards.list_id = lists.id
 		Join boards ON lists.board_id = boards.id
-		WHERE (boards.id = ?) AND (boards.user_id = ?)`)
+		WHERE (boards.id = ?) AND (boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?)))`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(query)).
-		WithArgs(boardID, userID).
+		WithArgs(boardID, userID, "owner", "editor", "viewer").
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id", "display_name", "url", "content_type", "card_id"}).
 				AddRow(mockFile.ID, mockFile.DisplayName, mockFile.URL, mockFile.ContentType, mockFile.CardID))
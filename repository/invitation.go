@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// invitationLifetime is the lifetime of an invitation when an expiry is not specified.
+const invitationLifetime = time.Hour * 24 * 7
+
+func pendingInvitation(db *gorm.DB) *gorm.DB {
+	return db.Where("expires_at > ? AND (single_use = ? OR used_at IS NULL)", time.Now(), false)
+}
+
+// CreateInvitation insert a new record to invitations table and returns the raw token.
+// the raw token can not be retrieved again.
+func (r *BoardMemberRepository) CreateInvitation(bid, uid uint, role string, singleUse bool, expiresAt *time.Time) (*entity.Invitation, string, []validator.ValidationError) {
+	inv := &entity.Invitation{
+		BoardID:   bid,
+		InviterID: uid,
+		Role:      role,
+		SingleUse: singleUse,
+		ExpiresAt: time.Now().Add(invitationLifetime),
+	}
+
+	if expiresAt != nil {
+		inv.ExpiresAt = *expiresAt
+	}
+
+	t, err := newSessionToken()
+
+	if err != nil {
+		log.Printf("fail to create invitation: %v", err)
+		return inv, "", validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	inv.Digest = digestToken(t)
+
+	if err := r.db.Create(inv).Error; err != nil {
+		if reflect.TypeOf(err).String() == "*mysql.MySQLError" {
+			return inv, "", validator.FormattedMySQLError(err)
+		}
+		return inv, "", validator.FormattedValidationError(err)
+	}
+
+	return inv, t, nil
+}
+
+// GetInvitations returns slice of Invitation's record that can still be accepted.
+func (r *BoardMemberRepository) GetInvitations(bid uint) *[]entity.Invitation {
+	var invs []entity.Invitation
+
+	r.db.Scopes(pendingInvitation).
+		Where("board_id = ?", bid).
+		Order("created_at desc").
+		Find(&invs)
+
+	return &invs
+}
+
+// DeleteInvitation delete an invitation of a board, so that its link can not be accepted anymore.
+func (r *BoardMemberRepository) DeleteInvitation(id, bid uint) []validator.ValidationError {
+	if err := deletionErrors(r.db.Where("id = ? AND board_id = ?", id, bid).Delete(&entity.Invitation{}), "invitation"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindInvitation returns an invitation of a token that can still be accepted.
+func (r *UserRepository) FindInvitation(token string) (*entity.Invitation, []validator.ValidationError) {
+	var inv entity.Invitation
+
+	if r.db.Scopes(pendingInvitation).Where("digest = ?", digestToken(token)).First(&inv).RecordNotFound() {
+		return &inv, validator.NewValidationErrors(ErrorInvalidToken)
+	}
+
+	return &inv, nil
+}
+
+// AcceptInvitation adds the user to members of a board of an invitation with the role of the invitation.
+// a single use invitation is marked as used, and can not be accepted again.
+func (r *UserRepository) AcceptInvitation(token string, uid uint) (*entity.BoardMember, []validator.ValidationError) {
+	var m *entity.BoardMember
+	var verr []validator.ValidationError
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var inv entity.Invitation
+
+		if tx.Set("gorm:query_option", "FOR UPDATE").
+			Scopes(pendingInvitation).
+			Where("digest = ?", digestToken(token)).
+			First(&inv).
+			RecordNotFound() {
+			verr = validator.NewValidationErrors(ErrorInvalidToken)
+			return gorm.ErrRecordNotFound
+		}
+
+		if tx.Select("id").First(&entity.Board{}, inv.BoardID).RecordNotFound() {
+			verr = validator.NewValidationErrors(ErrorInvalidToken)
+			return gorm.ErrRecordNotFound
+		}
+
+		if !tx.Where("board_id = ? AND user_id = ?", inv.BoardID, uid).First(&entity.BoardMember{}).RecordNotFound() {
+			verr = validator.NewValidationErrors(ErrorAlreadyMember)
+			return gorm.ErrRecordNotFound
+		}
+
+		m = &entity.BoardMember{BoardID: inv.BoardID, UserID: uid, Role: inv.Role}
+
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+
+		if inv.SingleUse {
+			return tx.Model(&inv).UpdateColumn("used_at", time.Now()).Error
+		}
+
+		return nil
+	})
+
+	if verr != nil {
+		return nil, verr
+	}
+
+	if err != nil {
+		log.Printf("fail to accept invitation: %v", err)
+		return nil, validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestShouldCreateInvitationWithDigestOfToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardMemberRepository(db)
+
+	boardID := uint(2)
+	userID := uint(1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `invitations` (`created_at`,`board_id`,`inviter_id`,`role`,`digest`,`single_use`,`expires_at`,`used_at`) VALUES (?,?,?,?,?,?,?,?)")).
+		WithArgs(utils.AnyTime{}, boardID, userID, "editor", sqlmock.AnyArg(), true, utils.AnyTime{}, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	inv, token, err := r.CreateInvitation(boardID, userID, entity.BoardRoleEditor, true, nil)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.NotEmpty(t, token)
+	assert.Equal(t, utils.DigestToken(token), inv.Digest)
+	assert.WithinDuration(t, time.Now().Add(invitationLifetime), inv.ExpiresAt, time.Minute)
+}
+
+func TestShouldNotCreateInvitationWithInvalidRole(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardMemberRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	_, _, err := r.CreateInvitation(uint(2), uint(1), "admin", false, nil)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.NotNil(t, err)
+}
+
+func TestShouldAcceptSingleUseInvitationAndMarkItAsUsed(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	token := "sampletoken"
+	invitationID := uint(5)
+	boardID := uint(2)
+	userID := uint(3)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `invitations` WHERE (expires_at > ? AND (single_use = ? OR used_at IS NULL)) AND (digest = ?) ORDER BY `invitations`.`id` ASC LIMIT 1 FOR UPDATE")).
+		WithArgs(utils.AnyTime{}, false, utils.DigestToken(token)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "board_id", "role", "single_use"}).
+				AddRow(invitationID, boardID, "viewer", true))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(boardID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `board_members` WHERE (board_id = ? AND user_id = ?)")).
+		WithArgs(boardID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id", "user_id"}))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `board_members`")).
+		WithArgs(boardID, userID, "viewer", utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `invitations` SET `used_at` = ? WHERE `invitations`.`id` = ?")).
+		WithArgs(utils.AnyTime{}, invitationID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	m, err := r.AcceptInvitation(token, userID)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, boardID, m.BoardID)
+	assert.Equal(t, entity.BoardRoleViewer, m.Role)
+}
+
+func TestShouldNotAcceptInvitationWhenUserIsAlreadyMember(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	boardID := uint(2)
+	userID := uint(3)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `invitations`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "board_id", "role", "single_use"}).
+				AddRow(uint(5), boardID, "editor", true))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `boards`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(boardID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `board_members`")).
+		WithArgs(boardID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id", "user_id", "role"}).AddRow(boardID, userID, "viewer"))
+
+	mock.ExpectRollback()
+
+	_, err := r.AcceptInvitation("sampletoken", userID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorAlreadyMember), err)
+}
+
+func TestShouldNotAcceptExpiredOrUsedInvitation(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `invitations`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectRollback()
+
+	_, err := r.AcceptInvitation("sampletoken", uint(3))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidToken), err)
+}
+
+func TestShouldDeleteInvitationOfBoard(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardMemberRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `invitations` WHERE (id = ? AND board_id = ?)")).
+		WithArgs(uint(5), uint(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	err := r.DeleteInvitation(uint(5), uint(2))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorRecordNotFound), err)
+}
@@ -1,8 +1,6 @@
 package repository
 
 import (
-	"log"
-
 	"github.com/jinzhu/gorm"
 
 	"local.packages/entity"
@@ -22,18 +20,18 @@ func NewLabelRepository(db *gorm.DB) *LabelRepository {
 }
 
 func selectLabelColumn(db *gorm.DB) *gorm.DB {
-	return db.Select("labels.id, labels.name, labels.color, labels.board_id")
+	return db.Select("labels.id, labels.name, labels.color, labels.board_id, labels.version")
 }
 
 func selectWithLabelAssociationKey(db *gorm.DB) *gorm.DB {
-	return db.Select("labels.id, labels.name, labels.color, labels.board_id, card_labels.card_id")
+	return db.Select("labels.id, labels.name, labels.color, labels.board_id, labels.version, card_labels.card_id")
 }
 
-// ValidateUID validates whether a boardID received as args was created by the login user.
+// ValidateUID validates whether the login user can edit a board of a boardID received as args.
 func (r *LabelRepository) ValidateUID(id, uid uint) []validator.ValidationError {
 	var b entity.Board
 
-	if r.db.Select("user_id").Where("user_id = ?", uid).First(&b, id).RecordNotFound() {
+	if r.db.Select("user_id").Scopes(memberOf(uid, editableRoles)).First(&b, id).RecordNotFound() {
 		return validator.NewValidationErrors(ErrorInvalidSession)
 	}
 
@@ -45,7 +43,7 @@ func (r *LabelRepository) Find(id, uid uint) (*entity.Label, []validator.Validat
 	var l entity.Label
 
 	rslt := r.db.Joins("Join boards on boards.id = labels.board_id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&l, id)
 
 	if rslt.RecordNotFound() {
@@ -72,19 +70,14 @@ func (r *LabelRepository) Create(name, color string, bid uint) (*entity.Label, [
 
 // Update update a record in a labels table.
 func (r *LabelRepository) Update(l *entity.Label, name, color string) []validator.ValidationError {
-	if err := r.db.Model(l).Updates(map[string]interface{}{"name": name, "color": color}).Error; err != nil {
-		return validator.FormattedValidationError(err)
-	}
-
-	return nil
+	return updateWithVersion(r.db, l, &l.Version, map[string]interface{}{"name": name, "color": color})
 }
 
 // Delete delete a record from a labels table.
-// use soft delete.
-func (r *LabelRepository) Delete(l *entity.Label) []validator.ValidationError {
-	if rslt := r.db.Delete(l); rslt.RowsAffected == 0 {
-		log.Printf("fail to delete label: %v", rslt.Error)
-		return validator.NewValidationErrors(ErrorInvalidRequest)
+// use soft delete. if a version is given, the label is deleted only if it still has the version.
+func (r *LabelRepository) Delete(l *entity.Label, version *uint) []validator.ValidationError {
+	if err := versionedDeletionErrors(r.db.Scopes(whereVersion(version)).Delete(l), "label", version); err != nil {
+		return err
 	}
 
 	return nil
@@ -96,7 +89,7 @@ func (r *LabelRepository) GetAll(bid, uid uint) *[]entity.Label {
 
 	r.db.Scopes(selectLabelColumn).
 		Joins("Join boards on boards.id = labels.board_id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, viewableRoles)).
 		Where("labels.board_id = ?", bid).
 		Find(&ls)
 
@@ -28,12 +28,12 @@ func TestShouldSuccessfullyValidateUIDOnLabelRepository(t *testing.T) {
 	query := utils.ReplaceQuotationForQuery(`
 		SELECT user_id
 		FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?) AND ('boards'.'id' = %d))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('boards'.'id' = %d))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, boardID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(boardID, userID))
 
 	if err := r.ValidateUID(boardID, userID); err != nil {
@@ -57,12 +57,12 @@ func TestShouldFailureValidateUIDOnLabelRepository(t *testing.T) {
 	query := utils.ReplaceQuotationForQuery(`
 		SELECT user_id
 		FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?) AND ('boards'.'id' = %d))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('boards'.'id' = %d))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, boardID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	err := r.ValidateUID(boardID, userID)
@@ -92,12 +92,12 @@ func TestShouldSuccessfullyFindLabel(t *testing.T) {
 		SELECT 'labels'.*
 		FROM 'labels'
 		Join boards on boards.id = labels.board_id
-		WHERE 'labels'.'deleted_at' IS NULL AND ((boards.user_id = ?) AND ('labels'.'id' = %d))
+		WHERE 'labels'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('labels'.'id' = %d))
 		ORDER BY 'labels'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, labelID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "board_id"}).AddRow(labelID, boardID))
 
 	l, err := r.Find(labelID, userID)
@@ -127,12 +127,12 @@ func TestShouldNotFindLabel(t *testing.T) {
 		SELECT 'labels'.*
 		FROM 'labels'
 		Join boards on boards.id = labels.board_id
-		WHERE 'labels'.'deleted_at' IS NULL AND ((boards.user_id = ?) AND ('labels'.'id' = %d))
+		WHERE 'labels'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('labels'.'id' = %d))
 		ORDER BY 'labels'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, labelID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.Find(labelID, userID)
@@ -186,12 +186,12 @@ func TestShouldSuccessfullyCreateLabel(t *testing.T) {
 			updatedAt := utils.AnyTime{}
 
 			query := utils.ReplaceQuotationForQuery(`
-				INSERT INTO 'labels' ('created_at','updated_at','deleted_at','name','color','board_id')
-				VALUES (?,?,?,?,?,?)`)
+				INSERT INTO 'labels' ('created_at','updated_at','deleted_at','name','color','board_id','version')
+				VALUES (?,?,?,?,?,?,?)`)
 
 			mock.ExpectBegin()
 			mock.ExpectExec(regexp.QuoteMeta(query)).
-				WithArgs(createdAt, updatedAt, nil, tc.labelName, tc.color, tc.boardID).
+				WithArgs(createdAt, updatedAt, nil, tc.labelName, tc.color, tc.boardID, 0).
 				WillReturnResult(sqlmock.NewResult(1, 1))
 
 			mock.ExpectCommit()
@@ -327,12 +327,12 @@ func TestShouldSuccessfullyUpdateLabel(t *testing.T) {
 
 			query := utils.ReplaceQuotationForQuery(`
 				UPDATE 'labels'
-				SET 'color' = ?, 'name' = ?, 'updated_at' = ?
-				WHERE 'labels'.'deleted_at' IS NULL AND 'labels'.'id' = ?`)
+				SET 'color' = ?, 'name' = ?, 'updated_at' = ?, 'version' = version + 1
+				WHERE 'labels'.'deleted_at' IS NULL AND 'labels'.'id' = ? AND ((version = ?))`)
 
 			mock.ExpectBegin()
 			mock.ExpectExec(regexp.QuoteMeta(query)).
-				WithArgs(tc.color, tc.labelName, updatedAt, l.ID).
+				WithArgs(tc.color, tc.labelName, updatedAt, l.ID, 0).
 				WillReturnResult(sqlmock.NewResult(1, 1))
 
 			mock.ExpectCommit()
@@ -449,7 +449,7 @@ func TestShouldSuccessfullyDeleteLabel(t *testing.T) {
 
 	mock.ExpectCommit()
 
-	if err := r.Delete(l); err != nil {
+	if err := r.Delete(l, nil); err != nil {
 		t.Errorf("was not expected an error. %v", err)
 	}
 
@@ -485,7 +485,7 @@ func TestShouldNotDeleteLabel(t *testing.T) {
 
 	mock.ExpectCommit()
 
-	err := r.Delete(l)
+	err := r.Delete(l, nil)
 
 	if err == nil {
 		t.Error("was expected an error, but did not recieved it.")
@@ -496,5 +496,5 @@ func TestShouldNotDeleteLabel(t *testing.T) {
 	}
 
 	assert.Nil(t, l.DeletedAt)
-	assert.Equal(t, err[0].Text, ErrorInvalidRequest)
+	assert.Equal(t, err[0].Text, ErrorRecordNotFound)
 }
@@ -26,14 +26,14 @@ func NewListRepository(db *gorm.DB) *ListRepository {
 }
 
 func selectListColumn(db *gorm.DB) *gorm.DB {
-	return db.Select("lists.id, lists.name, lists.board_id, lists.index")
+	return db.Select("lists.id, lists.name, lists.board_id, lists.index, lists.version")
 }
 
-// ValidateUID validates whether a boardID received as args was created by the login user.
+// ValidateUID validates whether the login user can edit a board of a boardID received as args.
 func (r *ListRepository) ValidateUID(id, uid uint) []validator.ValidationError {
 	var b entity.Board
 
-	if r.db.Select("user_id").Where("user_id = ?", uid).First(&b, id).RecordNotFound() {
+	if r.db.Select("user_id").Scopes(memberOf(uid, editableRoles)).First(&b, id).RecordNotFound() {
 		return validator.NewValidationErrors(ErrorInvalidSession)
 	}
 
@@ -45,7 +45,7 @@ func (r *ListRepository) Find(id, uid uint) (*entity.List, []validator.Validatio
 	var l entity.List
 
 	rslt := r.db.Joins("Join boards on boards.id = lists.board_id").
-		Where("boards.user_id = ?", uid).
+		Scopes(memberOf(uid, editableRoles)).
 		First(&l, id)
 
 	if rslt.RecordNotFound() {
@@ -77,18 +77,16 @@ func (r *ListRepository) Create(name string, bid uint) (*entity.List, []validato
 
 // Update update a record in a lists table.
 func (r *ListRepository) Update(l *entity.List, name string) []validator.ValidationError {
-	if err := r.db.Model(l).Updates(map[string]interface{}{"name": name}).Error; err != nil {
-		return validator.FormattedValidationError(err)
-	}
-
-	return nil
+	return updateWithVersion(r.db, l, &l.Version, map[string]interface{}{"name": name})
 }
 
 // UpdateIndex update List's order that recieved as args.
+// lists of boards that the login user can not edit are not updated.
+// a list whose index was changed is recorded as an activity.
 func (r *ListRepository) UpdateIndex(params []struct {
 	ID    uint
 	Index int
-}) []validator.ValidationError {
+}, uid uint) []validator.ValidationError {
 	ids := make([]string, 0, len(params))
 	values := make([]string, 0, len(params))
 
@@ -97,23 +95,80 @@ func (r *ListRepository) UpdateIndex(params []struct {
 		values = append(values, strconv.Itoa(p.Index))
 	}
 
+	var before []entity.List
+
+	r.db.Select("id, board_id, `index`").Where("id IN (?)", ids).Where("board_id IN ("+editableBoardIDs+")", uid, editableRoles).Find(&before)
+
 	joinedIDs := strings.Join(ids, ",")
 	joinedValues := strings.Join(values, ",")
-	q := fmt.Sprintf("UPDATE `lists` SET `index` = ELT(FIELD(id,%s),%s) WHERE id IN (%s)", joinedIDs, joinedValues, joinedIDs)
-
-	if err := r.db.Exec(q).Error; err != nil {
+	q := fmt.Sprintf(
+		"UPDATE `lists` SET `index` = ELT(FIELD(id,%s),%s) WHERE id IN (%s) AND board_id IN (%s)",
+		joinedIDs,
+		joinedValues,
+		joinedIDs,
+		editableBoardIDs)
+
+	if err := r.db.Exec(q, uid, editableRoles).Error; err != nil {
 		return validator.FormattedValidationError(err)
 	}
+
+	indexes := map[uint]int{}
+
+	for _, p := range params {
+		indexes[p.ID] = p.Index
+	}
+
+	for i := range before {
+		l := &before[i]
+
+		if l.Index != indexes[l.ID] {
+			r.RecordActivity(l, uid, entity.ActivityMove, entity.ActivityValues{"index": l.Index}, entity.ActivityValues{"index": indexes[l.ID]})
+		}
+	}
+
 	return nil
 }
 
-// Delete delete a record from a lists table.
-// use soft delete.
-func (r *ListRepository) Delete(l *entity.List) []validator.ValidationError {
-	if rslt := r.db.Model(l).UpdateColumns(map[string]interface{}{"deleted_at": time.Now(), "index": 0}); rslt.RowsAffected == 0 {
-		log.Printf("fail to delete list: %v", rslt.Error)
+// FindDeleted returns a record of List that was deleted, found by id.
+func (r *ListRepository) FindDeleted(id, uid uint) (*entity.List, []validator.ValidationError) {
+	var l entity.List
+
+	rslt := r.db.Unscoped().
+		Joins("Join boards on boards.id = lists.board_id").
+		Scopes(memberOf(uid, editableRoles)).
+		Where("lists.deleted_at IS NOT NULL AND boards.deleted_at IS NULL").
+		First(&l, id)
+
+	if rslt.RecordNotFound() {
+		return &l, validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return &l, nil
+}
+
+// Restore restores a deleted list to the end of its board with cards that were in it when it was deleted.
+func (r *ListRepository) Restore(l *entity.List) []validator.ValidationError {
+	index := 0
+	pl := &entity.List{}
+
+	if r.db.Select("`index`").Where("board_id = ?", l.BoardID).Order("`index` desc").Take(pl).RowsAffected > 0 {
+		index = pl.Index + 1
+	}
+
+	if rslt := r.db.Unscoped().Model(l).UpdateColumns(map[string]interface{}{"deleted_at": nil, "index": index}); rslt.RowsAffected == 0 {
+		log.Printf("fail to restore list: %v", rslt.Error)
 		return validator.NewValidationErrors(ErrorInvalidRequest)
 	}
 
 	return nil
 }
+
+// Delete delete a record from a lists table.
+// use soft delete. if a version is given, the list is deleted only if it still has the version.
+func (r *ListRepository) Delete(l *entity.List, version *uint) []validator.ValidationError {
+	if err := versionedDeletionErrors(r.db.Model(l).Scopes(whereVersion(version)).UpdateColumns(map[string]interface{}{"deleted_at": time.Now(), "index": 0}), "list", version); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -28,12 +28,12 @@ func TestShouldSuccessfullyValidateUIDOnListRepository(t *testing.T) {
 	query := utils.ReplaceQuotationForQuery(`
 		SELECT user_id
 		FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?) AND ('boards'.'id' = %d))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('boards'.'id' = %d))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, boardID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(boardID, userID))
 
 	if err := r.ValidateUID(boardID, userID); err != nil {
@@ -57,12 +57,12 @@ func TestShouldFailureValidateUIDOnListRepository(t *testing.T) {
 	query := utils.ReplaceQuotationForQuery(`
 		SELECT user_id
 		FROM 'boards'
-		WHERE 'boards'.'deleted_at' IS NULL AND ((user_id = ?) AND ('boards'.'id' = %d))
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('boards'.'id' = %d))
 		ORDER BY 'boards'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, boardID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	err := r.ValidateUID(boardID, userID)
@@ -92,12 +92,12 @@ func TestShouldSuccessfullyFindList(t *testing.T) {
 		SELECT 'lists'.*
 		FROM 'lists'
 		Join boards on boards.id = lists.board_id
-		WHERE 'lists'.'deleted_at' IS NULL AND ((boards.user_id = ?) AND ('lists'.'id' = %d))
+		WHERE 'lists'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('lists'.'id' = %d))
 		ORDER BY 'lists'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, listID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "board_id"}).AddRow(listID, boardID))
 
 	l, err := r.Find(listID, userID)
@@ -127,12 +127,12 @@ func TestShouldNotFindList(t *testing.T) {
 		SELECT 'lists'.*
 		FROM 'lists'
 		Join boards on boards.id = lists.board_id
-		WHERE 'lists'.'deleted_at' IS NULL AND ((boards.user_id = ?) AND ('lists'.'id' = %d))
+		WHERE 'lists'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))) AND ('lists'.'id' = %d))
 		ORDER BY 'lists'.'id' ASC
 		LIMIT 1`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, listID))).
-		WithArgs(userID).
+		WithArgs(userID, "owner", "editor").
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	_, err := r.Find(listID, userID)
@@ -168,8 +168,8 @@ func TestShouldSuccessfullyCreateList(t *testing.T) {
 		LIMIT 1`)
 
 	insertQuery := utils.ReplaceQuotationForQuery(`
-		INSERT INTO 'lists' ('created_at','updated_at','deleted_at','name','board_id','index')
-		VALUES (?,?,?,?,?,?)`)
+		INSERT INTO 'lists' ('created_at','updated_at','deleted_at','name','board_id','index','version')
+		VALUES (?,?,?,?,?,?,?)`)
 
 	mock.ExpectQuery(regexp.QuoteMeta(findQuery)).
 		WithArgs(boardID).
@@ -177,7 +177,7 @@ func TestShouldSuccessfullyCreateList(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
-		WithArgs(createdAt, updatedAt, nil, name, boardID, index+1).
+		WithArgs(createdAt, updatedAt, nil, name, boardID, index+1, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -263,12 +263,12 @@ func TestShouldSuccessfullyUpdateList(t *testing.T) {
 
 	query := utils.ReplaceQuotationForQuery(`
 		UPDATE 'lists'
-		SET 'name' = ?, 'updated_at' = ?
-		WHERE 'lists'.'deleted_at' IS NULL AND 'lists'.'id' = ?`)
+		SET 'name' = ?, 'updated_at' = ?, 'version' = version + 1
+		WHERE 'lists'.'deleted_at' IS NULL AND 'lists'.'id' = ? AND ((version = ?))`)
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta(query)).
-		WithArgs(name, updatedAt, l.ID).
+		WithArgs(name, updatedAt, l.ID, 0).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectCommit()
@@ -282,6 +282,7 @@ func TestShouldSuccessfullyUpdateList(t *testing.T) {
 	}
 
 	assert.Equal(t, l.Name, name)
+	assert.Equal(t, uint(1), l.Version)
 }
 
 func TestShouldNotUpdateList(t *testing.T) {
@@ -348,12 +349,35 @@ func TestShouldSuccessfullyUpdateListIndex(t *testing.T) {
 		{ID: 3, Index: 2},
 	}
 
-	query := "UPDATE `lists` SET `index` = ELT(FIELD(id,1,2,3),1,3,2) WHERE id IN (1,2,3)"
+	query := "UPDATE `lists` SET `index` = ELT(FIELD(id,1,2,3),1,3,2) WHERE id IN (1,2,3) AND board_id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?))"
+
+	uid := uint(1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, board_id, `index` FROM `lists`")).
+		WithArgs("1", "2", "3", uid, "owner", "editor").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "board_id", "index"}).AddRow(uint(1), uint(1), 1).AddRow(uint(2), uint(1), 2).AddRow(uint(3), uint(1), 3))
 
 	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(uid, "owner", "editor").
 		WillReturnResult(sqlmock.NewResult(1, 3))
 
-	if err := r.UpdateIndex(params); err != nil {
+	// a list that keeps its index is not recorded as moved.
+	for _, a := range []struct {
+		ID            uint
+		Before, After string
+	}{
+		{ID: 2, Before: `{"index":2}`, After: `{"index":3}`},
+		{ID: 3, Before: `{"index":3}`, After: `{"index":2}`},
+	} {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activities`")).
+			WithArgs(utils.AnyTime{}, uint(1), nil, uid, "move", "list", a.ID, a.Before, a.After).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+	}
+
+	if err := r.UpdateIndex(params, uid); err != nil {
 		t.Errorf("was not expected an error. %v", err)
 	}
 
@@ -388,7 +412,7 @@ func TestShouldSuccessfullyDeleteList(t *testing.T) {
 
 	mock.ExpectCommit()
 
-	if err := r.Delete(l); err != nil {
+	if err := r.Delete(l, nil); err != nil {
 		t.Errorf("was not expected an error. %v", err)
 	}
 
@@ -425,7 +449,7 @@ func TestShouldNotDeleteList(t *testing.T) {
 
 	mock.ExpectCommit()
 
-	err := r.Delete(l)
+	err := r.Delete(l, nil)
 
 	if err == nil {
 		t.Error("was expected an error, but did not recieved it.")
@@ -435,5 +459,5 @@ func TestShouldNotDeleteList(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %v", err)
 	}
 
-	assert.Equal(t, err[0].Text, ErrorInvalidRequest)
+	assert.Equal(t, err[0].Text, ErrorRecordNotFound)
 }
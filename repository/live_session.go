@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"local.packages/entity"
+)
+
+// liveSessionCheckInterval is the time during which a session that was found for a signed access token is trusted without database access.
+// a session that was deleted by another process is noticed within this interval.
+const liveSessionCheckInterval = time.Second * 30
+
+type liveSession struct {
+	uid       uint
+	checkedAt time.Time
+}
+
+// liveSessions are sessions that were recently found for signed access tokens.
+// they live in this process like presences, and are forgotten when a session is deleted in this process.
+var liveSessions = struct {
+	sync.Mutex
+	sessions map[uint]liveSession
+}{
+	sessions: map[uint]liveSession{},
+}
+
+// IsSessionAlive check if a session of the user still exists.
+// signed access tokens are verified without database access, so that this check rejects a token of a session
+// that was signed out or revoked, or of a user who was disabled or deleted, before the token expires.
+func (r *UserRepository) IsSessionAlive(sid, uid uint) bool {
+	now := time.Now()
+
+	liveSessions.Lock()
+	ls, ok := liveSessions.sessions[sid]
+	liveSessions.Unlock()
+
+	if ok && ls.uid == uid && now.Sub(ls.checkedAt) < liveSessionCheckInterval {
+		return true
+	}
+
+	if r.db.Select("id").Where("user_id = ?", uid).First(&entity.Session{}, sid).RecordNotFound() {
+		forgetLiveSession(sid)
+		return false
+	}
+
+	liveSessions.Lock()
+	defer liveSessions.Unlock()
+
+	for id, ls := range liveSessions.sessions {
+		if now.Sub(ls.checkedAt) >= liveSessionCheckInterval {
+			delete(liveSessions.sessions, id)
+		}
+	}
+
+	liveSessions.sessions[sid] = liveSession{uid: uid, checkedAt: now}
+
+	return true
+}
+
+// forgetLiveSession forgets a session that was deleted, so that its signed access tokens are rejected at once.
+func forgetLiveSession(sid uint) {
+	liveSessions.Lock()
+	defer liveSessions.Unlock()
+
+	delete(liveSessions.sessions, sid)
+}
+
+// forgetLiveSessionsOf forgets all sessions of the user after some of them were deleted.
+// sessions that still exist are found again in database.
+func forgetLiveSessionsOf(uid uint) {
+	liveSessions.Lock()
+	defer liveSessions.Unlock()
+
+	for id, ls := range liveSessions.sessions {
+		if ls.uid == uid {
+			delete(liveSessions.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/utils"
+)
+
+func TestIsSessionAliveShouldLookUpSessionOnlyOnceWithinInterval(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	sessionID := uint(101)
+	userID := uint(1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM `sessions` WHERE (user_id = ?) AND (`sessions`.`id` = 101)")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(sessionID))
+
+	assert.True(t, r.IsSessionAlive(sessionID, userID))
+	assert.True(t, r.IsSessionAlive(sessionID, userID))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIsSessionAliveShouldReturnFalseAfterSignOut(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	sessionID := uint(102)
+	userID := uint(1)
+
+	query := "SELECT id FROM `sessions` WHERE (user_id = ?) AND (`sessions`.`id` = 102)"
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(sessionID))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions` WHERE (id = ? AND user_id = ?)")).
+		WithArgs(sessionID, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	assert.True(t, r.IsSessionAlive(sessionID, userID))
+
+	if err := r.SignOut(sessionID, userID); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	assert.False(t, r.IsSessionAlive(sessionID, userID))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
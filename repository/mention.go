@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// mentionPattern matches `@handle` in a text. a handle is a name of a user without spaces.
+// a handle does not end with `.` or `-`, so that punctuation after a mention is not a part of the handle.
+var mentionPattern = regexp.MustCompile(`@([\p{L}\p{N}_]+(?:[.\-]+[\p{L}\p{N}_]+)*)`)
+
+// MentionHandle returns a handle to mention a user by the name.
+// characters that mentionPattern does not match are removed, and so are `.` and `-` at both ends, so that the handle is matched as a whole.
+// returns an empty string when no character of the name can be a part of a handle.
+func MentionHandle(name string) string {
+	h := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' || r == '.' || r == '-' {
+			return r
+		}
+
+		return -1
+	}, name)
+
+	return strings.Trim(h, ".-")
+}
+
+// mentionHandles returns handles mentioned in a text without duplicates.
+func mentionHandles(text string) []string {
+	var hs []string
+	seen := map[string]bool{}
+
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		h := strings.ToLower(m[1])
+
+		if !seen[h] {
+			seen[h] = true
+			hs = append(hs, h)
+		}
+	}
+
+	return hs
+}
+
+// saveMentions resolves handles in a text to members of the board of a card, and replaces mentions of the card description or the comment.
+// a handle that matches several members is ignored, and the actor is never mentioned.
+// users who are newly mentioned are notified, and returned as mentions with the mentioned users and the actor.
+func saveMentions(db *gorm.DB, cid uint, cmid *uint, actorID uint, text string) ([]entity.Mention, []validator.ValidationError) {
+	var created []entity.Mention
+
+	scope := func(db *gorm.DB) *gorm.DB {
+		if cmid == nil {
+			return db.Where("card_id = ? AND comment_id IS NULL", cid)
+		}
+
+		return db.Where("card_id = ? AND comment_id = ?", cid, *cmid)
+	}
+
+	hs := mentionHandles(text)
+
+	if len(hs) == 0 {
+		if err := db.Scopes(scope).Delete(&entity.Mention{}).Error; err != nil {
+			log.Printf("fail to delete mentions: %v", err)
+			return nil, validator.NewValidationErrors(ErrorInvalidRequest)
+		}
+
+		return nil, nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var bids []uint
+
+		if err := tx.Table("cards").Joins("Join lists ON lists.id = cards.list_id").Where("cards.id = ?", cid).Pluck("lists.board_id", &bids).Error; err != nil {
+			return err
+		}
+
+		if len(bids) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var us []entity.User
+
+		if err := tx.Select("users.id, users.name, users.email").
+			Joins("Join board_members ON board_members.user_id = users.id").
+			Where("board_members.board_id = ?", bids[0]).
+			Find(&us).Error; err != nil {
+			return err
+		}
+
+		uids := map[uint]*entity.User{}
+
+		for _, h := range hs {
+			var found *entity.User
+
+			for i := range us {
+				if strings.ToLower(MentionHandle(us[i].Name)) != h {
+					continue
+				}
+
+				if found != nil {
+					found = nil
+					break
+				}
+
+				found = &us[i]
+			}
+
+			if found != nil && found.ID != actorID {
+				uids[found.ID] = found
+			}
+		}
+
+		var existing []uint
+
+		if err := tx.Model(&entity.Mention{}).Scopes(scope).Pluck("user_id", &existing).Error; err != nil {
+			return err
+		}
+
+		var removed []uint
+
+		for _, uid := range existing {
+			if _, ok := uids[uid]; ok {
+				delete(uids, uid)
+			} else {
+				removed = append(removed, uid)
+			}
+		}
+
+		if len(removed) > 0 {
+			if err := tx.Scopes(scope).Where("user_id IN (?)", removed).Delete(&entity.Mention{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(uids) == 0 {
+			return nil
+		}
+
+		actor := &entity.User{}
+
+		if err := tx.Select("id, name").First(actor, actorID).Error; err != nil {
+			return err
+		}
+
+		for _, u := range uids {
+			m := entity.Mention{BoardID: bids[0], CardID: cid, CommentID: cmid, UserID: u.ID, ActorID: actorID}
+
+			if err := tx.Create(&m).Error; err != nil {
+				return err
+			}
+
+			m.User = u
+			m.Actor = actor
+			created = append(created, m)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("fail to save mentions: %v", err)
+		return nil, validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	uids := make([]uint, 0, len(created))
+
+	for _, m := range created {
+		uids = append(uids, m.UserID)
+	}
+
+	notifyCard(db, entity.NotificationMentioned, cid, actorID, uids)
+
+	return created, nil
+}
+
+// SaveMentions saves mentions in a description of a card.
+func (r *CardRepository) SaveMentions(c *entity.Card, uid uint) ([]entity.Mention, []validator.ValidationError) {
+	return saveMentions(r.db, c.ID, nil, uid, c.Description)
+}
+
+// SaveMentions saves mentions in a comment.
+func (r *CommentRepository) SaveMentions(cm *entity.Comment) ([]entity.Mention, []validator.ValidationError) {
+	return saveMentions(r.db, cm.CardID, &cm.ID, cm.UserID, cm.Body)
+}
+
+// escapeLike escapes `%`, `_` and the escape character in a string, so that LIKE matches the string as it is.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// SearchMembers returns members of a board whose name starts with a query, so that a client can complete mentions.
+// returns nothing unless the login user is a member of the board.
+func (r *BoardMemberRepository) SearchMembers(bid, uid uint, q string) *[]entity.User {
+	var us []entity.User
+
+	r.db.Select("users.id, users.name").
+		Joins("Join board_members ON board_members.user_id = users.id").
+		Joins("Join boards ON boards.id = board_members.board_id").
+		Scopes(memberOf(uid, viewableRoles)).
+		Where("board_members.board_id = ?", bid).
+		Where("users.name LIKE ?", escapeLike(q)+"%").
+		Order("users.name").
+		Limit(10).
+		Find(&us)
+
+	return &us
+}
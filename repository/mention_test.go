@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+)
+
+func TestShouldExtractMentionHandles(t *testing.T) {
+	hs := mentionHandles("@Gopher please review, cc @山田太郎 and @gopher again. mail@ is not a mention")
+
+	assert.Equal(t, []string{"gopher", "山田太郎"}, hs)
+	assert.Equal(t, "山田太郎", MentionHandle("山田 太郎"))
+}
+
+func TestShouldNotIncludeTrailingPunctuationInMentionHandles(t *testing.T) {
+	hs := mentionHandles("thanks @alice. and @bob-, ask @go.pher.jp or @mary-jane...")
+
+	assert.Equal(t, []string{"alice", "bob", "go.pher.jp", "mary-jane"}, hs)
+}
+
+func TestShouldMakeMentionHandlesThatCanBeMentioned(t *testing.T) {
+	names := map[string]string{
+		"Conan O'Brien": "ConanOBrien",
+		"ジョン・スミス":       "ジョンスミス",
+		"gopher-":       "gopher",
+		"-go.pher.":     "go.pher",
+		"☆☆☆":           "",
+	}
+
+	for name, handle := range names {
+		assert.Equal(t, handle, MentionHandle(name))
+
+		if handle != "" {
+			assert.Equal(t, []string{strings.ToLower(handle)}, mentionHandles("@"+handle+" hi"))
+		}
+	}
+}
+
+func TestShouldSearchMembersByNameWithWildcardsAsTheyAre(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewBoardMemberRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT users.id, users.name FROM `users`")).
+		WithArgs(uint(1), "owner", "editor", "viewer", uint(2), `100\%\_go%`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	r.SearchMembers(uint(2), uint(1), "100%_go")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldSaveMentionsOfMembersInComment(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewCommentRepository(db)
+
+	cm := &entity.Comment{ID: uint(7), CardID: uint(4), UserID: uint(1), Body: "@gopher @kanban-owner @gopher2 @nobody"}
+	boardID := uint(2)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT lists.board_id FROM `cards` Join lists ON lists.id = cards.list_id WHERE (cards.id = ?)")).
+		WithArgs(cm.CardID).
+		WillReturnRows(sqlmock.NewRows([]string{"board_id"}).AddRow(boardID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT users.id, users.name, users.email FROM `users` Join board_members ON board_members.user_id = users.id WHERE (board_members.board_id = ?)")).
+		WithArgs(boardID).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "email"}).
+				AddRow(uint(1), "kanban owner", "owner@sample.com").
+				AddRow(uint(3), "gopher", "gopher@sample.com").
+				AddRow(uint(5), "gopher2", "gopher2@sample.com").
+				AddRow(uint(6), "Gopher 2", "another@sample.com"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM `mentions` WHERE (card_id = ? AND comment_id = ?)")).
+		WithArgs(cm.CardID, cm.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(uint(8)))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `mentions` WHERE (card_id = ? AND comment_id = ?) AND (user_id IN (?))")).
+		WithArgs(cm.CardID, cm.ID, uint(8)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM `users` WHERE (`users`.`id` = 1)")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uint(1), "kanban owner"))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `mentions` (`created_at`,`board_id`,`card_id`,`comment_id`,`user_id`,`actor_id`) VALUES (?,?,?,?,?,?)")).
+		WithArgs(utils.AnyTime{}, boardID, cm.CardID, cm.ID, uint(3), cm.UserID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT cards.title, cards.list_id, lists.board_id FROM `cards`")).
+		WithArgs(cm.CardID).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "list_id", "board_id"}).AddRow("card", uint(9), boardID))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `notifications`")).
+		WithArgs(utils.AnyTime{}, uint(3), cm.UserID, "mentioned", boardID, uint(9), cm.CardID, "card", nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	ms, err := r.SaveMentions(cm)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	// the author and the ambiguous handle of two members are not mentioned.
+	assert.Equal(t, 1, len(ms))
+	assert.Equal(t, "gopher@sample.com", ms[0].User.Email)
+	assert.Equal(t, "kanban owner", ms[0].Actor.Name)
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// notificationsPerPage is the number of notifications in a page.
+const notificationsPerPage = 20
+
+// notifyUsers creates a copy of a notification for each user except for the actor.
+// notifications are secondary to the change that they tell, so that a failure is only logged.
+func notifyUsers(db *gorm.DB, n entity.Notification, uids []uint) {
+	seen := map[uint]bool{n.ActorID: true}
+
+	for _, uid := range uids {
+		if seen[uid] {
+			continue
+		}
+
+		seen[uid] = true
+
+		c := n
+		c.UserID = uid
+
+		if err := db.Create(&c).Error; err != nil {
+			log.Printf("fail to create notification: %v", err)
+		}
+	}
+}
+
+// notifyCard notifies users of a change to a card.
+func notifyCard(db *gorm.DB, kind string, cid, actorID uint, uids []uint) {
+	var rs []uint
+
+	for _, uid := range uids {
+		if uid != actorID {
+			rs = append(rs, uid)
+		}
+	}
+
+	if len(rs) == 0 {
+		return
+	}
+
+	var c struct {
+		Title   string
+		ListID  uint
+		BoardID uint
+	}
+
+	if err := db.Table("cards").
+		Select("cards.title, cards.list_id, lists.board_id").
+		Joins("Join lists ON lists.id = cards.list_id").
+		Where("cards.id = ?", cid).
+		Scan(&c).Error; err != nil {
+		log.Printf("fail to find card to notify: %v", err)
+		return
+	}
+
+	notifyUsers(db, entity.Notification{ActorID: actorID, Type: kind, BoardID: c.BoardID, ListID: &c.ListID, CardID: &cid, Title: c.Title}, rs)
+}
+
+// notifyCards notifies users of a change to each of cards.
+// users are given by card ids, so that the cards are found in one query.
+func notifyCards(db *gorm.DB, kind string, actorID uint, uids map[uint][]uint) {
+	var cids []uint
+
+	for cid, us := range uids {
+		for _, uid := range us {
+			if uid != actorID {
+				cids = append(cids, cid)
+				break
+			}
+		}
+	}
+
+	if len(cids) == 0 {
+		return
+	}
+
+	var cs []struct {
+		ID      uint
+		Title   string
+		ListID  uint
+		BoardID uint
+	}
+
+	if err := db.Table("cards").
+		Select("cards.id, cards.title, cards.list_id, lists.board_id").
+		Joins("Join lists ON lists.id = cards.list_id").
+		Where("cards.id IN (?)", cids).
+		Scan(&cs).Error; err != nil {
+		log.Printf("fail to find cards to notify: %v", err)
+		return
+	}
+
+	for i := range cs {
+		c := &cs[i]
+		notifyUsers(db, entity.Notification{ActorID: actorID, Type: kind, BoardID: c.BoardID, ListID: &c.ListID, CardID: &c.ID, Title: c.Title}, uids[c.ID])
+	}
+}
+
+// notifyList notifies members who watch a list or its board of a change to the list.
+func notifyList(db *gorm.DB, kind string, l *entity.List, actorID uint) {
+	notifyUsers(db, entity.Notification{ActorID: actorID, Type: kind, BoardID: l.BoardID, ListID: &l.ID, Title: l.Name}, listWatcherIDs(db, l.ID))
+}
+
+// notifyBoard notifies members who watch a board of a change to the board.
+// a board that was deleted is notified as well, so that it is found with soft deleted ones.
+func notifyBoard(db *gorm.DB, kind string, bid, actorID uint) {
+	uids := boardWatcherIDs(db, bid)
+
+	if len(uids) == 0 {
+		return
+	}
+
+	var b entity.Board
+
+	if err := db.Unscoped().Select("id, name").First(&b, bid).Error; err != nil {
+		log.Printf("fail to find board to notify: %v", err)
+		return
+	}
+
+	notifyUsers(db, entity.Notification{ActorID: actorID, Type: kind, BoardID: b.ID, Title: b.Name}, uids)
+}
+
+// NotificationRepository ...
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository is constructor for NotificationRepository.
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+// GetAll returns notifications of the login user in a page with their actors, newest first.
+// only unread notifications are returned when unread is true.
+// returns the number of all notifications that match as well.
+func (r *NotificationRepository) GetAll(uid uint, page int, unread bool) (*[]entity.Notification, int) {
+	var ns []entity.Notification
+	var total int
+
+	db := r.db.Model(&entity.Notification{}).Where("user_id = ?", uid)
+
+	if unread {
+		db = db.Where("read_at IS NULL")
+	}
+
+	db.Count(&total)
+
+	db.Preload("Actor", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, name")
+	}).
+		Order("id desc").
+		Limit(notificationsPerPage).
+		Offset((page - 1) * notificationsPerPage).
+		Find(&ns)
+
+	return &ns, total
+}
+
+// CountUnread returns the number of unread notifications of the login user.
+func (r *NotificationRepository) CountUnread(uid uint) int {
+	var n int
+
+	r.db.Model(&entity.Notification{}).Where("user_id = ? AND read_at IS NULL", uid).Count(&n)
+
+	return n
+}
+
+// Read marks a notification of the login user as read.
+// a notification that was read already is left as it is.
+func (r *NotificationRepository) Read(id, uid uint) (*entity.Notification, []validator.ValidationError) {
+	var n entity.Notification
+
+	if r.db.Where("user_id = ?", uid).First(&n, id).RecordNotFound() {
+		return &n, validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	if n.ReadAt != nil {
+		return &n, nil
+	}
+
+	if err := r.db.Model(&n).UpdateColumn("read_at", time.Now()).Error; err != nil {
+		log.Printf("fail to read notification: %v", err)
+		return &n, validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return &n, nil
+}
+
+// ReadAll marks all unread notifications of the login user as read.
+func (r *NotificationRepository) ReadAll(uid uint) []validator.ValidationError {
+	if err := r.db.Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", uid).
+		UpdateColumn("read_at", time.Now()).Error; err != nil {
+		log.Printf("fail to read notifications: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestShouldGetUnreadNotificationsOfPageWithTotal(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewNotificationRepository(db)
+
+	userID := uint(1)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `notifications` WHERE (user_id = ?) AND (read_at IS NULL)")).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `notifications` WHERE (user_id = ?) AND (read_at IS NULL) ORDER BY id desc LIMIT 20 OFFSET 0")).
+		WithArgs(userID).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "actor_id", "type", "card_id", "title"}).
+				AddRow(uint(2), userID, uint(3), "assigned", uint(4), "card"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM `users` WHERE (`id` IN (?))")).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(uint(3), "gopher"))
+
+	ns, total := r.GetAll(userID, 1, true)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "assigned", (*ns)[0].Type)
+	assert.Equal(t, "gopher", (*ns)[0].Actor.Name)
+}
+
+func TestShouldReadNotificationOfLoginUser(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewNotificationRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `notifications` WHERE (user_id = ?) AND (`notifications`.`id` = 2)")).
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(2), uint(1)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `notifications` SET `read_at` = ? WHERE `notifications`.`id` = ?")).
+		WithArgs(utils.AnyTime{}, uint(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	n, err := r.Read(uint(2), uint(1))
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.NotNil(t, n.ReadAt)
+}
+
+func TestShouldNotReadNotificationAgain(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewNotificationRepository(db)
+
+	readAt := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `notifications`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "read_at"}).AddRow(uint(2), uint(1), readAt))
+
+	n, err := r.Read(uint(2), uint(1))
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, readAt.Unix(), n.ReadAt.Unix())
+}
+
+func TestShouldNotReadNotificationOfAnotherUser(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewNotificationRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `notifications`")).
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := r.Read(uint(2), uint(1))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorRecordNotFound), err)
+}
+
+func TestShouldReadAllUnreadNotifications(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewNotificationRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `notifications` SET `read_at` = ? WHERE (user_id = ? AND read_at IS NULL)")).
+		WithArgs(utils.AnyTime{}, uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	mock.ExpectCommit()
+
+	if err := r.ReadAll(uint(1)); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"golang.org/x/crypto/bcrypt"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+const oidcAuthRequestLifetime = time.Minute * 10
+
+// CreateOIDCAuthRequest insert a new record to oidc_auth_requests table.
+// the nonce and the code verifier are kept until the callback of the authorization request.
+func (r *UserRepository) CreateOIDCAuthRequest(state, nonce, verifier string) []validator.ValidationError {
+	ar := &entity.OIDCAuthRequest{
+		StateDigest:  digestToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcAuthRequestLifetime),
+	}
+
+	if err := r.db.Create(ar).Error; err != nil {
+		log.Printf("fail to create oidc auth request: %v", err)
+		return validator.NewValidationErrors(ErrorAuthenticationFailed)
+	}
+
+	return nil
+}
+
+// UseOIDCAuthRequest returns an instance of OIDCAuthRequest that found by a state and delete it.
+// returns errors with message if the state is unknown, expired or already used.
+func (r *UserRepository) UseOIDCAuthRequest(state string) (*entity.OIDCAuthRequest, []validator.ValidationError) {
+	ar := &entity.OIDCAuthRequest{}
+
+	if r.db.Where("state_digest = ? AND expires_at > ?", digestToken(state), time.Now()).First(ar).RecordNotFound() {
+		return ar, validator.NewValidationErrors(ErrorInvalidToken)
+	}
+
+	// deleting before use prevents that a state is used twice by concurrent requests.
+	if rslt := r.db.Delete(ar); rslt.RowsAffected == 0 {
+		return ar, validator.NewValidationErrors(ErrorInvalidToken)
+	}
+
+	return ar, nil
+}
+
+// SignInWithIdentity returns an instance of User that linked to an account of an OpenID Provider.
+// if the account is not linked yet, it is linked to the user who has the same email address,
+// or a new user is created. linking requires that the provider has verified the email address.
+// a user who has not verified the email address is not linked, because the account may have been registered by someone else
+// who does not own the address and still knows its password.
+func (r *UserRepository) SignInWithIdentity(issuer, subject, email, name string, emailVerified bool) (*entity.User, []validator.ValidationError) {
+	u := &entity.User{}
+	var verr []validator.ValidationError
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var id entity.Identity
+
+		if !tx.Where("issuer = ? AND subject = ?", issuer, subject).First(&id).RecordNotFound() {
+			if tx.First(u, id.UserID).RecordNotFound() {
+				verr = validator.NewValidationErrors(ErrorUserDoesNotExist)
+				return gorm.ErrRecordNotFound
+			}
+
+			return nil
+		}
+
+		if email == "" || !emailVerified {
+			verr = validator.NewValidationErrors(ErrorIdentityEmailNotVerified)
+			return gorm.ErrRecordNotFound
+		}
+
+		if tx.Where("email = ?", email).First(u).RecordNotFound() {
+			if err := createIdentityUser(tx, u, email, name); err != nil {
+				return err
+			}
+		} else if r.IsTestUserID(u.ID) {
+			// test users are shared by anyone, so that they must not be linked to an external account.
+			verr = validator.NewValidationErrors(ErrorInvalidRequest)
+			return gorm.ErrRecordNotFound
+		} else if u.VerifiedAt == nil {
+			verr = validator.NewValidationErrors(ErrorIdentityAccountNotVerified)
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(&entity.Identity{
+			UserID:  u.ID,
+			Issuer:  issuer,
+			Subject: subject,
+		}).Error
+	})
+
+	if verr != nil {
+		return u, verr
+	}
+
+	if err != nil {
+		log.Printf("fail to sign in with identity: %v", err)
+		return u, validator.NewValidationErrors(ErrorAuthenticationFailed)
+	}
+
+	return u, nil
+}
+
+// createIdentityUser insert a new user who signs in only via an OpenID Provider.
+// a random password is set, so that the user can sign in with a password after resetting it.
+func createIdentityUser(tx *gorm.DB, u *entity.User, email, name string) error {
+	p, err := newSessionToken()
+
+	if err != nil {
+		return err
+	}
+
+	h, err := bcrypt.GenerateFromPassword([]byte(p), bcrypt.DefaultCost)
+
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = strings.SplitN(email, "@", 2)[0]
+	}
+
+	now := time.Now()
+
+	u.Name = name
+	u.Email = email
+	u.PasswordDigest = string(h)
+	u.VerifiedAt = &now
+
+	return tx.Create(u).Error
+}
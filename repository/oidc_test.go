@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestShouldSuccessfullyUseOIDCAuthRequest(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	state := "samplestate"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `oidc_auth_requests`")).
+		WithArgs(digestToken(state), utils.AnyTime{}).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "nonce", "code_verifier"}).
+				AddRow(uint(1), "samplenonce", "sampleverifier"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `oidc_auth_requests` WHERE `oidc_auth_requests`.`id` = ?")).
+		WithArgs(uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	ar, err := r.UseOIDCAuthRequest(state)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, "samplenonce", ar.Nonce)
+	assert.Equal(t, "sampleverifier", ar.CodeVerifier)
+}
+
+func TestShouldFailureUseOIDCAuthRequestWhenStateIsUnknown(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `oidc_auth_requests`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := r.UseOIDCAuthRequest("unknown")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidToken), err)
+}
+
+func TestShouldSignInWithLinkedIdentity(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	issuer := "https://idp.example.com"
+	subject := "subject"
+	userID := uint(9)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `identities` WHERE (issuer = ? AND subject = ?)")).
+		WithArgs(issuer, subject).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(1), userID))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(userID, "gopher@sample.com"))
+
+	mock.ExpectCommit()
+
+	u, err := r.SignInWithIdentity(issuer, subject, "changed@sample.com", "gopher", false)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, userID, u.ID)
+	assert.Equal(t, "gopher@sample.com", u.Email)
+}
+
+func TestShouldLinkIdentityToUserWhoHasSameEmail(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	issuer := "https://idp.example.com"
+	subject := "subject"
+	email := "gopher@sample.com"
+	userID := uint(9)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `identities`")).
+		WithArgs(issuer, subject).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE (email = ?)")).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "verified_at"}).AddRow(userID, email, time.Now()))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `identities` (`created_at`,`user_id`,`issuer`,`subject`)")).
+		WithArgs(utils.AnyTime{}, userID, issuer, subject).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	u, err := r.SignInWithIdentity(issuer, subject, email, "gopher", true)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, userID, u.ID)
+	assert.NotNil(t, u.VerifiedAt)
+}
+
+func TestShouldNotLinkIdentityToUserWhoHasNotVerifiedEmail(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	email := "gopher@sample.com"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `identities`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users` WHERE (email = ?)")).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "verified_at"}).AddRow(uint(9), email, nil))
+
+	mock.ExpectRollback()
+
+	_, err := r.SignInWithIdentity("https://idp.example.com", "subject", email, "gopher", true)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorIdentityAccountNotVerified), err)
+}
+
+func TestShouldCreateUserWithIdentity(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	issuer := "https://idp.example.com"
+	subject := "subject"
+	email := "gopher@sample.com"
+	userID := uint(9)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `identities`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `users`")).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, "gopher", email, sqlmock.AnyArg(), utils.AnyTime{}, "", nil, nil).
+		WillReturnResult(sqlmock.NewResult(int64(userID), 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `identities`")).
+		WithArgs(utils.AnyTime{}, userID, issuer, subject).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	u, err := r.SignInWithIdentity(issuer, subject, email, "", true)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, userID, u.ID)
+	assert.Equal(t, "gopher", u.Name)
+	assert.NotNil(t, u.VerifiedAt)
+}
+
+func TestShouldNotLinkIdentityWhenEmailIsNotVerified(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `identities`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectRollback()
+
+	_, err := r.SignInWithIdentity("https://idp.example.com", "subject", "gopher@sample.com", "gopher", false)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorIdentityEmailNotVerified), err)
+}
+
+func TestShouldNotLinkIdentityToTestUser(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	email := "tester@sample.com"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `identities`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(testUserIDs()[0], email))
+
+	mock.ExpectRollback()
+
+	_, err := r.SignInWithIdentity("https://idp.example.com", "subject", email, "tester", true)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidRequest), err)
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// createOneTimeToken insert a new record to one_time_tokens table and returns the raw token.
+// unused tokens of the same purpose that were issued before are discarded.
+func createOneTimeToken(db *gorm.DB, uid uint, purpose string, lifetime time.Duration) (string, error) {
+	t, err := newSessionToken()
+
+	if err != nil {
+		return "", err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", uid, purpose).Delete(&entity.OneTimeToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&entity.OneTimeToken{
+			UserID:    uid,
+			Purpose:   purpose,
+			Digest:    digestToken(t),
+			ExpiresAt: time.Now().Add(lifetime),
+		}).Error
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return t, nil
+}
+
+// useOneTimeToken marks a token as used and returns the record.
+// returns an error if the token is unknown, expired or already used.
+func useOneTimeToken(db *gorm.DB, token, purpose string) (*entity.OneTimeToken, []validator.ValidationError) {
+	var ot entity.OneTimeToken
+
+	if db.Where("digest = ? AND purpose = ?", digestToken(token), purpose).
+		Where("used_at IS NULL AND expires_at > ?", time.Now()).
+		First(&ot).
+		RecordNotFound() {
+		return &ot, validator.NewValidationErrors(ErrorInvalidToken)
+	}
+
+	// conditional update prevents that a token is used twice by concurrent requests.
+	if rslt := db.Model(&ot).Where("used_at IS NULL").UpdateColumn("used_at", time.Now()); rslt.RowsAffected == 0 {
+		return &ot, validator.NewValidationErrors(ErrorInvalidToken)
+	}
+
+	return &ot, nil
+}
+
+// failOneTimeToken counts a wrong attempt to use a token, and consumes the token when the attempts reach maxFailures.
+// it is called outside of the transaction that was rolled back by the wrong attempt, so that the count is kept.
+func failOneTimeToken(db *gorm.DB, token, purpose string, maxFailures int) {
+	if err := db.Model(&entity.OneTimeToken{}).
+		Where("digest = ? AND purpose = ? AND used_at IS NULL", digestToken(token), purpose).
+		UpdateColumn("failures", gorm.Expr("failures + 1")).Error; err != nil {
+		log.Printf("fail to count failure of one time token: %v", err)
+		return
+	}
+
+	if err := db.Model(&entity.OneTimeToken{}).
+		Where("digest = ? AND purpose = ? AND used_at IS NULL AND failures >= ?", digestToken(token), purpose, maxFailures).
+		UpdateColumn("used_at", time.Now()).Error; err != nil {
+		log.Printf("fail to consume one time token: %v", err)
+	}
+}
+
+// digestToken returns a SHA-256 digest of a token.
+// tokens have enough entropy, so that a fast hash is sufficient.
+func digestToken(t string) string {
+	d := sha256.Sum256([]byte(t))
+	return hex.EncodeToString(d[:])
+}
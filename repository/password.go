@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+const passwordResetLifetime = time.Hour * 1
+
+// FindByEmail returns a record of User that found by email.
+func (r *UserRepository) FindByEmail(email string) (*entity.User, []validator.ValidationError) {
+	u := &entity.User{}
+
+	if r.db.Where("email = ?", email).First(u).RecordNotFound() {
+		return u, validator.NewValidationErrors(ErrorUserDoesNotExist)
+	}
+
+	return u, nil
+}
+
+// CreatePasswordResetToken returns a new token to reset a password of the user.
+func (r *UserRepository) CreatePasswordResetToken(uid uint) (string, []validator.ValidationError) {
+	t, err := createOneTimeToken(r.db, uid, entity.PurposePasswordReset, passwordResetLifetime)
+
+	if err != nil {
+		log.Printf("fail to create password reset token: %v", err)
+		return "", validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return t, nil
+}
+
+// ResetPassword updates a password of the user who owns the token.
+// all sessions of the user are deleted, so that the user has to sign in again on every device.
+// a lockout of sign in by the email address is also released.
+func (r *UserRepository) ResetPassword(token, passwordDigest string) []validator.ValidationError {
+	var verr []validator.ValidationError
+	var uid uint
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		ot, err := useOneTimeToken(tx, token, entity.PurposePasswordReset)
+
+		if err != nil {
+			verr = err
+			return gorm.ErrRecordNotFound
+		}
+
+		var u entity.User
+
+		if err := tx.Select("id, email").First(&u, ot.UserID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&u).UpdateColumn("password_digest", passwordDigest).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", u.ID).Delete(&entity.Session{}).Error; err != nil {
+			return err
+		}
+
+		uid = u.ID
+
+		// the owner of the email address proved the identity, so that a lockout of sign in is released.
+		return clearSignInFailures(tx, u.Email)
+	})
+
+	if verr != nil {
+		return verr
+	}
+
+	if err != nil {
+		log.Printf("fail to reset password: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	forgetLiveSessionsOf(uid)
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+)
+
+func TestShouldSuccessfullyFindByEmail(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	email := "gopher@sample.com"
+
+	query := "SELECT * FROM `users` WHERE (email = ?) ORDER BY `users`.`id` ASC LIMIT 1"
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(uint(1), email))
+
+	u, err := r.FindByEmail(email)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, u.ID, uint(1))
+	assert.Equal(t, u.Email, email)
+}
+
+func TestShouldSuccessfullyCreatePasswordResetToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+
+	deleteQuery := utils.ReplaceQuotationForQuery(`
+		DELETE FROM 'one_time_tokens'
+		WHERE (user_id = ? AND purpose = ? AND used_at IS NULL)`)
+
+	insertQuery := utils.ReplaceQuotationForQuery(`
+		INSERT INTO 'one_time_tokens' ('created_at','user_id','purpose','digest','expires_at','used_at','failures')
+		VALUES (?,?,?,?,?,?,?)`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+		WithArgs(userID, entity.PurposePasswordReset).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta(insertQuery)).
+		WithArgs(utils.AnyTime{}, userID, entity.PurposePasswordReset, sqlmock.AnyArg(), utils.AnyTime{}, nil, 0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	token, err := r.CreatePasswordResetToken(userID)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.NotEmpty(t, token)
+}
+
+func TestShouldFailureCreatePasswordResetToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `one_time_tokens`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `one_time_tokens`")).
+		WillReturnError(errors.New("some error"))
+
+	mock.ExpectRollback()
+
+	token, err := r.CreatePasswordResetToken(uint(1))
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Empty(t, token)
+	assert.Equal(t, err[0].Text, ErrorInvalidRequest)
+}
+
+func TestShouldSuccessfullyResetPassword(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	token := "sampletoken"
+	passwordDigest := "digest"
+	tokenID := uint(3)
+	userID := uint(1)
+
+	findQuery := utils.ReplaceQuotationForQuery(`
+		SELECT * FROM 'one_time_tokens'
+		WHERE (digest = ? AND purpose = ?) AND (used_at IS NULL AND expires_at > ?)
+		ORDER BY 'one_time_tokens'.'id' ASC
+		LIMIT 1`)
+
+	useQuery := utils.ReplaceQuotationForQuery(`
+		UPDATE 'one_time_tokens'
+		SET 'used_at' = ?
+		WHERE 'one_time_tokens'.'id' = ? AND ((used_at IS NULL))`)
+
+	email := "gopher@sample.com"
+
+	findUserQuery := utils.ReplaceQuotationForQuery(`
+		SELECT id, email FROM 'users'
+		WHERE ('users'.'id' = 1)
+		ORDER BY 'users'.'id' ASC
+		LIMIT 1`)
+
+	updateQuery := "UPDATE `users` SET `password_digest` = ? WHERE `users`.`id` = ?"
+	deleteQuery := "DELETE FROM `sessions` WHERE (user_id = ?)"
+	clearQuery := "DELETE FROM `sign_in_throttles` WHERE (identifier = ?)"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(findQuery)).
+		WithArgs(digestToken(token), entity.PurposePasswordReset, utils.AnyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(tokenID, userID))
+
+	mock.ExpectExec(regexp.QuoteMeta(useQuery)).
+		WithArgs(utils.AnyTime{}, tokenID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta(findUserQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(userID, email))
+
+	mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+		WithArgs(passwordDigest, userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectExec(regexp.QuoteMeta(clearQuery)).
+		WithArgs("email:" + email).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.ResetPassword(token, passwordDigest); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldFailureResetPasswordWhenTokenIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `one_time_tokens`")).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	mock.ExpectRollback()
+
+	err := r.ResetPassword("sampletoken", "digest")
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorInvalidToken)
+}
+
+func TestShouldFailureResetPasswordWhenTokenWasUsedConcurrently(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `one_time_tokens`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(3), uint(1)))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `one_time_tokens`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectRollback()
+
+	err := r.ResetPassword("sampletoken", "digest")
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorInvalidToken)
+}
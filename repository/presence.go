@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// presenceTimeout is the time after which a user who is seen without a stream of events leaves a board.
+const presenceTimeout = time.Minute
+
+// presenceEventBuffer is the number of presence events that wait to be sent to a subscriber.
+const presenceEventBuffer = 32
+
+type presenceEntry struct {
+	presence entity.Presence
+	// streams is the number of streams of events that the user has open on the board.
+	// a user with open streams does not time out, and leaves when the last stream is closed.
+	streams int
+}
+
+// presences are users who are looking at each board, and channels of subscribers to their events.
+// they live in this process like activitySubscriptions.
+var presences = struct {
+	sync.Mutex
+	boards   map[uint]map[uint]*presenceEntry
+	channels map[uint]map[chan entity.PresenceEvent]bool
+}{
+	boards:   map[uint]map[uint]*presenceEntry{},
+	channels: map[uint]map[chan entity.PresenceEvent]bool{},
+}
+
+// publishPresence sends a presence event to subscribers of its board without waiting for them.
+// a subscriber that falls behind is closed, and gets a snapshot of presences again when it subscribes again.
+// presences must be locked by the caller.
+func publishPresence(e entity.PresenceEvent) {
+	for ch := range presences.channels[e.BoardID] {
+		select {
+		case ch <- e:
+		default:
+			delete(presences.channels[e.BoardID], ch)
+			close(ch)
+		}
+	}
+}
+
+// sweepPresences removes users who have timed out from a board.
+// presences must be locked by the caller.
+func sweepPresences(bid uint) {
+	deadline := time.Now().Add(-presenceTimeout)
+
+	for uid, e := range presences.boards[bid] {
+		if e.streams == 0 && e.presence.SeenAt.Before(deadline) {
+			delete(presences.boards[bid], uid)
+			publishPresence(entity.PresenceEvent{Type: entity.PresenceLeave, BoardID: bid, Presence: e.presence})
+		}
+	}
+
+	if len(presences.boards[bid]) == 0 {
+		delete(presences.boards, bid)
+	}
+}
+
+// seePresence marks a user as seen on a board, and reports whether the user has joined the board.
+// presences must be locked by the caller.
+func seePresence(bid, uid uint, name string) (*presenceEntry, bool) {
+	sweepPresences(bid)
+
+	if presences.boards[bid] == nil {
+		presences.boards[bid] = map[uint]*presenceEntry{}
+	}
+
+	e, ok := presences.boards[bid][uid]
+
+	if !ok {
+		e = &presenceEntry{presence: entity.Presence{UserID: uid, Name: name}}
+		presences.boards[bid][uid] = e
+	}
+
+	e.presence.SeenAt = time.Now()
+
+	return e, !ok
+}
+
+// PresenceRepository ...
+type PresenceRepository struct {
+	db *gorm.DB
+}
+
+// NewPresenceRepository is constructor for PresenceRepository.
+func NewPresenceRepository(db *gorm.DB) *PresenceRepository {
+	return &PresenceRepository{
+		db: db,
+	}
+}
+
+// ValidateUID validates whether the login user is a member of a board of a boardID received as args.
+func (r *PresenceRepository) ValidateUID(bid, uid uint) []validator.ValidationError {
+	var b entity.Board
+
+	if r.db.Select("id").Scopes(memberOf(uid, viewableRoles)).First(&b, bid).RecordNotFound() {
+		return validator.NewValidationErrors(ErrorInvalidSession)
+	}
+
+	return nil
+}
+
+// ValidateCard validates whether a card of a cardID received as args is on a board.
+func (r *PresenceRepository) ValidateCard(cid, bid uint) []validator.ValidationError {
+	var n int
+
+	r.db.Model(&entity.Card{}).
+		Joins("Join lists ON lists.id = cards.list_id").
+		Where("cards.id = ? AND lists.board_id = ? AND lists.deleted_at IS NULL", cid, bid).
+		Count(&n)
+
+	if n == 0 {
+		return validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return nil
+}
+
+func (r *PresenceRepository) nameOf(uid uint) string {
+	var names []string
+
+	r.db.Model(&entity.User{}).Where("id = ?", uid).Pluck("name", &names)
+
+	if len(names) == 0 {
+		return ""
+	}
+
+	return names[0]
+}
+
+// GetAll returns users who are looking at a board, ordered by user id.
+func (r *PresenceRepository) GetAll(bid uint) []entity.Presence {
+	presences.Lock()
+	defer presences.Unlock()
+
+	sweepPresences(bid)
+
+	ps := make([]entity.Presence, 0, len(presences.boards[bid]))
+
+	for _, e := range presences.boards[bid] {
+		ps = append(ps, e.presence)
+	}
+
+	sort.Slice(ps, func(i, j int) bool { return ps[i].UserID < ps[j].UserID })
+
+	return ps
+}
+
+// See marks the login user as looking at a board with a card open, or with no card open when cid is nil.
+// the user leaves the board after presenceTimeout unless the user is seen again or has a stream of events open.
+func (r *PresenceRepository) See(bid, uid uint, cid *uint) entity.Presence {
+	name := r.nameOf(uid)
+
+	presences.Lock()
+	defer presences.Unlock()
+
+	e, joined := seePresence(bid, uid, name)
+	changed := (e.presence.CardID == nil) != (cid == nil) || (cid != nil && *e.presence.CardID != *cid)
+	e.presence.CardID = cid
+
+	switch {
+	case joined:
+		publishPresence(entity.PresenceEvent{Type: entity.PresenceJoin, BoardID: bid, Presence: e.presence})
+	case changed:
+		publishPresence(entity.PresenceEvent{Type: entity.PresenceUpdate, BoardID: bid, Presence: e.presence})
+	}
+
+	return e.presence
+}
+
+// Enter marks the login user as looking at a board while a stream of events is open.
+func (r *PresenceRepository) Enter(bid, uid uint) {
+	name := r.nameOf(uid)
+
+	presences.Lock()
+	defer presences.Unlock()
+
+	e, joined := seePresence(bid, uid, name)
+	e.streams++
+
+	if joined {
+		publishPresence(entity.PresenceEvent{Type: entity.PresenceJoin, BoardID: bid, Presence: e.presence})
+	}
+}
+
+// Exit is called when a stream of events of the login user is closed.
+// the user leaves the board when no other stream is open.
+func (r *PresenceRepository) Exit(bid, uid uint) {
+	presences.Lock()
+	defer presences.Unlock()
+
+	e, ok := presences.boards[bid][uid]
+
+	if !ok {
+		return
+	}
+
+	e.streams--
+	e.presence.SeenAt = time.Now()
+
+	if e.streams > 0 {
+		return
+	}
+
+	delete(presences.boards[bid], uid)
+	publishPresence(entity.PresenceEvent{Type: entity.PresenceLeave, BoardID: bid, Presence: e.presence})
+}
+
+// Sweep removes users who have timed out from a board, and publishes their leave events.
+func (r *PresenceRepository) Sweep(bid uint) {
+	presences.Lock()
+	defer presences.Unlock()
+
+	sweepPresences(bid)
+}
+
+// Subscribe returns a channel that receives presence events of a board.
+// the returned function stops the subscription, and has to be called when the subscriber goes away.
+func (r *PresenceRepository) Subscribe(bid uint) (<-chan entity.PresenceEvent, func()) {
+	ch := make(chan entity.PresenceEvent, presenceEventBuffer)
+
+	presences.Lock()
+	defer presences.Unlock()
+
+	if presences.channels[bid] == nil {
+		presences.channels[bid] = map[chan entity.PresenceEvent]bool{}
+	}
+
+	presences.channels[bid][ch] = true
+
+	return ch, func() {
+		presences.Lock()
+		defer presences.Unlock()
+
+		if presences.channels[bid][ch] {
+			delete(presences.channels[bid], ch)
+			close(ch)
+		}
+
+		if len(presences.channels[bid]) == 0 {
+			delete(presences.channels, bid)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+)
+
+func TestShouldPublishJoinAndUpdateOfPresence(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewPresenceRepository(db)
+
+	boardID := uint(101)
+	userID := uint(1)
+	cardID := uint(5)
+
+	ch, unsubscribe := r.Subscribe(boardID)
+	defer unsubscribe()
+
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT name FROM `users` WHERE (id = ?)")).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("gopher"))
+	}
+
+	r.See(boardID, userID, nil)
+	p := r.See(boardID, userID, &cardID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	join := <-ch
+	update := <-ch
+
+	assert.Equal(t, entity.PresenceJoin, join.Type)
+	assert.Equal(t, "gopher", join.Presence.Name)
+	assert.Nil(t, join.Presence.CardID)
+	assert.Equal(t, entity.PresenceUpdate, update.Type)
+	assert.Equal(t, cardID, *update.Presence.CardID)
+	assert.Equal(t, []entity.Presence{p}, r.GetAll(boardID))
+}
+
+func TestShouldLeaveBoardWhenPresenceHasTimedOut(t *testing.T) {
+	db, _ := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewPresenceRepository(db)
+
+	boardID := uint(102)
+
+	r.See(boardID, uint(1), nil)
+	r.See(boardID, uint(2), nil)
+
+	ch, unsubscribe := r.Subscribe(boardID)
+	defer unsubscribe()
+
+	presences.Lock()
+	presences.boards[boardID][uint(1)].presence.SeenAt = time.Now().Add(-presenceTimeout - time.Second)
+	presences.Unlock()
+
+	r.Sweep(boardID)
+
+	e := <-ch
+
+	assert.Equal(t, entity.PresenceLeave, e.Type)
+	assert.Equal(t, uint(1), e.Presence.UserID)
+	assert.Len(t, r.GetAll(boardID), 1)
+}
+
+func TestShouldLeaveBoardWhenLastStreamIsClosed(t *testing.T) {
+	db, _ := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewPresenceRepository(db)
+
+	boardID := uint(103)
+	userID := uint(1)
+
+	r.Enter(boardID, userID)
+	r.Enter(boardID, userID)
+
+	// a user who has a stream of events open does not time out.
+	presences.Lock()
+	presences.boards[boardID][userID].presence.SeenAt = time.Now().Add(-presenceTimeout - time.Second)
+	presences.Unlock()
+
+	r.Sweep(boardID)
+	r.Exit(boardID, userID)
+
+	assert.Len(t, r.GetAll(boardID), 1)
+
+	ch, unsubscribe := r.Subscribe(boardID)
+	defer unsubscribe()
+
+	r.Exit(boardID, userID)
+
+	e := <-ch
+
+	assert.Equal(t, entity.PresenceLeave, e.Type)
+	assert.Len(t, r.GetAll(boardID), 0)
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+const (
+	sessionLifetime = time.Hour * 1
+	// lastSeenInterval is the minimum interval between updates of Session.LastSeenAt.
+	lastSeenInterval = time.Minute * 1
+)
+
+// CreateSession insert a new record to sessions table for a signed in device.
+func (r *UserRepository) CreateSession(uid uint, device, userAgent, ip string) (*entity.Session, []validator.ValidationError) {
+	s := &entity.Session{
+		UserID:     uid,
+		Device:     device,
+		UserAgent:  userAgent,
+		IPAddress:  ip,
+		LastSeenAt: time.Now(),
+	}
+
+	if err := setSessionToken(s); err != nil {
+		log.Printf("fail to create session token: %v", err)
+		return s, validator.NewValidationErrors(ErrorAuthenticationFailed)
+	}
+
+	if err := r.db.Create(s).Error; err != nil {
+		log.Printf("fail to create session: %v", err)
+		return s, validator.NewValidationErrors(ErrorAuthenticationFailed)
+	}
+
+	return s, nil
+}
+
+// UpdateSession rotates access token and refresh token of a session and extends expires.
+// the digest of the previous refresh token is kept to detect its reuse.
+// returns errors if the session was rotated by another request at the same time.
+func (r *UserRepository) UpdateSession(s *entity.Session) []validator.ValidationError {
+	previous := s.RefreshDigest
+
+	if err := setSessionToken(s); err != nil {
+		log.Printf("fail to create session token: %v", err)
+		return validator.NewValidationErrors(ErrorAuthenticationFailed)
+	}
+
+	s.LastSeenAt = time.Now()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&entity.RotatedRefreshToken{SessionID: s.ID, Digest: previous}).Error; err != nil {
+			return err
+		}
+
+		// conditional update prevents that a refresh token is rotated twice by concurrent requests.
+		rslt := tx.Model(s).Where("refresh_token = ?", previous).Updates(map[string]interface{}{
+			"remember_token": s.RememberDigest,
+			"refresh_token":  s.RefreshDigest,
+			"expires_at":     s.ExpiresAt,
+			"last_seen_at":   s.LastSeenAt,
+		})
+
+		if rslt.Error != nil {
+			return rslt.Error
+		}
+
+		if rslt.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("fail to update session: %v", err)
+		return validator.NewValidationErrors(ErrorAuthenticationFailed)
+	}
+
+	return nil
+}
+
+// RevokeReusedRefreshToken deletes a session if a refresh token was already rotated.
+// reuse of a rotated refresh token means that it was stolen, so that the whole family of tokens is revoked.
+// returns `true` if the session was revoked.
+func (r *UserRepository) RevokeReusedRefreshToken(rt string) bool {
+	var rrt entity.RotatedRefreshToken
+
+	if r.db.Where("digest = ?", digestToken(rt)).First(&rrt).RecordNotFound() {
+		return false
+	}
+
+	// rotated refresh tokens of the session are deleted by foreign key constraints.
+	if err := r.db.Where("id = ?", rrt.SessionID).Delete(&entity.Session{}).Error; err != nil {
+		log.Printf("fail to revoke session: %v", err)
+		return false
+	}
+
+	forgetLiveSession(rrt.SessionID)
+
+	log.Printf("revoked session %d by reuse of a rotated refresh token", rrt.SessionID)
+
+	return true
+}
+
+// Touch updates the last seen time of a session.
+// skip update if the session was seen within lastSeenInterval.
+func (r *UserRepository) Touch(s *entity.Session) {
+	if time.Since(s.LastSeenAt) < lastSeenInterval {
+		return
+	}
+
+	if err := r.db.Model(s).UpdateColumn("last_seen_at", time.Now()).Error; err != nil {
+		log.Printf("fail to update last seen: %v", err)
+	}
+}
+
+// SignOut delete a session of the login user.
+func (r *UserRepository) SignOut(sid, uid uint) []validator.ValidationError {
+	if err := r.db.Where("id = ? AND user_id = ?", sid, uid).Delete(&entity.Session{}).Error; err != nil {
+		log.Printf("fail to delete session: %v", err)
+		return validator.NewValidationErrors(ErrorAuthenticationFailed)
+	}
+
+	forgetLiveSession(sid)
+
+	return nil
+}
+
+// IsSignedIn returns an instance of Session that found by access token.
+// returns `false` if the record not found.
+func (r *UserRepository) IsSignedIn(token string) (*entity.Session, bool) {
+	s := &entity.Session{}
+
+	if r.db.Where("remember_token = ?", digestToken(token)).First(s).RecordNotFound() {
+		return s, false
+	}
+
+	return s, true
+}
+
+// ValidateToken returns an instance of Session that found by access token and refresh token.
+// returns `false` if the record not found.
+func (r *UserRepository) ValidateToken(at, rt string) (*entity.Session, bool) {
+	s := &entity.Session{}
+
+	if r.db.Where("remember_token = ?", digestToken(at)).Where("refresh_token = ?", digestToken(rt)).First(s).RecordNotFound() {
+		return s, false
+	}
+
+	return s, true
+}
+
+// ValidateRefreshToken returns an instance of Session that found by id and refresh token.
+// it is used when an access token is signed and not stored in database.
+// returns `false` if the record not found.
+func (r *UserRepository) ValidateRefreshToken(sid uint, rt string) (*entity.Session, bool) {
+	s := &entity.Session{}
+
+	if r.db.Where("id = ?", sid).Where("refresh_token = ?", digestToken(rt)).First(s).RecordNotFound() {
+		return s, false
+	}
+
+	return s, true
+}
+
+// GetSessions returns slice of Session's record that belongs to the login user.
+func (r *UserRepository) GetSessions(uid uint) *[]entity.Session {
+	var ss []entity.Session
+
+	r.db.Select("id, created_at, device, user_agent, ip_address, last_seen_at, expires_at").
+		Where("user_id = ?", uid).
+		Order("last_seen_at desc").
+		Find(&ss)
+
+	return &ss
+}
+
+// DeleteSession delete a session of the login user that found by id.
+func (r *UserRepository) DeleteSession(id, uid uint) []validator.ValidationError {
+	if err := deletionErrors(r.db.Where("id = ? AND user_id = ?", id, uid).Delete(&entity.Session{}), "session"); err != nil {
+		return err
+	}
+
+	forgetLiveSession(id)
+
+	return nil
+}
+
+// setSessionToken sets new plain tokens and their digests to a session.
+func setSessionToken(s *entity.Session) error {
+	at, err := newSessionToken()
+
+	if err != nil {
+		return err
+	}
+
+	rt, err := newSessionToken()
+
+	if err != nil {
+		return err
+	}
+
+	s.RememberToken = at
+	s.RefreshToken = rt
+	s.RememberDigest = digestToken(at)
+	s.RefreshDigest = digestToken(rt)
+	s.ExpiresAt = time.Now().Add(sessionLifetime)
+
+	return nil
+}
+
+func newSessionToken() (string, error) {
+	b := make([]byte, 32)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,515 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestShouldSuccessfullyCreateSession(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+	device := "laptop"
+	userAgent := "Mozilla/5.0"
+	ip := "192.0.2.1"
+
+	query := utils.ReplaceQuotationForQuery(`
+		INSERT INTO 'sessions' ('created_at','updated_at','user_id','device','user_agent','ip_address','remember_token','refresh_token','last_seen_at','expires_at')
+		VALUES (?,?,?,?,?,?,?,?,?,?)`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, userID, device, userAgent, ip, sqlmock.AnyArg(), sqlmock.AnyArg(), utils.AnyTime{}, utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	s, err := r.CreateSession(userID, device, userAgent, ip)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, s.ID, uint(1))
+	assert.Equal(t, s.UserID, userID)
+	assert.Equal(t, s.Device, device)
+	assert.NotEmpty(t, s.RememberToken)
+	assert.NotEmpty(t, s.RefreshToken)
+	assert.NotEqual(t, s.RememberToken, s.RefreshToken)
+	assert.Equal(t, digestToken(s.RememberToken), s.RememberDigest)
+	assert.Equal(t, digestToken(s.RefreshToken), s.RefreshDigest)
+	assert.True(t, s.ExpiresAt.After(time.Now()))
+}
+
+func TestShouldFailureCreateSession(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `sessions`")).
+		WillReturnError(errors.New("some error"))
+
+	mock.ExpectRollback()
+
+	_, err := r.CreateSession(uint(1), "", "", "")
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorAuthenticationFailed)
+}
+
+func TestIsSignedInShouldReturnTrueWhenTokenIsValid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	id := uint(2)
+	userID := uint(1)
+	token := "sample_token"
+
+	query := "SELECT * FROM `sessions`  WHERE (remember_token = ?) ORDER BY `sessions`.`id` ASC LIMIT 1"
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(digestToken(token)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(id, userID))
+
+	s, ok := r.IsSignedIn(token)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, s.ID, id)
+	assert.Equal(t, s.UserID, userID)
+	assert.True(t, ok)
+}
+
+func TestIsSignedInShouldReturnFalseWhenTokenIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	token := "sample_token"
+
+	query := "SELECT * FROM `sessions`  WHERE (remember_token = ?) ORDER BY `sessions`.`id` ASC LIMIT 1"
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(digestToken(token)).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, ok := r.IsSignedIn(token)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.False(t, ok)
+}
+
+func TestShouldSuccessfullyValidateToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	accessToken := "dsfjsefsefljfsf"
+	refreshToken := "eskljfnaejfauh"
+	userID := uint(1)
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT * FROM 'sessions'
+		WHERE (remember_token = ?) AND (refresh_token = ?)
+		ORDER BY 'sessions'.'id' ASC
+		LIMIT 1`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(digestToken(accessToken), digestToken(refreshToken)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "remember_token", "refresh_token"}).
+				AddRow(uint(2), userID, digestToken(accessToken), digestToken(refreshToken)))
+
+	s, ok := r.ValidateToken(accessToken, refreshToken)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.True(t, ok)
+	assert.Equal(t, s.UserID, userID)
+	assert.Equal(t, s.RememberDigest, digestToken(accessToken))
+	assert.Equal(t, s.RefreshDigest, digestToken(refreshToken))
+}
+
+func TestShouldFailureValidateToken(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	accessToken := "dsfjsefsefljfsf"
+	refreshToken := "eskljfnaejfauh"
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT * FROM 'sessions'
+		WHERE (remember_token = ?) AND (refresh_token = ?)
+		ORDER BY 'sessions'.'id' ASC
+		LIMIT 1`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(digestToken(accessToken), digestToken(refreshToken)).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, ok := r.ValidateToken(accessToken, refreshToken)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.False(t, ok)
+}
+
+func TestShouldSuccessfullyUpdateSession(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	s := &entity.Session{ID: uint(2), RememberDigest: "old_access_digest", RefreshDigest: "old_refresh_digest"}
+
+	query := utils.ReplaceQuotationForQuery(`
+		UPDATE 'sessions'
+		SET 'expires_at' = ?, 'last_seen_at' = ?, 'refresh_token' = ?, 'remember_token' = ?, 'updated_at' = ?
+		WHERE 'sessions'.'id' = ? AND ((refresh_token = ?))`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `rotated_refresh_tokens` (`created_at`,`session_id`,`digest`)")).
+		WithArgs(utils.AnyTime{}, s.ID, "old_refresh_digest").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, sqlmock.AnyArg(), sqlmock.AnyArg(), utils.AnyTime{}, s.ID, "old_refresh_digest").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.UpdateSession(s); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.NotEmpty(t, s.RememberToken)
+	assert.NotEmpty(t, s.RefreshToken)
+	assert.Equal(t, digestToken(s.RefreshToken), s.RefreshDigest)
+}
+
+func TestShouldFailureUpdateSession(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	s := &entity.Session{ID: uint(2), RefreshDigest: "old_refresh_digest"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `rotated_refresh_tokens`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `sessions`")).
+		WillReturnError(errors.New("some error"))
+
+	mock.ExpectRollback()
+
+	err := r.UpdateSession(s)
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorAuthenticationFailed)
+}
+
+func TestShouldFailureUpdateSessionWhenRefreshTokenWasRotatedConcurrently(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	s := &entity.Session{ID: uint(2), RefreshDigest: "old_refresh_digest"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `rotated_refresh_tokens`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `sessions`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectRollback()
+
+	err := r.UpdateSession(s)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorAuthenticationFailed)
+}
+
+func TestShouldRevokeSessionWhenRotatedRefreshTokenIsReused(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	refreshToken := "old_refresh_token"
+	sessionID := uint(2)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `rotated_refresh_tokens` WHERE (digest = ?)")).
+		WithArgs(digestToken(refreshToken)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "session_id"}).AddRow(uint(1), sessionID))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions` WHERE (id = ?)")).
+		WithArgs(sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	ok := r.RevokeReusedRefreshToken(refreshToken)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.True(t, ok)
+}
+
+func TestShouldNotRevokeSessionWhenRefreshTokenIsUnknown(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `rotated_refresh_tokens`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ok := r.RevokeReusedRefreshToken("unknown")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.False(t, ok)
+}
+
+func TestTouchShouldNotUpdateWhenSessionWasSeenRecently(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	r.Touch(&entity.Session{ID: uint(2), LastSeenAt: time.Now()})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldSuccessfullySignOut(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	sessionID := uint(2)
+	userID := uint(1)
+
+	query := "DELETE FROM `sessions` WHERE (id = ? AND user_id = ?)"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(sessionID, userID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.SignOut(sessionID, userID); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldFailureSignOut(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	sessionID := uint(2)
+	userID := uint(1)
+
+	query := "DELETE FROM `sessions` WHERE (id = ? AND user_id = ?)"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(sessionID, userID).
+		WillReturnError(errors.New("some error"))
+
+	mock.ExpectRollback()
+
+	err := r.SignOut(sessionID, userID)
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorAuthenticationFailed)
+}
+
+func TestShouldSuccessfullyGetSessions(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT id, created_at, device, user_agent, ip_address, last_seen_at, expires_at
+		FROM 'sessions'
+		WHERE (user_id = ?)
+		ORDER BY last_seen_at desc`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(userID).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "device"}).
+				AddRow(uint(1), "laptop").
+				AddRow(uint(2), "phone"))
+
+	ss := r.GetSessions(userID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, len(*ss), 2)
+	assert.Equal(t, (*ss)[0].Device, "laptop")
+	assert.Equal(t, (*ss)[1].Device, "phone")
+}
+
+func TestShouldSuccessfullyDeleteSession(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	id := uint(2)
+	userID := uint(1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions` WHERE (id = ? AND user_id = ?)")).
+		WithArgs(id, userID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.DeleteSession(id, userID); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldFailureDeleteSessionWhenSessionDoesNotBelongToUser(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	id := uint(2)
+	userID := uint(1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions` WHERE (id = ? AND user_id = ?)")).
+		WithArgs(id, userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	err := r.DeleteSession(id, userID)
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorRecordNotFound)
+}
+
+func TestShouldFailureDeleteSessionWhenDatabaseFails(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `sessions` WHERE (id = ? AND user_id = ?)")).
+		WithArgs(uint(2), uint(1)).
+		WillReturnError(errors.New("some error"))
+
+	mock.ExpectRollback()
+
+	err := r.DeleteSession(uint(2), uint(1))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidRequest), err)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// streamTokenLifetime is short, because a stream token is sent in a URL and may be left in logs of proxies.
+const streamTokenLifetime = time.Minute
+
+// CreateStreamToken returns a new token to open a stream of events as the user.
+func (r *UserRepository) CreateStreamToken(uid uint) (string, []validator.ValidationError) {
+	t, err := createOneTimeToken(r.db, uid, entity.PurposeEventStream, streamTokenLifetime)
+
+	if err != nil {
+		log.Printf("fail to create stream token: %v", err)
+		return "", validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return t, nil
+}
+
+// UseStreamToken returns an id of the user who owns a stream token, and marks the token as used.
+// a token of the user who was disabled after it was issued is rejected.
+func (r *UserRepository) UseStreamToken(token string) (uint, []validator.ValidationError) {
+	var uid uint
+	var verr []validator.ValidationError
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		ot, err := useOneTimeToken(tx, token, entity.PurposeEventStream)
+
+		if err != nil {
+			verr = err
+			return gorm.ErrRecordNotFound
+		}
+
+		if tx.Select("id").Where("disabled_at IS NULL").First(&entity.User{}, ot.UserID).RecordNotFound() {
+			verr = validator.NewValidationErrors(ErrorUserDisabled)
+			return gorm.ErrRecordNotFound
+		}
+
+		uid = ot.UserID
+
+		return nil
+	})
+
+	if verr != nil {
+		return 0, verr
+	}
+
+	if err != nil {
+		log.Printf("fail to use stream token: %v", err)
+		return 0, validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return uid, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+)
+
+const (
+	// emailFreeAttempts is the number of failed sign in attempts per email address that are allowed without lockout.
+	emailFreeAttempts = 5
+	// ipFreeAttempts is larger than emailFreeAttempts, because many users can share an IP address.
+	ipFreeAttempts = 20
+	// lockoutBase is the lockout duration after the first attempt over the free attempts.
+	// it is doubled on every further failure up to lockoutMax.
+	lockoutBase = time.Minute * 1
+	lockoutMax  = time.Hour * 1
+	// failureWindow is the duration after which failed attempts are forgotten.
+	failureWindow = time.Hour * 24
+)
+
+func emailIdentifier(email string) string {
+	return "email:" + email
+}
+
+func ipIdentifier(ip string) string {
+	return "ip:" + ip
+}
+
+// IsLocked check if sign in by an email address or from an IP address is locked.
+// returns the remaining duration of the lockout.
+func (r *UserRepository) IsLocked(email, ip string) (time.Duration, bool) {
+	var ids []string
+
+	if email != "" {
+		ids = append(ids, emailIdentifier(email))
+	}
+
+	if ip != "" {
+		ids = append(ids, ipIdentifier(ip))
+	}
+
+	if len(ids) == 0 {
+		return 0, false
+	}
+
+	var t entity.SignInThrottle
+
+	if r.db.Where("identifier IN (?) AND locked_until > ?", ids, time.Now()).
+		Order("locked_until desc").
+		First(&t).
+		RecordNotFound() {
+		return 0, false
+	}
+
+	return time.Until(*t.LockedUntil), true
+}
+
+// RecordFailedSignIn counts a failed sign in attempt by an email address and from an IP address.
+// sign in is locked with exponential back-off when the failures exceed the free attempts.
+func (r *UserRepository) RecordFailedSignIn(email, ip string) {
+	if email != "" {
+		r.recordFailure(emailIdentifier(email), emailFreeAttempts)
+	}
+
+	if ip != "" {
+		r.recordFailure(ipIdentifier(ip), ipFreeAttempts)
+	}
+}
+
+// ClearSignInFailures forgets failed sign in attempts by an email address and unlocks it.
+func (r *UserRepository) ClearSignInFailures(email string) {
+	clearSignInFailures(r.db, email)
+}
+
+func clearSignInFailures(db *gorm.DB, email string) error {
+	if err := db.Where("identifier = ?", emailIdentifier(email)).Delete(&entity.SignInThrottle{}).Error; err != nil {
+		log.Printf("fail to clear sign in failures: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// recordFailure counts a failure of an identifier and locks it when the failures exceed the free attempts.
+// the failure is counted by an upsert, so that concurrent first failures of an identifier are all counted.
+func (r *UserRepository) recordFailure(identifier string, freeAttempts int) {
+	now := time.Now()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(
+			"INSERT INTO sign_in_throttles (identifier, failures, last_failed_at) VALUES (?, 1, ?) ON DUPLICATE KEY UPDATE failures = IF(last_failed_at < ?, 1, failures + 1), last_failed_at = VALUES(last_failed_at)",
+			identifier, now, now.Add(-failureWindow)).Error; err != nil {
+			return err
+		}
+
+		var t entity.SignInThrottle
+
+		if err := tx.Where("identifier = ?", identifier).First(&t).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&t).UpdateColumn("locked_until", lockedUntil(t.Failures, freeAttempts, now)).Error
+	})
+
+	if err != nil {
+		log.Printf("fail to record failed sign in: %v", err)
+	}
+}
+
+// lockedUntil returns the end of the lockout for the number of failures.
+// returns nil if the failures are within the free attempts.
+func lockedUntil(failures, freeAttempts int, now time.Time) *time.Time {
+	if failures <= freeAttempts {
+		return nil
+	}
+
+	d := lockoutBase
+
+	for i := freeAttempts + 1; i < failures && d < lockoutMax; i++ {
+		d *= 2
+	}
+
+	if d > lockoutMax {
+		d = lockoutMax
+	}
+
+	t := now.Add(d)
+
+	return &t
+}
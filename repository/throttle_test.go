@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/utils"
+)
+
+func TestLockedUntil(t *testing.T) {
+	now := time.Now()
+
+	type testCase struct {
+		testName string
+		failures int
+		expected time.Duration
+	}
+
+	testCases := []testCase{
+		{testName: "when failures are within free attempts", failures: 5, expected: 0},
+		{testName: "when failures exceed free attempts", failures: 6, expected: time.Minute * 1},
+		{testName: "when failures exceed free attempts twice", failures: 7, expected: time.Minute * 2},
+		{testName: "when failures exceed free attempts three times", failures: 8, expected: time.Minute * 4},
+		{testName: "when failures are too many", failures: 30, expected: time.Hour * 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			l := lockedUntil(tc.failures, emailFreeAttempts, now)
+
+			if tc.expected == 0 {
+				assert.Nil(t, l)
+				return
+			}
+
+			assert.Equal(t, l.Sub(now), tc.expected)
+		})
+	}
+}
+
+func TestShouldReturnsLockedWhenSignInIsLocked(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	email := "gopher@sample.com"
+	ip := "192.0.2.1"
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT * FROM 'sign_in_throttles'
+		WHERE (identifier IN (?,?) AND locked_until > ?)
+		ORDER BY locked_until desc,'sign_in_throttles'.'id' ASC
+		LIMIT 1`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs("email:"+email, "ip:"+ip, utils.AnyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "locked_until"}).AddRow(uint(1), time.Now().Add(time.Minute*2)))
+
+	d, locked := r.IsLocked(email, ip)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.True(t, locked)
+	assert.True(t, d > time.Minute)
+}
+
+func TestShouldReturnsNotLockedWhenSignInIsNotLocked(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sign_in_throttles`")).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, locked := r.IsLocked("gopher@sample.com", "192.0.2.1")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.False(t, locked)
+}
+
+func TestShouldCountFailedSignInWithUpsert(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	email := "gopher@sample.com"
+
+	findQuery := utils.ReplaceQuotationForQuery(`
+		SELECT * FROM 'sign_in_throttles'
+		WHERE (identifier = ?)
+		ORDER BY 'sign_in_throttles'.'id' ASC
+		LIMIT 1`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO sign_in_throttles (identifier, failures, last_failed_at) VALUES (?, 1, ?) ON DUPLICATE KEY UPDATE failures = IF(last_failed_at < ?, 1, failures + 1), last_failed_at = VALUES(last_failed_at)")).
+		WithArgs("email:"+email, utils.AnyTime{}, utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta(findQuery)).
+		WithArgs("email:" + email).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "identifier", "failures", "last_failed_at"}).
+				AddRow(uint(1), "email:"+email, 1, time.Now()))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `sign_in_throttles` SET `locked_until` = ? WHERE `sign_in_throttles`.`id` = ?")).
+		WithArgs(nil, uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.RecordFailedSignIn(email, "")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldLockSignInWhenFailuresExceedFreeAttempts(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	email := "gopher@sample.com"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO sign_in_throttles")).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sign_in_throttles`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "identifier", "failures", "last_failed_at"}).
+				AddRow(uint(1), "email:"+email, emailFreeAttempts+1, time.Now()))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `sign_in_throttles` SET `locked_until` = ? WHERE `sign_in_throttles`.`id` = ?")).
+		WithArgs(utils.AnyTime{}, uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	r.RecordFailedSignIn(email, "")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"golang.org/x/crypto/bcrypt"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+const (
+	totpIssuer = "kanban"
+	// totpPeriod is a time step of RFC 6238 in seconds.
+	totpPeriod = 30
+	// totpDigits is the number of digits of a code, which is up to 9 so that a code fits in uint32.
+	totpDigits = 6
+	// totpSkew is the number of time steps that are accepted before and after the current one.
+	totpSkew = 1
+
+	recoveryCodeCount       = 10
+	signInChallengeLifetime = time.Minute * 5
+	// signInChallengeMaxFailures is the number of wrong codes after which a challenge token can not be used anymore.
+	signInChallengeMaxFailures = 5
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// SetUpTOTP generates a new TOTP secret of the user.
+// the secret is not used for sign in until it was confirmed by EnableTOTP.
+// returns the secret and a provisioning URI for authenticator apps.
+func (r *UserRepository) SetUpTOTP(u *entity.User) (string, string, []validator.ValidationError) {
+	if u.TOTPEnabledAt != nil {
+		return "", "", validator.NewValidationErrors(ErrorTOTPAlreadyEnabled)
+	}
+
+	b := make([]byte, 20)
+
+	if _, err := rand.Read(b); err != nil {
+		log.Printf("fail to create totp secret: %v", err)
+		return "", "", validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	secret := base32NoPadding.EncodeToString(b)
+
+	if err := r.db.Model(u).UpdateColumn("totp_secret", secret).Error; err != nil {
+		log.Printf("fail to update totp secret: %v", err)
+		return "", "", validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return secret, provisioningURI(u.Email, secret), nil
+}
+
+// EnableTOTP enables two-factor authentication after confirming a code of the pending secret.
+// returns recovery codes that are shown only once.
+func (r *UserRepository) EnableTOTP(u *entity.User, code string) ([]string, []validator.ValidationError) {
+	if u.TOTPEnabledAt != nil {
+		return nil, validator.NewValidationErrors(ErrorTOTPAlreadyEnabled)
+	}
+
+	if u.TOTPSecret == "" {
+		return nil, validator.NewValidationErrors(ErrorTOTPNotEnabled)
+	}
+
+	step, ok := validateTOTP(u.TOTPSecret, code, time.Now(), 0)
+
+	if !ok {
+		return nil, validator.NewValidationErrors(ErrorInvalidTOTPCode)
+	}
+
+	var codes []string
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(u).UpdateColumns(map[string]interface{}{
+			"totp_enabled_at": time.Now(),
+			"totp_last_step":  step,
+		}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = createRecoveryCodes(tx, u.ID)
+
+		return err
+	})
+
+	if err != nil {
+		log.Printf("fail to enable totp: %v", err)
+		return nil, validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return codes, nil
+}
+
+// DisableTOTP disables two-factor authentication after confirming the password and a second factor.
+func (r *UserRepository) DisableTOTP(u *entity.User, password, code string) []validator.ValidationError {
+	if u.TOTPEnabledAt == nil {
+		return validator.NewValidationErrors(ErrorTOTPNotEnabled)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordDigest), []byte(password)); err != nil {
+		return validator.NewValidationErrors(ErrorInvalidPassword)
+	}
+
+	var verr []validator.ValidationError
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if verr = verifySecondFactor(tx, u, code); verr != nil {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Model(u).UpdateColumns(map[string]interface{}{
+			"totp_secret":     "",
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", u.ID).Delete(&entity.RecoveryCode{}).Error
+	})
+
+	if verr != nil {
+		return verr
+	}
+
+	if err != nil {
+		log.Printf("fail to disable totp: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return nil
+}
+
+// CreateSignInChallenge returns a token that identifies a sign in waiting for a second factor.
+func (r *UserRepository) CreateSignInChallenge(uid uint) (string, []validator.ValidationError) {
+	t, err := createOneTimeToken(r.db, uid, entity.PurposeSignInChallenge, signInChallengeLifetime)
+
+	if err != nil {
+		log.Printf("fail to create sign in challenge: %v", err)
+		return "", validator.NewValidationErrors(ErrorAuthenticationFailed)
+	}
+
+	return t, nil
+}
+
+// PassSignInChallenge returns the user who owns the challenge token if a second factor was valid.
+// the challenge token is not consumed by an invalid code, so that the user can retry, but it is consumed after signInChallengeMaxFailures invalid codes.
+// the user is returned with an invalid code as well, so that the failure can be counted against the email address.
+func (r *UserRepository) PassSignInChallenge(token, code string) (*entity.User, []validator.ValidationError) {
+	u := &entity.User{}
+
+	var verr []validator.ValidationError
+	wrongCode := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		ot, err := useOneTimeToken(tx, token, entity.PurposeSignInChallenge)
+
+		if err != nil {
+			verr = err
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.First(u, ot.UserID).Error; err != nil {
+			return err
+		}
+
+		if verr = verifySecondFactor(tx, u, code); verr != nil {
+			wrongCode = true
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+
+	if wrongCode {
+		failOneTimeToken(r.db, token, entity.PurposeSignInChallenge, signInChallengeMaxFailures)
+	}
+
+	if verr != nil {
+		return u, verr
+	}
+
+	if err != nil {
+		log.Printf("fail to pass sign in challenge: %v", err)
+		return u, validator.NewValidationErrors(ErrorAuthenticationFailed)
+	}
+
+	return u, nil
+}
+
+// verifySecondFactor accepts a TOTP code or an unused recovery code.
+// a TOTP code is not accepted twice, and a recovery code is marked as used.
+func verifySecondFactor(db *gorm.DB, u *entity.User, code string) []validator.ValidationError {
+	if u.TOTPEnabledAt == nil {
+		return validator.NewValidationErrors(ErrorTOTPNotEnabled)
+	}
+
+	if step, ok := validateTOTP(u.TOTPSecret, code, time.Now(), u.TOTPLastStep); ok {
+		// conditional update prevents that a code is used twice by concurrent requests.
+		if rslt := db.Model(u).Where("totp_last_step < ?", step).UpdateColumn("totp_last_step", step); rslt.RowsAffected == 0 {
+			return validator.NewValidationErrors(ErrorInvalidTOTPCode)
+		}
+
+		return nil
+	}
+
+	if rslt := db.Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND digest = ? AND used_at IS NULL", u.ID, digestToken(normalizeRecoveryCode(code))).
+		UpdateColumn("used_at", time.Now()); rslt.RowsAffected == 0 {
+		return validator.NewValidationErrors(ErrorInvalidTOTPCode)
+	}
+
+	return nil
+}
+
+// createRecoveryCodes replaces recovery codes of the user and returns the raw codes.
+func createRecoveryCodes(db *gorm.DB, uid uint) ([]string, error) {
+	if err := db.Where("user_id = ?", uid).Delete(&entity.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		b := make([]byte, 5)
+
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+
+		h := hex.EncodeToString(b)
+		codes[i] = fmt.Sprintf("%s-%s", h[:5], h[5:])
+
+		if err := db.Create(&entity.RecoveryCode{
+			UserID: uid,
+			Digest: digestToken(normalizeRecoveryCode(codes[i])),
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+func provisioningURI(email, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", totpIssuer)
+	v.Set("period", fmt.Sprint(totpPeriod))
+	v.Set("digits", fmt.Sprint(totpDigits))
+
+	label := url.PathEscape(fmt.Sprintf("%s:%s", totpIssuer, email))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, v.Encode())
+}
+
+// validateTOTP check a code against time steps around t.
+// returns the matched time step if it is later than lastStep.
+func validateTOTP(secret, code string, t time.Time, lastStep int64) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	key, err := base32NoPadding.DecodeString(secret)
+
+	if err != nil {
+		return 0, false
+	}
+
+	current := t.Unix() / totpPeriod
+
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+
+		if hmac.Equal([]byte(hotp(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// hotp returns a HMAC-based one time password of RFC 4226.
+func hotp(key []byte, counter int64) string {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(counter))
+
+	m := hmac.New(sha1.New, key)
+	m.Write(b)
+	sum := m.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, bin%mod)
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+)
+
+func TestHOTPShouldMatchRFC4226TestValues(t *testing.T) {
+	key := []byte("12345678901234567890")
+	expected := []string{"755224", "287082", "359152", "969429", "338314"}
+
+	for i, e := range expected {
+		assert.Equal(t, hotp(key, int64(i)), e)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := base32NoPadding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111109, 0)
+	step := now.Unix() / totpPeriod
+
+	key, _ := base32NoPadding.DecodeString(secret)
+
+	// a test value of RFC 6238 that is truncated to 6 digits.
+	assert.Equal(t, hotp(key, step), "081804")
+
+	type testCase struct {
+		testName string
+		code     string
+		lastStep int64
+		expected bool
+	}
+
+	testCases := []testCase{
+		{testName: "when code is of the current step", code: hotp(key, step), expected: true},
+		{testName: "when code is of the previous step", code: hotp(key, step-1), expected: true},
+		{testName: "when code is too old", code: hotp(key, step-2), expected: false},
+		{testName: "when code has already been used", code: hotp(key, step), lastStep: step, expected: false},
+		{testName: "when code has invalid length", code: "12345", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			_, ok := validateTOTP(secret, tc.code, now, tc.lastStep)
+			assert.Equal(t, ok, tc.expected)
+		})
+	}
+}
+
+func TestShouldSuccessfullySetUpTOTP(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	u := &entity.User{ID: uint(1), Email: "gopher@sample.com"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `users` SET `totp_secret` = ? WHERE `users`.`id` = ?")).
+		WithArgs(sqlmock.AnyArg(), u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	secret, uri, err := r.SetUpTOTP(u)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Len(t, secret, 32)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/kanban:gopher@sample.com?"))
+	assert.Contains(t, uri, "secret="+secret)
+}
+
+func TestShouldNotSetUpTOTPWhenAlreadyEnabled(t *testing.T) {
+	db, _ := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	now := time.Now()
+
+	_, _, err := r.SetUpTOTP(&entity.User{ID: uint(1), TOTPEnabledAt: &now})
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	assert.Equal(t, err[0].Text, ErrorTOTPAlreadyEnabled)
+}
+
+func TestShouldSuccessfullyEnableTOTP(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	secret := base32NoPadding.EncodeToString([]byte("12345678901234567890"))
+	key, _ := base32NoPadding.DecodeString(secret)
+	u := &entity.User{ID: uint(1), TOTPSecret: secret}
+
+	code := hotp(key, time.Now().Unix()/totpPeriod)
+
+	updateQuery := "UPDATE `users` SET `totp_enabled_at` = ?, `totp_last_step` = ? WHERE `users`.`id` = ?"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+		WithArgs(utils.AnyTime{}, sqlmock.AnyArg(), u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `recovery_codes` WHERE (user_id = ?)")).
+		WithArgs(u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `recovery_codes`")).
+			WithArgs(utils.AnyTime{}, u.ID, sqlmock.AnyArg(), nil).
+			WillReturnResult(sqlmock.NewResult(int64(i+1), 1))
+	}
+
+	mock.ExpectCommit()
+
+	codes, err := r.EnableTOTP(u, code)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Len(t, codes, recoveryCodeCount)
+	assert.Regexp(t, "^[0-9a-f]{5}-[0-9a-f]{5}$", codes[0])
+}
+
+func TestShouldNotEnableTOTPWhenCodeIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	secret := base32NoPadding.EncodeToString([]byte("12345678901234567890"))
+
+	_, err := r.EnableTOTP(&entity.User{ID: uint(1), TOTPSecret: secret}, "000000")
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorInvalidTOTPCode)
+}
+
+func TestShouldPassSignInChallengeWithTOTPCode(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	token := "sampletoken"
+	userID := uint(1)
+	secret := base32NoPadding.EncodeToString([]byte("12345678901234567890"))
+	key, _ := base32NoPadding.DecodeString(secret)
+	step := time.Now().Unix() / totpPeriod
+
+	lastStepQuery := utils.ReplaceQuotationForQuery(`
+		UPDATE 'users' SET 'totp_last_step' = ?
+		WHERE 'users'.'id' = ? AND ((totp_last_step < ?))`)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `one_time_tokens`")).
+		WithArgs(digestToken(token), entity.PurposeSignInChallenge, utils.AnyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(3), userID))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `one_time_tokens`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "totp_secret", "totp_enabled_at", "totp_last_step"}).
+				AddRow(userID, secret, time.Now(), 0))
+
+	mock.ExpectExec(regexp.QuoteMeta(lastStepQuery)).
+		WithArgs(step, userID, step).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	u, err := r.PassSignInChallenge(token, hotp(key, step))
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, u.ID, userID)
+}
+
+func TestShouldPassSignInChallengeWithRecoveryCode(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+	code := "a1b2c-3d4e5"
+
+	recoveryQuery := utils.ReplaceQuotationForQuery(`
+		UPDATE 'recovery_codes' SET 'used_at' = ?
+		WHERE (user_id = ? AND digest = ? AND used_at IS NULL)`)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `one_time_tokens`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(3), userID))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `one_time_tokens`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "totp_secret", "totp_enabled_at"}).
+				AddRow(userID, base32NoPadding.EncodeToString([]byte("12345678901234567890")), time.Now()))
+
+	mock.ExpectExec(regexp.QuoteMeta(recoveryQuery)).
+		WithArgs(utils.AnyTime{}, userID, digestToken("a1b2c3d4e5")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if _, err := r.PassSignInChallenge("sampletoken", code); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldFailurePassSignInChallengeWhenCodeIsInvalid(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewUserRepository(db)
+
+	userID := uint(1)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `one_time_tokens`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(uint(3), userID))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `one_time_tokens`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "totp_secret", "totp_enabled_at"}).
+				AddRow(userID, base32NoPadding.EncodeToString([]byte("12345678901234567890")), time.Now()))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `recovery_codes`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `one_time_tokens` SET `failures` = failures + 1 WHERE (digest = ? AND purpose = ? AND used_at IS NULL)")).
+		WithArgs(digestToken("sampletoken"), entity.PurposeSignInChallenge).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `one_time_tokens` SET `used_at` = ? WHERE (digest = ? AND purpose = ? AND used_at IS NULL AND failures >= ?)")).
+		WithArgs(utils.AnyTime{}, digestToken("sampletoken"), entity.PurposeSignInChallenge, signInChallengeMaxFailures).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	u, err := r.PassSignInChallenge("sampletoken", "000000")
+
+	if err == nil {
+		t.Errorf("was expected an error but did not recieved it.")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, err[0].Text, ErrorInvalidTOTPCode)
+	assert.Equal(t, userID, u.ID)
+}
@@ -1,19 +1,19 @@
 package repository
 
 import (
-	"crypto/rand"
-	"encoding/base64"
-	"log"
 	"time"
 
 	"github.com/jinzhu/gorm"
 	"golang.org/x/crypto/bcrypt"
 
+	"local.packages/config"
 	"local.packages/entity"
-	"local.packages/utils"
 	"local.packages/validator"
 )
 
+// defaultTestUserIDs are ids of users for testing when `demo.user_ids` is not specified in config file.
+var defaultTestUserIDs = []uint{2, 3, 4, 5}
+
 // UserRepository ...
 type UserRepository struct {
 	db *gorm.DB
@@ -30,12 +30,17 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 func (r *UserRepository) TestUsers() *[]entity.User {
 	var us []entity.User
 
-	r.db.Select("id, name, email, expires_at").Where("id IN (?)", []uint{2, 3, 4, 5}).Find(&us)
+	r.db.Select("id, name, email").
+		Preload("Sessions", func(db *gorm.DB) *gorm.DB {
+			return db.Select("user_id, expires_at").Order("expires_at desc")
+		}).
+		Where("id IN (?)", testUserIDs()).
+		Find(&us)
 
 	return &us
 }
 
-// IsExpire validate a session has not expired.
+// IsExpire validate that the user is a test user and a session has not expired.
 func (r *UserRepository) IsExpire(email string) (bool, []validator.ValidationError) {
 	u := &entity.User{}
 
@@ -43,7 +48,13 @@ func (r *UserRepository) IsExpire(email string) (bool, []validator.ValidationErr
 		return false, validator.NewValidationErrors(ErrorUserDoesNotExist)
 	}
 
-	if e := utils.CalcExpiresIn(u.ExpiresAt); e > 0 /*token is valid*/ {
+	if !r.IsTestUserID(u.ID) {
+		return false, validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	var s entity.Session
+
+	if !r.db.Select("id").Where("user_id = ? AND expires_at > ?", u.ID, time.Now()).First(&s).RecordNotFound() {
 		return false, validator.NewValidationErrors(ErrorUnavailableTestUser)
 	}
 
@@ -58,14 +69,22 @@ func (r *UserRepository) IsTester(email string) (bool, []validator.ValidationErr
 		return false, validator.NewValidationErrors(ErrorUserDoesNotExist)
 	}
 
-	ids := []uint{2, 3, 4, 5} // test user ids
+	if r.IsTestUserID(u.ID) {
+		return true, validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return false, nil
+}
 
-	for _, id := range ids {
-		if id == u.ID {
-			return true, validator.NewValidationErrors(ErrorInvalidRequest)
+// IsTestUserID check if an id belongs to a test user.
+func (r *UserRepository) IsTestUserID(uid uint) bool {
+	for _, id := range testUserIDs() {
+		if id == uid {
+			return true
 		}
 	}
-	return false, nil
+
+	return false
 }
 
 // Create insert a new record to users table.
@@ -83,33 +102,18 @@ func (r *UserRepository) Create(name, email, password string) (*entity.User, []v
 	return u, nil
 }
 
-// UpdateUserSession updates access token, refresh token and expires.
-func (r *UserRepository) UpdateUserSession(u *entity.User) []validator.ValidationError {
-	at, err := newSessionToken()
-
-	if err != nil {
-		log.Printf("fail to create access token: %v", err)
-		return validator.NewValidationErrors(ErrorAuthenticationFailed)
-	}
-
-	rt, err := newSessionToken()
-
-	if err != nil {
-		log.Printf("fail to create refresh token: %v", err)
-		return validator.NewValidationErrors(ErrorAuthenticationFailed)
-	}
-
-	expire := time.Now().Add(time.Hour * 1)
+// Find returns a record of User that found by id.
+func (r *UserRepository) Find(id uint) (*entity.User, []validator.ValidationError) {
+	u := &entity.User{}
 
-	if err := r.db.Model(u).Updates(map[string]interface{}{"remember_token": at, "refresh_token": rt, "expires_at": expire}).Error; err != nil {
-		log.Printf("fail to update user session: %v", err)
-		return validator.NewValidationErrors(ErrorAuthenticationFailed)
+	if r.db.First(u, id).RecordNotFound() {
+		return u, validator.NewValidationErrors(ErrorUserDoesNotExist)
 	}
 
-	return nil
+	return u, nil
 }
 
-// SignIn returns instance of User that contain a new session token.
+// SignIn returns instance of User that found by an email and password.
 // returns errors with message if an email or password is invalid.
 func (r *UserRepository) SignIn(email, password string) (*entity.User, []validator.ValidationError) {
 	u := &entity.User{}
@@ -122,47 +126,13 @@ func (r *UserRepository) SignIn(email, password string) (*entity.User, []validat
 		return u, validator.NewValidationErrors(ErrorInvalidPassword)
 	}
 
-	if err := r.UpdateUserSession(u); err != nil {
-		return u, err
+	if u.DisabledAt != nil {
+		return u, validator.NewValidationErrors(ErrorUserDisabled)
 	}
 
 	return u, nil
 }
 
-// SignOut delete remember token and refresh token.
-func (r *UserRepository) SignOut(uid uint) []validator.ValidationError {
-	if err := r.db.Table("users").Where("id = ?", uid).Updates(map[string]interface{}{"expires_at": nil, "remember_token": nil, "refresh_token": nil}).Error; err != nil {
-		log.Printf("fail to delete session: %v", err)
-		return validator.NewValidationErrors(ErrorAuthenticationFailed)
-	}
-
-	return nil
-}
-
-// IsSignedIn returns an instance of User that found by session token.
-// returns `false` if the record not found.
-func (r *UserRepository) IsSignedIn(token string) (*entity.User, bool) {
-	u := &entity.User{}
-
-	if r.db.Where("remember_token = ?", token).First(u).RecordNotFound() {
-		return u, false
-	}
-
-	return u, true
-}
-
-// ValidateToken returns an instance of User that found by access token and refresh token.
-// returns `false` if the record not found.
-func (r *UserRepository) ValidateToken(at, rt string) (*entity.User, bool) {
-	u := &entity.User{}
-
-	if r.db.Where("remember_token = ?", at).Where("refresh_token = ?", rt).First(u).RecordNotFound() {
-		return u, false
-	}
-
-	return u, true
-}
-
 // EncryptPassword returns the bcrypt hash of the password.
 func (r *UserRepository) EncryptPassword(password string) (string, []validator.ValidationError) {
 	h, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -176,12 +146,11 @@ func (r *UserRepository) EncryptPassword(password string) (string, []validator.V
 	return digest, nil
 }
 
-func newSessionToken() (string, error) {
-	b := make([]byte, 32)
-
-	if _, err := rand.Read(b); err != nil {
-		return "", err
+// testUserIDs returns ids of users that can be signed in by anyone for testing.
+func testUserIDs() []uint {
+	if ids := config.Config.Demo.UserIDs; len(ids) > 0 {
+		return ids
 	}
 
-	return base64.URLEncoding.EncodeToString(b), nil
+	return defaultTestUserIDs
 }
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"log"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+const verificationLifetime = time.Hour * 24
+
+// CreateVerificationToken returns a new token to verify an email address of the user.
+func (r *UserRepository) CreateVerificationToken(uid uint) (string, []validator.ValidationError) {
+	t, err := createOneTimeToken(r.db, uid, entity.PurposeEmailVerification, verificationLifetime)
+
+	if err != nil {
+		log.Printf("fail to create verification token: %v", err)
+		return "", validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return t, nil
+}
+
+// VerifyEmail marks an email address of the user who owns the token as verified.
+func (r *UserRepository) VerifyEmail(token string) []validator.ValidationError {
+	var verr []validator.ValidationError
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		ot, err := useOneTimeToken(tx, token, entity.PurposeEmailVerification)
+
+		if err != nil {
+			verr = err
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Table("users").Where("id = ?", ot.UserID).UpdateColumn("verified_at", time.Now()).Error
+	})
+
+	if verr != nil {
+		return verr
+	}
+
+	if err != nil {
+		log.Printf("fail to verify email: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return nil
+}
+
+// IsVerified check if an email address of the user has been verified.
+func (r *UserRepository) IsVerified(uid uint) bool {
+	u := &entity.User{}
+
+	if r.db.Select("id").Where("verified_at IS NOT NULL").First(u, uid).RecordNotFound() {
+		return false
+	}
+
+	return true
+}
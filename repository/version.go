@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+
+	"local.packages/validator"
+)
+
+// updateWithVersion updates columns of a record and increments its version.
+// the record is updated only if its version is still the one that was found, so that a change made by another request in the meantime is not overwritten.
+// returns ErrorConflict when the record has been updated by another request.
+func updateWithVersion(db *gorm.DB, value interface{}, version *uint, columns map[string]interface{}) []validator.ValidationError {
+	columns["version"] = gorm.Expr("version + 1")
+
+	rslt := db.Model(value).Where("version = ?", *version).Updates(columns)
+
+	if rslt.Error != nil {
+		return validator.FormattedValidationError(rslt.Error)
+	}
+
+	if rslt.RowsAffected == 0 {
+		return validator.NewValidationErrors(ErrorConflict)
+	}
+
+	*version++
+
+	return nil
+}
+
+// whereVersion restricts a query to a version of a record if the version is given.
+// a nil version means that a client does not ask for a conditional request.
+func whereVersion(version *uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if version == nil {
+			return db
+		}
+
+		return db.Where("version = ?", *version)
+	}
+}
+
+// versionedDeletionErrors returns errors of a deletion of a record by its result like deletionErrors.
+// returns ErrorConflict when a version was given and no rows were affected, because the record has been updated or deleted by another request.
+func versionedDeletionErrors(rslt *gorm.DB, name string, version *uint) []validator.ValidationError {
+	if rslt.Error == nil && rslt.RowsAffected == 0 && version != nil {
+		return validator.NewValidationErrors(ErrorConflict)
+	}
+
+	return deletionErrors(rslt, name)
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"log"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// watchingMembers joins watches with members of a board joined as lists, so that users who left the board are not notified.
+func watchingMembers(db *gorm.DB) *gorm.DB {
+	return db.Joins("Join board_members ON board_members.board_id = lists.board_id AND board_members.user_id = watches.user_id")
+}
+
+// cardWatcherIDs returns ids of users who are notified of changes to a card.
+// assignees of a card watch it, as well as members who watch the card, its list or its board.
+func cardWatcherIDs(db *gorm.DB, cid uint) []uint {
+	var aids, wids []uint
+
+	if err := db.Model(&entity.CardAssignee{}).Where("card_id = ?", cid).Pluck("user_id", &aids).Error; err != nil {
+		log.Printf("fail to get assignees of card: %v", err)
+	}
+
+	if err := db.Model(&entity.Watch{}).
+		Joins("Join cards ON cards.id = ?", cid).
+		Joins("Join lists ON lists.id = cards.list_id").
+		Scopes(watchingMembers).
+		Where("(watches.target_type = ? AND watches.target_id = cards.id) OR (watches.target_type = ? AND watches.target_id = lists.id) OR (watches.target_type = ? AND watches.target_id = lists.board_id)",
+			entity.WatchTargetCard, entity.WatchTargetList, entity.WatchTargetBoard).
+		Pluck("watches.user_id", &wids).Error; err != nil {
+		log.Printf("fail to get watchers of card: %v", err)
+	}
+
+	return append(aids, wids...)
+}
+
+// cardsWatcherIDs returns ids of users who are notified of changes to cards, indexed by card id.
+// they are the same as cardWatcherIDs of each card, but are found in one query.
+func cardsWatcherIDs(db *gorm.DB, cids []uint) map[uint][]uint {
+	var rows []struct {
+		CardID uint
+		UserID uint
+	}
+
+	q := "SELECT card_id, user_id FROM card_assignees WHERE card_id IN (?) " +
+		"UNION ALL " +
+		"SELECT cards.id AS card_id, watches.user_id FROM watches " +
+		"Join cards ON cards.id IN (?) " +
+		"Join lists ON lists.id = cards.list_id " +
+		"Join board_members ON board_members.board_id = lists.board_id AND board_members.user_id = watches.user_id " +
+		"WHERE (watches.target_type = ? AND watches.target_id = cards.id) OR (watches.target_type = ? AND watches.target_id = lists.id) OR (watches.target_type = ? AND watches.target_id = lists.board_id)"
+
+	if err := db.Raw(q, cids, cids, entity.WatchTargetCard, entity.WatchTargetList, entity.WatchTargetBoard).Scan(&rows).Error; err != nil {
+		log.Printf("fail to get watchers of cards: %v", err)
+	}
+
+	uids := map[uint][]uint{}
+
+	for _, r := range rows {
+		uids[r.CardID] = append(uids[r.CardID], r.UserID)
+	}
+
+	return uids
+}
+
+// listWatcherIDs returns ids of members who watch a list or its board.
+func listWatcherIDs(db *gorm.DB, lid uint) []uint {
+	var uids []uint
+
+	if err := db.Model(&entity.Watch{}).
+		Joins("Join lists ON lists.id = ?", lid).
+		Scopes(watchingMembers).
+		Where("(watches.target_type = ? AND watches.target_id = lists.id) OR (watches.target_type = ? AND watches.target_id = lists.board_id)",
+			entity.WatchTargetList, entity.WatchTargetBoard).
+		Pluck("watches.user_id", &uids).Error; err != nil {
+		log.Printf("fail to get watchers of list: %v", err)
+	}
+
+	return uids
+}
+
+// boardWatcherIDs returns ids of members who watch a board.
+func boardWatcherIDs(db *gorm.DB, bid uint) []uint {
+	var uids []uint
+
+	if err := db.Model(&entity.Watch{}).
+		Joins("Join board_members ON board_members.board_id = watches.target_id AND board_members.user_id = watches.user_id").
+		Where("watches.target_type = ? AND watches.target_id = ?", entity.WatchTargetBoard, bid).
+		Pluck("watches.user_id", &uids).Error; err != nil {
+		log.Printf("fail to get watchers of board: %v", err)
+	}
+
+	return uids
+}
+
+// WatchRepository ...
+type WatchRepository struct {
+	db *gorm.DB
+}
+
+// NewWatchRepository is constructor for WatchRepository.
+func NewWatchRepository(db *gorm.DB) *WatchRepository {
+	return &WatchRepository{
+		db: db,
+	}
+}
+
+// ValidateUID validates whether the login user is a member of a board that has the board, the list or the card to watch.
+func (r *WatchRepository) ValidateUID(targetType string, id, uid uint) []validator.ValidationError {
+	var b entity.Board
+
+	db := r.db.Select("boards.id").Scopes(memberOf(uid, viewableRoles))
+
+	switch targetType {
+	case entity.WatchTargetBoard:
+		db = db.Where("boards.id = ?", id)
+	case entity.WatchTargetList:
+		db = db.Joins("Join lists ON boards.id = lists.board_id").
+			Where("lists.id = ? AND lists.deleted_at IS NULL", id)
+	case entity.WatchTargetCard:
+		db = db.Joins("Join lists ON boards.id = lists.board_id").
+			Joins("Join cards ON lists.id = cards.list_id").
+			Where("cards.id = ? AND cards.deleted_at IS NULL", id)
+	default:
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	if db.First(&b).RecordNotFound() {
+		return validator.NewValidationErrors(ErrorInvalidSession)
+	}
+
+	return nil
+}
+
+// GetAll returns watches of the login user.
+func (r *WatchRepository) GetAll(uid uint) *[]entity.Watch {
+	var ws []entity.Watch
+
+	r.db.Where("user_id = ?", uid).Order("created_at desc").Find(&ws)
+
+	return &ws
+}
+
+// Create insert a new record to a watches table.
+func (r *WatchRepository) Create(targetType string, id, uid uint) (*entity.Watch, []validator.ValidationError) {
+	w := &entity.Watch{
+		UserID:     uid,
+		TargetType: targetType,
+		TargetID:   id,
+	}
+
+	if err := r.db.Create(w).Error; err != nil {
+		return w, validator.FormattedMySQLError(err)
+	}
+
+	return w, nil
+}
+
+// Delete delete a record from a watches table.
+func (r *WatchRepository) Delete(targetType string, id, uid uint) []validator.ValidationError {
+	if err := deletionErrors(r.db.Where("user_id = ? AND target_type = ? AND target_id = ?", uid, targetType, id).Delete(&entity.Watch{}), "watch"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NotifyWatchers notifies watchers of a card that the login user changed or deleted it.
+func (r *CardRepository) NotifyWatchers(c *entity.Card, kind string, uid uint) {
+	notifyCard(r.db, kind, c.ID, uid, cardWatcherIDs(r.db, c.ID))
+}
+
+// NotifyWatchers notifies watchers of a card that the login user added a check list to it.
+func (r *CheckListRepository) NotifyWatchers(cl *entity.CheckList, uid uint) {
+	notifyCard(r.db, entity.NotificationCheckListAdded, cl.CardID, uid, cardWatcherIDs(r.db, cl.CardID))
+}
+
+// NotifyWatchers notifies watchers of a card that the login user attached a file to it.
+func (r *FileRepository) NotifyWatchers(f *entity.File, uid uint) {
+	notifyCard(r.db, entity.NotificationFileAdded, f.CardID, uid, cardWatcherIDs(r.db, f.CardID))
+}
+
+// NotifyWatchers notifies watchers of a list that the login user changed or deleted it.
+func (r *ListRepository) NotifyWatchers(l *entity.List, kind string, uid uint) {
+	notifyList(r.db, kind, l, uid)
+}
+
+// NotifyWatchers notifies watchers of a board that the login user changed or deleted it.
+func (r *BoardRepository) NotifyWatchers(bid uint, kind string, uid uint) {
+	notifyBoard(r.db, kind, bid, uid)
+}
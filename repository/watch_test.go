@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestShouldValidateUIDOfCardToWatch(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewWatchRepository(db)
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT boards.id FROM 'boards'
+		Join lists ON boards.id = lists.board_id
+		Join cards ON lists.id = cards.list_id
+		WHERE 'boards'.'deleted_at' IS NULL AND ((boards.id IN (SELECT board_id FROM board_members WHERE user_id = ? AND role IN (?,?,?)))
+		AND (cards.id = ? AND cards.deleted_at IS NULL))`)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(uint(1), "owner", "editor", "viewer", uint(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uint(2)))
+
+	if err := r.ValidateUID(entity.WatchTargetCard, uint(4), uint(1)); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldNotValidateUIDOfUnknownTarget(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewWatchRepository(db)
+
+	err := r.ValidateUID("label", uint(4), uint(1))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidRequest), err)
+}
+
+func TestShouldNotifyMembersWhoWatchListOrItsBoard(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewListRepository(db)
+
+	l := &entity.List{ID: uint(3), Name: "list", BoardID: uint(2)}
+
+	query := utils.ReplaceQuotationForQuery(`
+		SELECT watches.user_id FROM 'watches'
+		Join lists ON lists.id = ?
+		Join board_members ON board_members.board_id = lists.board_id AND board_members.user_id = watches.user_id
+		WHERE ((watches.target_type = ? AND watches.target_id = lists.id) OR (watches.target_type = ? AND watches.target_id = lists.board_id))`)
+
+	// a member who watches both the list and the board is notified once.
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(l.ID, "list", "board").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(uint(1)).AddRow(uint(5)).AddRow(uint(5)))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `notifications` (`created_at`,`user_id`,`actor_id`,`type`,`board_id`,`list_id`,`card_id`,`title`,`read_at`) VALUES (?,?,?,?,?,?,?,?,?)")).
+		WithArgs(utils.AnyTime{}, uint(5), uint(1), "list_updated", l.BoardID, l.ID, nil, l.Name, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	r.NotifyWatchers(l, entity.NotificationListUpdated, uint(1))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldNotDeleteWatchThatDoesNotExist(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewWatchRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `watches` WHERE (user_id = ? AND target_type = ? AND target_id = ?)")).
+		WithArgs(uint(1), "list", uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	err := r.Delete(entity.WatchTargetList, uint(3), uint(1))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorRecordNotFound), err)
+}
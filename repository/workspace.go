@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"local.packages/entity"
+	"local.packages/validator"
+)
+
+// workspaceMemberOf returns a scope that narrows workspaces down to those the user is a member of with one of roles.
+// a query must refer to workspaces table.
+func workspaceMemberOf(uid uint, roles []string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("workspaces.id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ? AND role IN (?))", uid, roles)
+	}
+}
+
+// joinWorkspaceBoards adds a user to members of every board of a workspace with a role.
+// boards the user is already a member of are left as they are.
+func joinWorkspaceBoards(tx *gorm.DB, wid, uid uint, role string) error {
+	return tx.Exec(
+		"INSERT IGNORE INTO board_members (board_id, user_id, role, created_at) SELECT id, ?, ?, ? FROM boards WHERE workspace_id = ? AND deleted_at IS NULL",
+		uid, role, time.Now(), wid).Error
+}
+
+// WorkspaceRepository ...
+type WorkspaceRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkspaceRepository is constructor for WorkspaceRepository.
+func NewWorkspaceRepository(db *gorm.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{
+		db: db,
+	}
+}
+
+// ValidateUID validates whether the login user is an owner of a workspace.
+func (r *WorkspaceRepository) ValidateUID(wid, uid uint) []validator.ValidationError {
+	var w entity.Workspace
+
+	if r.db.Select("id").Scopes(workspaceMemberOf(uid, []string{entity.WorkspaceRoleOwner})).First(&w, wid).RecordNotFound() {
+		return validator.NewValidationErrors(ErrorInvalidSession)
+	}
+
+	return nil
+}
+
+// Find returns a record of Workspace that the login user is a member of.
+func (r *WorkspaceRepository) Find(id, uid uint) (*entity.Workspace, []validator.ValidationError) {
+	var w entity.Workspace
+
+	if r.db.Scopes(workspaceMemberOf(uid, []string{entity.WorkspaceRoleOwner, entity.WorkspaceRoleMember})).First(&w, id).RecordNotFound() {
+		return &w, validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return &w, nil
+}
+
+// GetAll returns slice of Workspace's record that the login user is a member of.
+func (r *WorkspaceRepository) GetAll(uid uint) *[]entity.Workspace {
+	var ws []entity.Workspace
+
+	r.db.Scopes(workspaceMemberOf(uid, []string{entity.WorkspaceRoleOwner, entity.WorkspaceRoleMember})).
+		Order("workspaces.id").
+		Find(&ws)
+
+	return &ws
+}
+
+// Create insert a new record to a workspaces table.
+// the login user becomes an owner of the workspace.
+func (r *WorkspaceRepository) Create(name, defaultRole string, uid uint) (*entity.Workspace, []validator.ValidationError) {
+	w := &entity.Workspace{
+		Name:        name,
+		DefaultRole: defaultRole,
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(w).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&entity.WorkspaceMember{WorkspaceID: w.ID, UserID: uid, Role: entity.WorkspaceRoleOwner}).Error
+	})
+
+	if err != nil {
+		if reflect.TypeOf(err).String() == "*mysql.MySQLError" {
+			return w, validator.FormattedMySQLError(err)
+		}
+		return w, validator.FormattedValidationError(err)
+	}
+
+	return w, nil
+}
+
+// Update update a name and a default role of a workspace.
+// the default role applies to members who join boards of the workspace afterwards.
+func (r *WorkspaceRepository) Update(w *entity.Workspace, name, defaultRole string) []validator.ValidationError {
+	if err := r.db.Model(w).Updates(map[string]interface{}{"name": name, "default_role": defaultRole}).Error; err != nil {
+		return validator.FormattedValidationError(err)
+	}
+
+	return nil
+}
+
+// Delete delete a workspace and its members.
+// boards of the workspace are not deleted, and their members can still access them.
+func (r *WorkspaceRepository) Delete(id uint) []validator.ValidationError {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return deleteWorkspace(tx, id)
+	})
+
+	if err != nil {
+		log.Printf("fail to delete workspace: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return nil
+}
+
+// deleteWorkspace deletes a workspace and its members, and detaches its boards from it.
+func deleteWorkspace(tx *gorm.DB, id uint) error {
+	if err := tx.Model(&entity.Board{}).Unscoped().Where("workspace_id = ?", id).UpdateColumn("workspace_id", nil).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("workspace_id = ?", id).Delete(&entity.WorkspaceMember{}).Error; err != nil {
+		return err
+	}
+
+	if rslt := tx.Where("id = ?", id).Delete(&entity.Workspace{}); rslt.Error != nil || rslt.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// FindMember returns a record of WorkspaceMember that found by workspace id and user id.
+func (r *WorkspaceRepository) FindMember(wid, uid uint) (*entity.WorkspaceMember, []validator.ValidationError) {
+	var m entity.WorkspaceMember
+
+	if r.db.Where("workspace_id = ? AND user_id = ?", wid, uid).First(&m).RecordNotFound() {
+		return &m, validator.NewValidationErrors(ErrorRecordNotFound)
+	}
+
+	return &m, nil
+}
+
+// GetMembers returns slice of WorkspaceMember's record with their users.
+// returns nothing unless the login user is a member of the workspace.
+func (r *WorkspaceRepository) GetMembers(wid, uid uint) *[]entity.WorkspaceMember {
+	var ms []entity.WorkspaceMember
+
+	r.db.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id, name, email")
+	}).
+		Joins("Join workspaces ON workspaces.id = workspace_members.workspace_id").
+		Scopes(workspaceMemberOf(uid, []string{entity.WorkspaceRoleOwner, entity.WorkspaceRoleMember})).
+		Where("workspace_members.workspace_id = ?", wid).
+		Order("workspace_members.created_at").
+		Find(&ms)
+
+	return &ms
+}
+
+// CreateMember insert a new record to a workspace_members table for the user who has an email address.
+// the user also joins every board of the workspace with the default role of the workspace.
+func (r *WorkspaceRepository) CreateMember(w *entity.Workspace, email, role string) (*entity.WorkspaceMember, []validator.ValidationError) {
+	u := &entity.User{}
+
+	if r.db.Select("id, name, email").Where("email = ?", email).First(u).RecordNotFound() {
+		return nil, validator.NewValidationErrors(ErrorUserDoesNotExist)
+	}
+
+	m := &entity.WorkspaceMember{
+		WorkspaceID: w.ID,
+		UserID:      u.ID,
+		Role:        role,
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+
+		return joinWorkspaceBoards(tx, w.ID, u.ID, w.DefaultRole)
+	})
+
+	if err != nil {
+		if reflect.TypeOf(err).String() == "*mysql.MySQLError" {
+			return m, validator.FormattedMySQLError(err)
+		}
+		return m, validator.FormattedValidationError(err)
+	}
+
+	m.User = u
+
+	return m, nil
+}
+
+// DeleteMember delete a record from a workspace_members table.
+// the user also leaves boards of the workspace except for boards the user owns.
+// a workspace must have at least one owner, so that the last owner can not be removed.
+func (r *WorkspaceRepository) DeleteMember(m *entity.WorkspaceMember) []validator.ValidationError {
+	var verr []validator.ValidationError
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var uids []uint
+
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").
+			Model(&entity.WorkspaceMember{}).
+			Where("workspace_id = ? AND role = ?", m.WorkspaceID, entity.WorkspaceRoleOwner).
+			Pluck("user_id", &uids).
+			Error; err != nil {
+			return err
+		}
+
+		if len(uids) == 1 && uids[0] == m.UserID {
+			verr = validator.NewValidationErrors(ErrorLastWorkspaceOwner)
+			return gorm.ErrRecordNotFound
+		}
+
+		if rslt := tx.Where("workspace_id = ? AND user_id = ?", m.WorkspaceID, m.UserID).Delete(&entity.WorkspaceMember{}); rslt.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("user_id = ? AND role <> ? AND board_id IN (SELECT id FROM boards WHERE workspace_id = ?)", m.UserID, entity.BoardRoleOwner, m.WorkspaceID).
+			Delete(&entity.BoardMember{}).
+			Error; err != nil {
+			return err
+		}
+
+		return unassignNonMemberCards(tx, m.UserID)
+	})
+
+	if verr != nil {
+		return verr
+	}
+
+	if err != nil {
+		log.Printf("fail to delete workspace member: %v", err)
+		return validator.NewValidationErrors(ErrorInvalidRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"local.packages/entity"
+	"local.packages/utils"
+	"local.packages/validator"
+)
+
+func TestShouldCreateWorkspaceWithOwner(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewWorkspaceRepository(db)
+
+	userID := uint(1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `workspaces` (`created_at`,`updated_at`,`name`,`default_role`) VALUES (?,?,?,?)")).
+		WithArgs(utils.AnyTime{}, utils.AnyTime{}, "team", "editor").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `workspace_members` (`workspace_id`,`user_id`,`role`,`created_at`) VALUES (?,?,?,?)")).
+		WithArgs(uint(3), userID, "owner", utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	w, err := r.Create("team", entity.BoardRoleEditor, userID)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, uint(3), w.ID)
+}
+
+func TestShouldNotCreateWorkspaceWithOwnerDefaultRole(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewWorkspaceRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	_, err := r.Create("team", entity.BoardRoleOwner, uint(1))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.NotNil(t, err)
+}
+
+func TestShouldCreateWorkspaceMemberAndJoinBoardsOfWorkspace(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewWorkspaceRepository(db)
+
+	w := &entity.Workspace{ID: uint(3), Name: "team", DefaultRole: entity.BoardRoleViewer}
+	userID := uint(4)
+	email := "gopher@sample.com"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email FROM `users` WHERE (email = ?)")).
+		WithArgs(email).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).AddRow(userID, "gopher", email))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `workspace_members`")).
+		WithArgs(w.ID, userID, "member", utils.AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO board_members (board_id, user_id, role, created_at) SELECT id, ?, ?, ? FROM boards WHERE workspace_id = ? AND deleted_at IS NULL")).
+		WithArgs(userID, "viewer", utils.AnyTime{}, w.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectCommit()
+
+	m, err := r.CreateMember(w, email, entity.WorkspaceRoleMember)
+
+	if err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, "gopher", m.User.Name)
+}
+
+func TestShouldDeleteWorkspaceMemberAndLeaveBoardsExceptOwnBoards(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewWorkspaceRepository(db)
+
+	m := &entity.WorkspaceMember{WorkspaceID: uint(3), UserID: uint(4), Role: entity.WorkspaceRoleMember}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM `workspace_members` WHERE (workspace_id = ? AND role = ?) FOR UPDATE")).
+		WithArgs(m.WorkspaceID, "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(uint(1)))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `workspace_members` WHERE (workspace_id = ? AND user_id = ?)")).
+		WithArgs(m.WorkspaceID, m.UserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `board_members` WHERE (user_id = ? AND role <> ? AND board_id IN (SELECT id FROM boards WHERE workspace_id = ?))")).
+		WithArgs(m.UserID, "owner", m.WorkspaceID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `card_assignees`")).
+		WithArgs(m.UserID, m.UserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.DeleteMember(m); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestShouldNotDeleteLastOwnerOfWorkspace(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewWorkspaceRepository(db)
+
+	m := &entity.WorkspaceMember{WorkspaceID: uint(3), UserID: uint(1), Role: entity.WorkspaceRoleOwner}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM `workspace_members`")).
+		WithArgs(m.WorkspaceID, "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(m.UserID))
+
+	mock.ExpectRollback()
+
+	err := r.DeleteMember(m)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorLastWorkspaceOwner), err)
+}
+
+func TestShouldNotDeleteWorkspaceMemberWhenOwnersCanNotBeFound(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewWorkspaceRepository(db)
+
+	m := &entity.WorkspaceMember{WorkspaceID: uint(3), UserID: uint(1), Role: entity.WorkspaceRoleOwner}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM `workspace_members`")).
+		WithArgs(m.WorkspaceID, "owner").
+		WillReturnError(errors.New("some error"))
+
+	mock.ExpectRollback()
+
+	err := r.DeleteMember(m)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+
+	assert.Equal(t, validator.NewValidationErrors(ErrorInvalidRequest), err)
+}
+
+func TestShouldDeleteWorkspaceAndKeepItsBoards(t *testing.T) {
+	db, mock := utils.NewDBMock(t)
+	defer db.Close()
+
+	r := NewWorkspaceRepository(db)
+
+	workspaceID := uint(3)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `boards` SET `workspace_id` = ? WHERE (workspace_id = ?)")).
+		WithArgs(nil, workspaceID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `workspace_members` WHERE (workspace_id = ?)")).
+		WithArgs(workspaceID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `workspaces` WHERE (id = ?)")).
+		WithArgs(workspaceID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	if err := r.Delete(workspaceID); err != nil {
+		t.Errorf("was not expected an error. %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// StubIdP is a local OpenID Provider for testing the authorization code flow.
+// it signs ID tokens by RS256 and checks client credentials and PKCE at the token endpoint.
+type StubIdP struct {
+	ClientID     string
+	ClientSecret string
+	server       *httptest.Server
+	key          *rsa.PrivateKey
+	mu           sync.Mutex
+	grants       map[string]stubGrant
+}
+
+type stubGrant struct {
+	challenge string
+	claims    map[string]interface{}
+}
+
+// NewStubIdP starts a StubIdP for a client.
+func NewStubIdP(clientID, clientSecret string) (*StubIdP, error) {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StubIdP{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          k,
+		grants:       map[string]stubGrant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+
+	s.server = httptest.NewServer(mux)
+
+	return s, nil
+}
+
+// Issuer returns an issuer identifier of the provider.
+func (s *StubIdP) Issuer() string {
+	return s.server.URL
+}
+
+// Close shuts down the provider.
+func (s *StubIdP) Close() {
+	s.server.Close()
+}
+
+// Authorize simulates that a user has been authenticated at an authorization URL.
+// returns an authorization code that is exchanged for an ID token of the user.
+func (s *StubIdP) Authorize(authURL, subject, email string, emailVerified bool, name string) (string, error) {
+	u, err := url.Parse(authURL)
+
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+
+	if q.Get("client_id") != s.ClientID || q.Get("code_challenge_method") != "S256" {
+		return "", errors.New("stub idp: invalid authorization request")
+	}
+
+	code := fmt.Sprintf("code-%s-%d", subject, time.Now().UnixNano())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.grants[code] = stubGrant{
+		challenge: q.Get("code_challenge"),
+		claims: map[string]interface{}{
+			"iss":            s.Issuer(),
+			"sub":            subject,
+			"aud":            s.ClientID,
+			"exp":            time.Now().Add(time.Minute * 5).Unix(),
+			"iat":            time.Now().Unix(),
+			"nonce":          q.Get("nonce"),
+			"email":          email,
+			"email_verified": emailVerified,
+			"name":           name,
+		},
+	}
+
+	return code, nil
+}
+
+// SignIDToken returns an ID token that contains the claims and is signed by the provider.
+func (s *StubIdP) SignIDToken(claims map[string]interface{}) (string, error) {
+	h, err := json.Marshal(map[string]string{"alg": "RS256", "kid": "stub"})
+
+	if err != nil {
+		return "", err
+	}
+
+	c, err := json.Marshal(claims)
+
+	if err != nil {
+		return "", err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	d := sha256.Sum256([]byte(signed))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, d[:])
+
+	if err != nil {
+		return "", err
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func (s *StubIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.Issuer(),
+		"authorization_endpoint": s.Issuer() + "/authorize",
+		"token_endpoint":         s.Issuer() + "/token",
+		"jwks_uri":               s.Issuer() + "/jwks",
+	})
+}
+
+func (s *StubIdP) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+
+	if !ok || id != s.ClientID || secret != s.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	s.mu.Lock()
+	g, ok := s.grants[r.PostForm.Get("code")]
+	delete(s.grants, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	d := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+
+	if !ok || base64.RawURLEncoding.EncodeToString(d[:]) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	t, err := s.SignIDToken(g.claims)
+
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": t, "token_type": "Bearer"})
+}
+
+func (s *StubIdP) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "stub",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
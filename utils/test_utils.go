@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/hex"
 	"net/http"
 	"regexp"
 	"strings"
@@ -60,9 +62,17 @@ func SetUpAuthentication(r *gin.Engine, req *http.Request, mock sqlmock.Sqlmock,
 
 	expire := time.Now().Add(time.Hour * 1)
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `users`")).
-		WithArgs(token).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "expires_at"}).AddRow(uint(1), expire))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sessions`")).
+		WithArgs(DigestToken(token)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "last_seen_at", "expires_at"}).
+				AddRow(uint(1), uint(1), time.Now(), expire))
+}
+
+// DigestToken returns a SHA-256 digest of a token that is stored in database instead of the token.
+func DigestToken(token string) string {
+	d := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(d[:])
 }
 
 // ReplaceQuotationForQuery replace the single quotation with the back quotation.
@@ -19,6 +19,16 @@ func ErrorHexcolor(field string) string {
 	return fmt.Sprintf("%sは16進数で入力してください", field)
 }
 
+// ErrorEmail returns error text that the field must be an email address.
+func ErrorEmail(field string) string {
+	return fmt.Sprintf("%sの形式が正しくありません", field)
+}
+
+// ErrorURL returns error text that the field must be an URL.
+func ErrorURL(field string) string {
+	return fmt.Sprintf("%sはURLの形式で入力してください", field)
+}
+
 // ErrorTooLong returns error text that the field is less than a param.
 func ErrorTooLong(field, param string) string {
 	return fmt.Sprintf("%sは%s文字以下で入力してください", field, param)
@@ -33,3 +43,8 @@ func ErrorTooShort(field, param string) string {
 func ErrorEqualField(field, param string) string {
 	return fmt.Sprintf("%sと%sの値は一致する必要があります", field, param)
 }
+
+// ErrorOneOf returns error text that the field must be one of params.
+func ErrorOneOf(field, param string) string {
+	return fmt.Sprintf("%sは%sのいずれかを指定してください", field, param)
+}
@@ -74,6 +74,12 @@ func FormattedValidationError(err error) []ValidationError {
 		case "required":
 			t := ErrorRequired(f)
 			validationErrors = append(validationErrors, ValidationError{t})
+		case "email":
+			t := ErrorEmail(f)
+			validationErrors = append(validationErrors, ValidationError{t})
+		case "url":
+			t := ErrorURL(f)
+			validationErrors = append(validationErrors, ValidationError{t})
 		case "hexcolor":
 			t := ErrorHexcolor(f)
 			validationErrors = append(validationErrors, ValidationError{t})
@@ -86,6 +92,9 @@ func FormattedValidationError(err error) []ValidationError {
 		case "eqfield":
 			t := ErrorEqualField(f, p)
 			validationErrors = append(validationErrors, ValidationError{t})
+		case "oneof":
+			t := ErrorOneOf(f, strings.ReplaceAll(p, " ", ", "))
+			validationErrors = append(validationErrors, ValidationError{t})
 		}
 	}
 